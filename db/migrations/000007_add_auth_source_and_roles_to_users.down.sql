@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN auth_source,
+    DROP COLUMN roles;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN auth_source TEXT NOT NULL DEFAULT 'local',
+    ADD COLUMN roles TEXT[] NOT NULL DEFAULT '{}';
\ No newline at end of file
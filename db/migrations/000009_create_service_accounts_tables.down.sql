@@ -0,0 +1,4 @@
+DROP TABLE used_client_assertions;
+DROP TABLE service_account_keys;
+DROP TABLE service_account_secrets;
+DROP TABLE service_accounts;
\ No newline at end of file
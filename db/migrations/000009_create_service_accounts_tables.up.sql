@@ -0,0 +1,37 @@
+CREATE TABLE service_accounts (
+    id BIGSERIAL PRIMARY KEY,
+    client_id TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    organization TEXT NOT NULL,
+    owner_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON service_accounts (owner_user_id);
+
+CREATE TABLE service_account_secrets (
+    id BIGSERIAL PRIMARY KEY,
+    service_account_id BIGINT NOT NULL REFERENCES service_accounts(id) ON DELETE CASCADE,
+    secret_hash TEXT UNIQUE NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON service_account_secrets (service_account_id);
+
+CREATE TABLE service_account_keys (
+    id BIGSERIAL PRIMARY KEY,
+    service_account_id BIGINT NOT NULL REFERENCES service_accounts(id) ON DELETE CASCADE,
+    key_id TEXT NOT NULL,
+    public_key_pem TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (service_account_id, key_id)
+);
+
+CREATE TABLE used_client_assertions (
+    service_account_id BIGINT NOT NULL REFERENCES service_accounts(id) ON DELETE CASCADE,
+    jti TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (service_account_id, jti)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE device_authorizations;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE device_authorizations (
+    id BIGSERIAL PRIMARY KEY,
+    device_code_hash TEXT UNIQUE NOT NULL,
+    user_code_hash TEXT UNIQUE NOT NULL,
+    client_id TEXT NOT NULL,
+    scope TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    poll_interval INT NOT NULL,
+    last_polled_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
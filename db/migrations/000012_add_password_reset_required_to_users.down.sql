@@ -0,0 +1,2 @@
+ALTER TABLE users
+    DROP COLUMN password_reset_required;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN password_changed_at;
+
+DROP TABLE password_histories;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE password_histories (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON password_histories (user_id, created_at);
+
+ALTER TABLE users
+    ADD COLUMN password_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE users
+    DROP COLUMN last_login_at,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_at,
+    DROP COLUMN private_metadata,
+    DROP COLUMN public_metadata,
+    DROP COLUMN avatar_url,
+    DROP COLUMN timezone,
+    DROP COLUMN locale;
\ No newline at end of file
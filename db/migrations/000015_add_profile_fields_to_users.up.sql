@@ -0,0 +1,9 @@
+ALTER TABLE users
+    ADD COLUMN locale TEXT NOT NULL DEFAULT '',
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT '',
+    ADD COLUMN avatar_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN public_metadata JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN private_metadata JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN last_login_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN purge_at,
+    DROP COLUMN deleted_at;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN purge_at TIMESTAMPTZ;
+
+CREATE INDEX ON users (purge_at) WHERE purge_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP COLUMN status_changed_at,
+    DROP COLUMN suspended_until,
+    DROP COLUMN status_note,
+    DROP COLUMN status_reason,
+    DROP COLUMN status;
\ No newline at end of file
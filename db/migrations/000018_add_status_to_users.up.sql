@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'disabled', 'suspended')),
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN status_note TEXT NOT NULL DEFAULT '',
+    ADD COLUMN suspended_until TIMESTAMPTZ,
+    ADD COLUMN status_changed_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE impersonations;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE sessions
+    DROP COLUMN auth_time,
+    DROP COLUMN amr;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE sessions
+    ADD COLUMN auth_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN amr TEXT[] NOT NULL DEFAULT '{}';
+
+-- Sessions started before the column existed were authenticated when they were created
+UPDATE sessions SET auth_time = created_at;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE known_devices (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id_hash TEXT NOT NULL,
+    user_agent_family TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    location TEXT NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, device_id_hash, user_agent_family)
+);
\ No newline at end of file
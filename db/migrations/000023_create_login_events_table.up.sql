@@ -0,0 +1,15 @@
+CREATE TABLE login_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address TEXT NOT NULL,
+    country_code TEXT NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    risk_score INTEGER NOT NULL,
+    risk_reasons TEXT[] NOT NULL DEFAULT '{}',
+    decision TEXT NOT NULL CHECK (decision IN ('allow', 'require_mfa', 'block')),
+    succeeded BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON login_events (user_id, created_at);
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE mfa_challenges (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    login_event_id BIGINT REFERENCES login_events(id) ON DELETE SET NULL,
+    code_hash TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    remember_me BOOLEAN NOT NULL DEFAULT FALSE,
+    use_session BOOLEAN NOT NULL DEFAULT FALSE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON mfa_challenges (user_id);
\ No newline at end of file
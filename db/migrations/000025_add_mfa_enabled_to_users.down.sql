@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN mfa_enabled;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN mfa_enabled BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE trusted_devices;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE trusted_devices (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id_hash TEXT NOT NULL,
+    user_agent_family TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX ON trusted_devices (user_id);
\ No newline at end of file
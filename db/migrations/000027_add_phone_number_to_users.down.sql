@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN phone_number,
+    DROP COLUMN mfa_channel;
\ No newline at end of file
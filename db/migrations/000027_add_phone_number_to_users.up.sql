@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN phone_number TEXT NOT NULL DEFAULT '',
+    ADD COLUMN mfa_channel TEXT NOT NULL DEFAULT 'email';
\ No newline at end of file
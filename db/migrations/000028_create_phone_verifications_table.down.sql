@@ -0,0 +1 @@
+DROP TABLE phone_verifications;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE phone_code_sends;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE phone_code_sends (
+    id BIGSERIAL PRIMARY KEY,
+    phone_number TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON phone_code_sends (phone_number, created_at);
\ No newline at end of file
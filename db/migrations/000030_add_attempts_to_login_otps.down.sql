@@ -0,0 +1 @@
+ALTER TABLE login_otps DROP COLUMN attempts;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE login_otps ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE remember_tokens DROP COLUMN client_id;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE remember_tokens ADD COLUMN client_id TEXT NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE used_secure_account_tokens;
\ No newline at end of file
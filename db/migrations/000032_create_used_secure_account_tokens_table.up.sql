@@ -0,0 +1,5 @@
+CREATE TABLE used_secure_account_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sessions DROP COLUMN previous_token_hash, DROP COLUMN rotated_at;
\ No newline at end of file
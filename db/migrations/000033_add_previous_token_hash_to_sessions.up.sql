@@ -0,0 +1,3 @@
+ALTER TABLE sessions ADD COLUMN previous_token_hash TEXT, ADD COLUMN rotated_at TIMESTAMPTZ;
+
+CREATE INDEX ON sessions (previous_token_hash);
\ No newline at end of file
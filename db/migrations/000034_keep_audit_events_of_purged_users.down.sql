@@ -0,0 +1,6 @@
+DELETE FROM audit_events WHERE user_id IS NULL;
+
+ALTER TABLE audit_events DROP CONSTRAINT audit_events_user_id_fkey;
+ALTER TABLE audit_events ADD CONSTRAINT audit_events_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE audit_events ALTER COLUMN user_id SET NOT NULL;
\ No newline at end of file
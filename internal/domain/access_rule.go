@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"net"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// AccessRule restricts which users the forward auth endpoint lets through to a host and path
+type AccessRule struct {
+	// Host is an exact host name or a wildcard such as *.example.com
+	Host string
+	// PathPrefix limits the rule to part of the host, matched on path segments so /metrics doesn't cover /metricsfoo.
+	// It is matched case insensitively against the cleaned path. An empty prefix matches every path.
+	PathPrefix string
+	// Roles lists the roles allowed through, a user needs at least one of them. An empty list allows any authenticated user.
+	Roles []string
+}
+
+// Matches reports whether the rule applies to the request host and raw path.
+// A path that can't be decoded matches every rule, so it is never let through by a more permissive one.
+func (r AccessRule) Matches(host string, rawPath string) bool {
+	host = strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(host, ".")
+
+	pattern := strings.ToLower(r.Host)
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		if !strings.HasSuffix(host, "."+suffix) {
+			return false
+		}
+	} else if pattern != host {
+		return false
+	}
+
+	cleaned, ok := CleanPath(rawPath)
+	if !ok {
+		return true
+	}
+
+	cleaned = strings.ToLower(cleaned)
+	prefix := strings.ToLower(strings.TrimSuffix(r.PathPrefix, "/"))
+	return prefix == "" || cleaned == prefix || strings.HasPrefix(cleaned, prefix+"/")
+}
+
+// CleanPath decodes the raw path of a request and resolves its dot segments and repeated slashes,
+// the way applications behind the proxy see it. It returns false when the path can't be decoded.
+func CleanPath(rawPath string) (string, bool) {
+	decoded, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return "", false
+	}
+
+	return path.Clean("/" + decoded), true
+}
+
+// Allows reports whether a user with the given roles may pass the rule
+func (r AccessRule) Allows(roles []string) bool {
+	if len(r.Roles) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(r.Roles, func(role string) bool { return slices.Contains(roles, role) })
+}
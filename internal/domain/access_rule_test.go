@@ -0,0 +1,68 @@
+package domain
+
+import "testing"
+
+func TestAccessRuleMatches(t *testing.T) {
+	adminRule := AccessRule{Host: "app.example.com", PathPrefix: "/admin/"}
+
+	tests := []struct {
+		name string
+		rule AccessRule
+		host string
+		path string
+		want bool
+	}{
+		{name: "exact prefix", rule: adminRule, host: "app.example.com", path: "/admin", want: true},
+		{name: "below prefix", rule: adminRule, host: "app.example.com", path: "/admin/users", want: true},
+		{name: "other path", rule: adminRule, host: "app.example.com", path: "/public", want: false},
+		{name: "segment boundary", rule: adminRule, host: "app.example.com", path: "/administrator", want: false},
+		{name: "other host", rule: adminRule, host: "api.example.com", path: "/admin", want: false},
+		{name: "host with port", rule: adminRule, host: "app.example.com:8443", path: "/admin", want: true},
+		{name: "host case", rule: adminRule, host: "APP.example.com", path: "/admin", want: true},
+		{name: "fully qualified host", rule: adminRule, host: "app.example.com.", path: "/admin", want: true},
+		{name: "dot segments into prefix", rule: adminRule, host: "app.example.com", path: "/public/../admin", want: true},
+		{name: "dot segments out of prefix", rule: adminRule, host: "app.example.com", path: "/admin/../public", want: false},
+		{name: "repeated slashes", rule: adminRule, host: "app.example.com", path: "//admin", want: true},
+		{name: "percent encoded", rule: adminRule, host: "app.example.com", path: "/%61dmin", want: true},
+		{name: "encoded slash", rule: adminRule, host: "app.example.com", path: "/public%2F..%2Fadmin", want: true},
+		{name: "path case", rule: adminRule, host: "app.example.com", path: "/Admin", want: true},
+		{name: "relative path", rule: adminRule, host: "app.example.com", path: "admin/users", want: true},
+		{name: "undecodable path", rule: adminRule, host: "app.example.com", path: "/public/%zz", want: true},
+		{name: "wildcard host", rule: AccessRule{Host: "*.internal.example.com"}, host: "metrics.internal.example.com", path: "/", want: true},
+		{name: "wildcard apex", rule: AccessRule{Host: "*.internal.example.com"}, host: "internal.example.com", path: "/", want: false},
+		{name: "empty prefix", rule: AccessRule{Host: "app.example.com"}, host: "app.example.com", path: "/anything", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.host, tt.path); got != tt.want {
+				t.Errorf("Matches(%q, %q) = %v, want %v", tt.host, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		rawPath string
+		want    string
+		wantOK  bool
+	}{
+		{rawPath: "", want: "/", wantOK: true},
+		{rawPath: "/a/./b/../c/", want: "/a/c", wantOK: true},
+		{rawPath: "/../../etc", want: "/etc", wantOK: true},
+		{rawPath: "/%2e%2e/admin", want: "/admin", wantOK: true},
+		{rawPath: "/a%20b", want: "/a b", wantOK: true},
+		{rawPath: "/%", want: "", wantOK: false},
+		{rawPath: "/%g1", want: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawPath, func(t *testing.T) {
+			got, ok := CleanPath(tt.rawPath)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("CleanPath(%q) = %q, %v, want %q, %v", tt.rawPath, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Audited actions
+const (
+	AuditActionImpersonationStarted = "impersonation.started"
+	AuditActionImpersonationRequest = "impersonation.request"
+	AuditActionImpersonationEnded   = "impersonation.ended"
+)
+
+// AuditEvent records an action taken on a user's account, by the user or by someone acting on their behalf
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// UserID is zero once the account was purged, its events are kept for the record
+	UserID int64 `json:"user_id"`
+	// ActorID is the user who took the action when it wasn't the account owner, e.g. an impersonating administrator
+	ActorID   *int64         `json:"actor_id,omitempty"`
+	Action    string         `json:"action" example:"impersonation.started"`
+	Details   map[string]any `json:"details"`
+	IPAddress string         `json:"ip_address"`
+	CreatedAt time.Time      `json:"created_at"`
+}
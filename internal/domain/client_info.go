@@ -0,0 +1,55 @@
+package domain
+
+import "strings"
+
+// ClientInfo describes the client a request comes from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+	// DeviceID is the long-lived identifier kept by the browser in the device cookie, or sent by apps
+	DeviceID string
+	// TrustedDeviceToken is kept by devices allowed to skip the second factor
+	TrustedDeviceToken string
+}
+
+// userAgentBrowsers maps user agent tokens to browser names. Order matters, e.g. Edge and Opera also claim to be Chrome.
+var userAgentBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"CriOS/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+}
+
+// userAgentSystems maps user agent tokens to operating system names, Android must be checked before Linux
+var userAgentSystems = []struct{ token, name string }{
+	{"Windows", "Windows"},
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// UserAgentFamily describes the browser and operating system of the client, e.g. "Firefox on Windows".
+// Versions are left out so a browser update doesn't make a known device look new.
+func (c ClientInfo) UserAgentFamily() string {
+	browser := "Unknown browser"
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(c.UserAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	for _, s := range userAgentSystems {
+		if strings.Contains(c.UserAgent, s.token) {
+			return browser + " on " + s.name
+		}
+	}
+
+	return browser
+}
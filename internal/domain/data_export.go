@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// Data export statuses
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+)
+
+// DataExport represents an archive of everything stored about a user, generated in the background.
+// Once ready it can be downloaded with the token emailed to the user until it expires.
+type DataExport struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Status      string     `json:"status" example:"ready"`
+	TokenHash   string     `json:"-"`
+	StorageKey  string     `json:"-"`
+	SizeBytes   int64      `json:"size_bytes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// IsInProgress reports whether the export is still being generated
+func (e *DataExport) IsInProgress() bool {
+	return e.Status == DataExportStatusPending || e.Status == DataExportStatusProcessing
+}
+
+// PersonalData is the content of a data export. Secrets such as password and token hashes are left out.
+type PersonalData struct {
+	ExportedAt           time.Time                   `json:"exported_at"`
+	User                 *User                       `json:"user"`
+	Identities           []PersonalDataIdentity      `json:"identities"`
+	Sessions             []PersonalDataSession       `json:"sessions"`
+	RememberTokens       []PersonalDataRememberToken `json:"remember_tokens"`
+	PersonalAccessTokens []PersonalAccessToken       `json:"personal_access_tokens"`
+	ServiceAccounts      []ServiceAccount            `json:"service_accounts"`
+	EmailChanges         []PersonalDataEmailChange   `json:"email_changes"`
+	PasswordChanges      []time.Time                 `json:"password_changes"`
+	AuditEvents          []AuditEvent                `json:"audit_events"`
+	KnownDevices         []KnownDevice               `json:"known_devices"`
+	LoginEvents          []LoginEvent                `json:"login_events"`
+	TrustedDevices       []TrustedDevice             `json:"trusted_devices"`
+	Consents             []PersonalDataConsent       `json:"consents"`
+}
+
+// PersonalDataIdentity describes a way the user signs in
+type PersonalDataIdentity struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+// PersonalDataSession describes a browser session of the user
+type PersonalDataSession struct {
+	ID         int64     `json:"id"`
+	Persistent bool      `json:"persistent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// PersonalDataRememberToken describes a remember me token of the user
+type PersonalDataRememberToken struct {
+	ID        int64     `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PersonalDataConsent describes the decision of the user on a client asking for access to their account.
+// Device authorizations are the only consents recorded, they are purged a day after they expire.
+type PersonalDataConsent struct {
+	ClientID  string    `json:"client_id"`
+	Scope     string    `json:"scope"`
+	Status    string    `json:"status" example:"approved"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PersonalDataEmailChange describes a requested change of the user's email address
+type PersonalDataEmailChange struct {
+	OldEmail    string     `json:"old_email"`
+	NewEmail    string     `json:"new_email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Device authorization statuses
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+)
+
+// DeviceAuthorization represents a pending OAuth 2.0 device authorization grant (RFC 8628).
+// Both codes are only stored hashed.
+type DeviceAuthorization struct {
+	ID             int64
+	DeviceCodeHash string
+	UserCodeHash   string
+	ClientID       string
+	Scope          string
+	Status         string
+	UserID         *int64
+	Interval       int
+	LastPolledAt   *time.Time
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+}
+
+// IsExpired reports whether the codes of the authorization can no longer be used
+func (a *DeviceAuthorization) IsExpired() bool {
+	return time.Now().After(a.ExpiresAt)
+}
+
+// NormalizeUserCode removes the separators and case differences users introduce when typing a user code
+func NormalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(userCode))
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// EmailChange represents a request to change a user's email address.
+// It is applied once confirmed from the new address and can be reverted from the old one until RevertExpiresAt.
+type EmailChange struct {
+	ID               int64
+	UserID           int64
+	OldEmail         string
+	NewEmail         string
+	ConfirmTokenHash string
+	RevertTokenHash  string
+	ExpiresAt        time.Time
+	RevertExpiresAt  time.Time
+	ConfirmedAt      *time.Time
+	CreatedAt        time.Time
+}
+
+// IsConfirmed reports whether the new address has been confirmed and applied
+func (c *EmailChange) IsConfirmed() bool {
+	return c.ConfirmedAt != nil
+}
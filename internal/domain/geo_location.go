@@ -0,0 +1,38 @@
+package domain
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// GeoLocation represents the approximate location of an IP address
+type GeoLocation struct {
+	City        string
+	Country     string
+	CountryCode string
+	Latitude    float64
+	Longitude   float64
+}
+
+// String describes the location for people, e.g. "Jakarta, Indonesia"
+func (l *GeoLocation) String() string {
+	if l.City == "" {
+		return l.Country
+	}
+	if l.Country == "" {
+		return l.City
+	}
+
+	return l.City + ", " + l.Country
+}
+
+// DistanceKm returns the great-circle distance between two coordinates in kilometers (haversine formula)
+func DistanceKm(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	lat1 := latitude1 * math.Pi / 180
+	lat2 := latitude2 * math.Pi / 180
+	deltaLat := (latitude2 - latitude1) * math.Pi / 180
+	deltaLon := (longitude2 - longitude1) * math.Pi / 180
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// ImpersonationScopes lists the scopes an impersonation token can be granted
+var ImpersonationScopes = []string{ScopeProfileRead, ScopeProfileWrite}
+
+// Impersonation represents an administrator acting as a user, e.g. for support to see what the user sees
+type Impersonation struct {
+	ID        int64      `json:"id"`
+	AdminID   int64      `json:"admin_id"`
+	UserID    int64      `json:"user_id"`
+	Reason    string     `json:"reason" example:"ticket #1234, profile page doesn't load"`
+	Scopes    []string   `json:"scopes" example:"profile:read"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+}
+
+// IsActive reports whether the impersonation has neither been ended nor expired
+func (i *Impersonation) IsActive() bool {
+	return i.EndedAt == nil && time.Now().Before(i.ExpiresAt)
+}
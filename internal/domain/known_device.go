@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// KnownDevice represents a device a user has signed in from, identified by its device ID and user agent family
+type KnownDevice struct {
+	ID              int64     `json:"id"`
+	UserID          int64     `json:"-"`
+	DeviceIDHash    string    `json:"-"`
+	UserAgentFamily string    `json:"user_agent_family"`
+	IPAddress       string    `json:"ip_address"`
+	Location        string    `json:"location"`
+	FirstSeenAt     time.Time `json:"first_seen_at"`
+	LastSeenAt      time.Time `json:"last_seen_at"`
+}
+
+// DeviceLogin describes a sign-in as reported to the user
+type DeviceLogin struct {
+	Device    string
+	IPAddress string
+	// Location is the approximate location of the IP address, empty when unknown
+	Location string
+	LoginAt  time.Time
+}
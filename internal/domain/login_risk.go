@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Reasons raising the risk score of a login
+const (
+	RiskReasonKnownBadIP       = "known_bad_ip"
+	RiskReasonImpossibleTravel = "impossible_travel"
+	RiskReasonNewCountry       = "new_country"
+	RiskReasonVelocity         = "velocity"
+)
+
+// Decisions taken on a login according to its risk score
+const (
+	RiskDecisionAllow      = "allow"
+	RiskDecisionRequireMFA = "require_mfa"
+	RiskDecisionBlock      = "block"
+)
+
+// LoginEvent represents a login with correct credentials and the risk assessed for it
+type LoginEvent struct {
+	ID          int64    `json:"id"`
+	UserID      int64    `json:"-"`
+	IPAddress   string   `json:"ip_address"`
+	CountryCode string   `json:"country_code"`
+	Latitude    *float64 `json:"-"`
+	Longitude   *float64 `json:"-"`
+	RiskScore   int      `json:"risk_score"`
+	RiskReasons []string `json:"risk_reasons"`
+	Decision    string   `json:"decision"`
+	// Succeeded is set once the user is signed in, right away or after a second factor
+	Succeeded bool      `json:"succeeded"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsLocated reports whether the coordinates of the login are known
+func (e *LoginEvent) IsLocated() bool {
+	return e.Latitude != nil && e.Longitude != nil
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// MaxMFAAttempts is the number of wrong codes after which an MFA challenge is discarded
+const MaxMFAAttempts = 5
+
+// MFAChallenge represents a second factor the user must complete to finish a login
+type MFAChallenge struct {
+	ID     int64
+	UserID int64
+	// LoginEventID is the login waiting for the second factor
+	LoginEventID *int64
+	CodeHash     string
+	Channel      string
+	// RememberMe and UseSession are the options of the login, applied once the challenge is completed
+	RememberMe bool
+	UseSession bool
+	Attempts   int
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+}
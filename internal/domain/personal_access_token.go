@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PersonalAccessTokenPrefix marks raw personal access tokens so they can be told apart from JWTs
+const PersonalAccessTokenPrefix = "pat_"
+
+// PersonalAccessTokenScopes lists the scopes a personal access token can be granted
+var PersonalAccessTokenScopes = []string{ScopeProfileRead, ScopeProfileWrite}
+
+// PersonalAccessToken represents a long-lived token a user creates for scripts and CLI tools
+type PersonalAccessToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP *string    `json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Validate validates the personal access token
+func (t *PersonalAccessToken) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("token name is required")
+	}
+
+	if len(t.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+
+	for _, scope := range t.Scopes {
+		if !slices.Contains(PersonalAccessTokenScopes, scope) {
+			return errors.New("unknown scope: " + scope)
+		}
+	}
+
+	return nil
+}
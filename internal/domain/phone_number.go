@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// Channels delivering one-time codes
+const (
+	OTPChannelEmail = "email"
+	OTPChannelSMS   = "sms"
+	OTPChannelVoice = "voice"
+)
+
+// Purposes of the codes sent to phone numbers, they decide the wording of the message
+const (
+	PhoneCodePurposeLogin        = "login"
+	PhoneCodePurposeMFA          = "mfa"
+	PhoneCodePurposeVerification = "verification"
+)
+
+// e164Pattern matches phone numbers in E.164 format: a plus sign and up to 15 digits, the first one not zero
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// IsValidPhoneNumber reports whether the phone number is in E.164 format, e.g. "+6281234567890"
+func IsValidPhoneNumber(phoneNumber string) bool {
+	return e164Pattern.MatchString(phoneNumber)
+}
+
+// IsOTPChannel reports whether the channel can deliver one-time codes
+func IsOTPChannel(channel string) bool {
+	return channel == OTPChannelEmail || IsPhoneChannel(channel)
+}
+
+// IsPhoneChannel reports whether the channel delivers codes to a phone number
+func IsPhoneChannel(channel string) bool {
+	return channel == OTPChannelSMS || channel == OTPChannelVoice
+}
+
+// PhoneVerification represents a code sent to a phone number the user wants to add to their account
+type PhoneVerification struct {
+	UserID      int64
+	PhoneNumber string
+	CodeHash    string
+	Attempts    int
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
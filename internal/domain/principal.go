@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Principal types
+const (
+	PrincipalTypeUser    = "user"
+	PrincipalTypeService = "service"
+)
+
+// Scopes that can be carried by access credentials
+const (
+	// ScopeAll is held by interactive user sessions and grants every scope
+	ScopeAll          = "*"
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+	ScopeTokensWrite  = "tokens:write"
+	// ScopeTokenExchange lets a service account exchange the tokens of its callers for downscoped tokens,
+	// it is granted by an administrator
+	ScopeTokenExchange = "tokens:exchange"
+	// ScopeTokenIntrospect lets a service account introspect the tokens of any user
+	ScopeTokenIntrospect = "tokens:introspect"
+)
+
+// Authentication methods recorded in the amr claim of access tokens and in sessions (RFC 8176)
+const (
+	AMRPassword = "pwd"
+	AMROTP      = "otp"
+	AMRMFA      = "mfa"
+	AMRPasskey  = "passkey"
+)
+
+// Principal represents the authenticated caller of a request
+type Principal struct {
+	Type string
+	// ID is the user ID for user principals and the service account ID for service principals
+	ID     int64
+	Scopes []string
+	// CredentialID identifies the credential used to authenticate, e.g. the personal access token ID
+	CredentialID int64
+	// ClientID is the OAuth client the token was issued to
+	ClientID string
+	// SessionID is set when the user authenticated with a session cookie
+	SessionID int64
+	// Roles are the current roles of user principals
+	Roles []string
+	// ActorID is the administrator acting as the user, it is set with ImpersonationID for impersonation tokens
+	ActorID         int64
+	ImpersonationID int64
+	// AuthTime is when the user last proved their identity, it is zero for credentials not issued by a login
+	AuthTime time.Time
+	// AMR lists the authentication methods used at AuthTime
+	AMR []string
+	// ExpiresAt is when the credential expires, it is zero for credentials checked on every request such as sessions
+	ExpiresAt time.Time
+}
+
+// IsUser reports whether the principal is a human user
+func (p *Principal) IsUser() bool {
+	return p.Type == PrincipalTypeUser
+}
+
+// IsImpersonated reports whether an administrator is acting as the user
+func (p *Principal) IsImpersonated() bool {
+	return p.ActorID != 0
+}
+
+// IsInteractive reports whether the principal is a user who logged in themselves, with a login token or a session
+func (p *Principal) IsInteractive() bool {
+	return p.IsUser() && !p.IsImpersonated() && p.CredentialID == 0 && p.ClientID == ""
+}
+
+// IsAuthenticatedWithin reports whether the user proved their identity within maxAge
+func (p *Principal) IsAuthenticatedWithin(maxAge time.Duration) bool {
+	return !p.AuthTime.IsZero() && time.Since(p.AuthTime) <= maxAge
+}
+
+// HasAMR reports whether the user authenticated with any of the given methods
+func (p *Principal) HasAMR(methods ...string) bool {
+	for _, method := range methods {
+		if slices.Contains(p.AMR, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasRole reports whether the principal is a user who has been granted the given role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasScope reports whether the principal has been granted the given scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,8 +4,10 @@ import "time"
 
 // RememberToken represents a remember token.
 type RememberToken struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// ClientID is the OAuth client the token was issued to, empty for first-party logins
+	ClientID  string    `json:"client_id"`
 	TokenHash string    `json:"token_hash"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
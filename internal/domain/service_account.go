@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ServiceAccountClientIDPrefix marks the client ID of service accounts
+const ServiceAccountClientIDPrefix = "sa_"
+
+// scopePattern restricts the scopes that can be granted to service accounts
+var scopePattern = regexp.MustCompile(`^[a-z][a-z0-9_.:-]*$`)
+
+// ServiceAccount represents a non-human principal owned by an organization
+type ServiceAccount struct {
+	ID           int64     `json:"id"`
+	ClientID     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	Organization string    `json:"organization"`
+	OwnerUserID  int64     `json:"owner_user_id"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Validate validates the service account
+func (a *ServiceAccount) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return errors.New("service account name is required")
+	}
+
+	if strings.TrimSpace(a.Organization) == "" {
+		return errors.New("organization is required")
+	}
+
+	for _, scope := range a.Scopes {
+		if !IsGrantableServiceScope(scope) {
+			return errors.New("invalid scope: " + scope)
+		}
+	}
+
+	return nil
+}
+
+// IsGrantableServiceScope reports whether the scope can be granted to a service account
+func IsGrantableServiceScope(scope string) bool {
+	return scope != ScopeTokensWrite && scopePattern.MatchString(scope)
+}
+
+// IsPrivilegedServiceScope reports whether the scope reaches the tokens of other users,
+// only administrators can grant it to a service account
+func IsPrivilegedServiceScope(scope string) bool {
+	return scope == ScopeTokenIntrospect || scope == ScopeTokenExchange
+}
+
+// ServiceAccountSecret represents a hashed client secret of a service account.
+// A secret without expiry stays valid until a rotation gives it one.
+type ServiceAccountSecret struct {
+	ID               int64
+	ServiceAccountID int64
+	SecretHash       string
+	ExpiresAt        *time.Time
+	CreatedAt        time.Time
+}
+
+// ServiceAccountKey represents a public key a service account signs client assertions with
+type ServiceAccountKey struct {
+	ID               int64     `json:"id"`
+	ServiceAccountID int64     `json:"service_account_id"`
+	KeyID            string    `json:"key_id"`
+	PublicKeyPEM     string    `json:"public_key"`
+	CreatedAt        time.Time `json:"created_at"`
+}
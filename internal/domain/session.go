@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Session represents a server-side browser session referenced by an opaque cookie
+type Session struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	// Roles snapshots the roles of the user when the session token was issued, a change rotates the token
+	Roles []string
+	// Persistent sessions survive browser restarts, their cookie has an expiry
+	Persistent bool
+	// AuthTime is when the user last proved their identity in this session, AMR how they did it
+	AuthTime   time.Time
+	AMR        []string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	// RotatedAt is when the token was last replaced, nil if it never was
+	RotatedAt *time.Time
+}
+
+// IsIdle reports whether the session hasn't been used within the idle timeout
+func (s *Session) IsIdle(idleTimeout time.Duration) bool {
+	return idleTimeout > 0 && time.Since(s.LastSeenAt) > idleTimeout
+}
+
+// HasRoles reports whether the session was issued for exactly the given roles, in any order
+func (s *Session) HasRoles(roles []string) bool {
+	current := slices.Clone(s.Roles)
+	slices.Sort(current)
+	other := slices.Clone(roles)
+	slices.Sort(other)
+
+	return slices.Equal(current, other)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// TrustedDevice represents a device on which the user completed a second factor and chose to skip it for a while
+type TrustedDevice struct {
+	ID              int64     `json:"id"`
+	UserID          int64     `json:"-"`
+	DeviceIDHash    string    `json:"-"`
+	UserAgentFamily string    `json:"user_agent_family"`
+	IPAddress       string    `json:"ip_address"`
+	CreatedAt       time.Time `json:"created_at"`
+	LastUsedAt      time.Time `json:"last_used_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// IsExpired reports whether the device must complete a second factor again
+func (d *TrustedDevice) IsExpired() bool {
+	return time.Now().After(d.ExpiresAt)
+}
@@ -3,17 +3,84 @@ package domain
 import (
 	"errors"
 	"strings"
+	"time"
 )
 
+// Authentication sources a user account can originate from
+const (
+	AuthSourceLocal = "local"
+	AuthSourceLDAP  = "ldap"
+)
+
+// RoleAdmin grants access to the administration API
+const RoleAdmin = "admin"
+
+// Account statuses. UserStatusPendingDeletion is derived from DeletedAt and never stored.
+const (
+	UserStatusActive          = "active"
+	UserStatusDisabled        = "disabled"
+	UserStatusSuspended       = "suspended"
+	UserStatusPendingDeletion = "pending_deletion"
+)
+
+// Reason codes recorded when an administrator blocks an account, they are only shown to administrators
+const (
+	StatusReasonAbuse          = "abuse"
+	StatusReasonFraud          = "fraud"
+	StatusReasonCompromised    = "compromised"
+	StatusReasonTermsViolation = "terms_violation"
+	StatusReasonOther          = "other"
+)
+
+// StatusReasons lists the reason codes an account can be blocked with
+var StatusReasons = []string{
+	StatusReasonAbuse,
+	StatusReasonFraud,
+	StatusReasonCompromised,
+	StatusReasonTermsViolation,
+	StatusReasonOther,
+}
+
 // User represent a user in the system
 // @Description User information
-// @Description with id, name, email, and password
+// @Description with id, name, email and profile attributes
 type User struct {
-	ID       int64  `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Verified bool   `json:"verified"`
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Password holds the password hash, it is never serialized
+	Password   string   `json:"-"`
+	Verified   bool     `json:"verified"`
+	AuthSource string   `json:"auth_source"`
+	Roles      []string `json:"roles"`
+	// PasswordResetRequired blocks password logins, e.g. after the password was found in a data breach
+	PasswordResetRequired bool      `json:"password_reset_required"`
+	PasswordChangedAt     time.Time `json:"password_changed_at"`
+	Locale                string    `json:"locale" example:"en-US"`
+	Timezone              string    `json:"timezone" example:"Asia/Jakarta"`
+	AvatarURL             string    `json:"avatar_url" example:"https://example.com/avatar.png"`
+	// PublicMetadata and PrivateMetadata are written by the user, so no other service may trust them.
+	// PrivateMetadata is only ever returned to the user themselves
+	PublicMetadata  map[string]any `json:"public_metadata"`
+	PrivateMetadata map[string]any `json:"private_metadata"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	LastLoginAt     *time.Time     `json:"last_login_at"`
+	// DeletedAt is set while the account waits for deletion, it can be restored by logging in until PurgeAt
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`
+	// PhoneNumber is a verified phone number in E.164 format, numbers waiting for verification are kept aside
+	PhoneNumber string `json:"phone_number" example:"+6281234567890"`
+	// MFAEnabled makes every password login complete a second factor, unless the device is trusted
+	MFAEnabled bool `json:"mfa_enabled"`
+	// MFAChannel is the OTP channel of the second factor, phone channels fall back to email without a phone number
+	MFAChannel string `json:"mfa_channel" example:"email"`
+	// Status is set by administrators, the user is only ever told their account is unavailable
+	Status          string     `json:"-"`
+	StatusReason    string     `json:"-"`
+	StatusNote      string     `json:"-"`
+	SuspendedUntil  *time.Time `json:"-"`
+	StatusChangedAt *time.Time `json:"-"`
 }
 
 // Validate user
@@ -24,3 +91,48 @@ func (u *User) Validate() error {
 
 	return nil
 }
+
+// IsDeleted reports whether the user has deleted their account and it is waiting to be purged
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// EffectiveStatus returns the status the account is in right now
+func (u *User) EffectiveStatus() string {
+	if u.IsDeleted() {
+		return UserStatusPendingDeletion
+	}
+
+	return u.blockStatus()
+}
+
+// IsBlocked reports whether an administrator has disabled the account or it is currently suspended.
+// A deleted account stays blocked.
+func (u *User) IsBlocked() bool {
+	return u.blockStatus() != UserStatusActive
+}
+
+// blockStatus returns the status set by administrators, a suspension ends by itself once SuspendedUntil has passed
+func (u *User) blockStatus() string {
+	switch u.Status {
+	case UserStatusDisabled:
+		return UserStatusDisabled
+	case UserStatusSuspended:
+		if u.SuspendedUntil != nil && time.Now().Before(*u.SuspendedUntil) {
+			return UserStatusSuspended
+		}
+	}
+
+	return UserStatusActive
+}
+
+// HasRole reports whether the user has been granted the given role
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"auth/internal/domain"
+	"auth/internal/usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminHandler represents the administration handler object
+type AdminHandler struct {
+	logger                    *slog.Logger
+	getUserProfileUseCase     *usecase.GetUserProfileUseCase
+	updateUserStatusUseCase   *usecase.UpdateUserStatusUseCase
+	startImpersonationUseCase *usecase.StartImpersonationUseCase
+	endImpersonationUseCase   *usecase.EndImpersonationUseCase
+}
+
+// NewAdminHandler creates a new administration handler object
+func NewAdminHandler(
+	logger *slog.Logger,
+	getProfileUC *usecase.GetUserProfileUseCase,
+	updateStatusUC *usecase.UpdateUserStatusUseCase,
+	startImpersonationUC *usecase.StartImpersonationUseCase,
+	endImpersonationUC *usecase.EndImpersonationUseCase,
+) *AdminHandler {
+	return &AdminHandler{
+		logger:                    logger,
+		getUserProfileUseCase:     getProfileUC,
+		updateUserStatusUseCase:   updateStatusUC,
+		startImpersonationUseCase: startImpersonationUC,
+		endImpersonationUseCase:   endImpersonationUC,
+	}
+}
+
+// AdminUserResponse represent a user as seen by administrators, including why the account is blocked
+type AdminUserResponse struct {
+	domain.User
+	Status          string     `json:"status" example:"suspended"`
+	StatusReason    string     `json:"status_reason,omitempty" example:"abuse"`
+	StatusNote      string     `json:"status_note,omitempty" example:"spam reports from several users"`
+	SuspendedUntil  *time.Time `json:"suspended_until,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+}
+
+// UpdateUserStatusRequest represent the request body for update user status
+type UpdateUserStatusRequest struct {
+	Status         string     `json:"status" example:"suspended"`
+	Reason         string     `json:"reason" example:"abuse"`
+	Note           string     `json:"note" example:"spam reports from several users"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+// UpdateUserStatusFailResponse represent the response body for update user status fail
+type UpdateUserStatusFailResponse struct {
+	Status         []string `json:"status" example:"status must be active, disabled or suspended"`
+	Reason         []string `json:"reason" example:"reason must be one of abuse, fraud, compromised, terms_violation or other"`
+	Note           []string `json:"note" example:"note must be at most 500 characters long"`
+	SuspendedUntil []string `json:"suspended_until" example:"suspended until must be in the future"`
+}
+
+// StartImpersonationRequest represent the request body for start impersonation
+type StartImpersonationRequest struct {
+	UserID int64    `json:"user_id" example:"42"`
+	Reason string   `json:"reason" example:"ticket #1234, profile page doesn't load"`
+	Scopes []string `json:"scopes" example:"profile:read"`
+}
+
+// StartImpersonationSuccessResponse represent the response body for start impersonation success
+type StartImpersonationSuccessResponse struct {
+	ID          int64     `json:"id" example:"1"`
+	UserID      int64     `json:"user_id" example:"42"`
+	Scopes      []string  `json:"scopes" example:"profile:read"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	AccessToken string    `json:"access_token"`
+}
+
+// StartImpersonationFailResponse represent the response body for start impersonation fail
+type StartImpersonationFailResponse struct {
+	Reason []string `json:"reason" example:"a reason is required to impersonate a user"`
+	Scopes []string `json:"scopes" example:"invalid or unsupported scope"`
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Get a user with the status of their account and the reason it was blocked. Requires the admin role.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} SuccessResponse{data=AdminUserResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/users/{id} [get]
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	user, err := h.getUserProfileUseCase.Execute(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, usecase.ErrUserNotFound.Error())
+			return
+		}
+
+		h.logger.Error("Failed to get user : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, newAdminUserResponse(user))
+}
+
+// UpdateUserStatus godoc
+// @Summary Update the status of a user
+// @Description Disable, suspend until a given time or reactivate an account. Blocking an account signs the user out everywhere,
+// @Description the user is only told that their account is unavailable. Requires the admin role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Param status body UpdateUserStatusRequest true "New status"
+// @Success 200 {object} SuccessResponse{data=AdminUserResponse}
+// @Failure 400 {object} FailResponse{data=UpdateUserStatusFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/users/{id}/status [put]
+func (h *AdminHandler) UpdateUserStatus(w http.ResponseWriter, r *http.Request) {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	var req UpdateUserStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	user, err := h.updateUserStatusUseCase.Execute(r.Context(), usecase.UpdateUserStatusInput{
+		AdminID:        adminID,
+		UserID:         userID,
+		Status:         req.Status,
+		Reason:         req.Reason,
+		Note:           req.Note,
+		SuspendedUntil: req.SuspendedUntil,
+	})
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrInvalidUserStatus) {
+			validationErrors["status"] = append(validationErrors["status"], usecase.ErrInvalidUserStatus.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidStatusReason) {
+			validationErrors["reason"] = append(validationErrors["reason"], usecase.ErrInvalidStatusReason.Error())
+		}
+		if errors.Is(err, usecase.ErrStatusNoteTooLong) {
+			validationErrors["note"] = append(validationErrors["note"], usecase.ErrStatusNoteTooLong.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidSuspensionEnd) {
+			validationErrors["suspended_until"] = append(validationErrors["suspended_until"], usecase.ErrInvalidSuspensionEnd.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		if errors.Is(err, usecase.ErrOwnStatusChange) {
+			writeError(w, http.StatusConflict, usecase.ErrOwnStatusChange.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, usecase.ErrUserNotFound.Error())
+			return
+		}
+
+		h.logger.Error("Failed to update user status : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, newAdminUserResponse(user))
+}
+
+// StartImpersonation godoc
+// @Summary Impersonate a user
+// @Description Issue a short-lived access token to act as a user, e.g. to see what they see. The token names the administrator
+// @Description in its act claim, every request made with it is audited and security settings of the account can't be changed with it.
+// @Description Requires the admin role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param impersonation body StartImpersonationRequest true "User to impersonate and why"
+// @Success 201 {object} SuccessResponse{data=StartImpersonationSuccessResponse}
+// @Failure 400 {object} FailResponse{data=StartImpersonationFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/impersonations [post]
+func (h *AdminHandler) StartImpersonation(w http.ResponseWriter, r *http.Request) {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req StartImpersonationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	result, err := h.startImpersonationUseCase.Execute(r.Context(), usecase.StartImpersonationInput{
+		AdminID:   adminID,
+		UserID:    req.UserID,
+		Reason:    req.Reason,
+		Scopes:    req.Scopes,
+		IPAddress: clientIP(r),
+	})
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrEmptyImpersonationReason) {
+			validationErrors["reason"] = append(validationErrors["reason"], usecase.ErrEmptyImpersonationReason.Error())
+		}
+		if errors.Is(err, usecase.ErrImpersonationReasonTooLong) {
+			validationErrors["reason"] = append(validationErrors["reason"], usecase.ErrImpersonationReasonTooLong.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidScope) {
+			validationErrors["scopes"] = append(validationErrors["scopes"], usecase.ErrInvalidScope.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, usecase.ErrUserNotFound.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrSelfImpersonation) {
+			writeError(w, http.StatusConflict, usecase.ErrSelfImpersonation.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrAdminImpersonation) {
+			writeError(w, http.StatusForbidden, usecase.ErrAdminImpersonation.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrAccountUnavailable) {
+			writeError(w, http.StatusConflict, usecase.ErrAccountUnavailable.Error())
+			return
+		}
+
+		h.logger.Error("Failed to start impersonation : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	response := StartImpersonationSuccessResponse{
+		ID:          result.Impersonation.ID,
+		UserID:      result.Impersonation.UserID,
+		Scopes:      result.Impersonation.Scopes,
+		ExpiresAt:   result.Impersonation.ExpiresAt,
+		AccessToken: result.AccessToken,
+	}
+
+	writeSuccess(w, http.StatusCreated, response)
+}
+
+// EndImpersonation godoc
+// @Summary End an impersonation
+// @Description End an impersonation started by the current administrator, its access token stops working right away.
+// @Description Requires the admin role.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Impersonation ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/impersonations/{id} [delete]
+func (h *AdminHandler) EndImpersonation(w http.ResponseWriter, r *http.Request) {
+	adminID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	impersonationID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	err = h.endImpersonationUseCase.Execute(r.Context(), adminID, impersonationID, clientIP(r))
+	if err != nil {
+		if errors.Is(err, usecase.ErrImpersonationNotFound) {
+			writeError(w, http.StatusNotFound, usecase.ErrImpersonationNotFound.Error())
+			return
+		}
+
+		h.logger.Error("Failed to end impersonation : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "impersonation has ended"})
+}
+
+// newAdminUserResponse creates the administrator view of a user
+func newAdminUserResponse(user *domain.User) AdminUserResponse {
+	return AdminUserResponse{
+		User:            *user,
+		Status:          user.EffectiveStatus(),
+		StatusReason:    user.StatusReason,
+		StatusNote:      user.StatusNote,
+		SuspendedUntil:  user.SuspendedUntil,
+		StatusChangedAt: user.StatusChangedAt,
+	}
+}
@@ -7,47 +7,75 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 )
 
 // AuthHandler represents the auth handler object
 type AuthHandler struct {
 	logger                         *slog.Logger
+	cookies                        CookiePolicy
 	loginUserUseCase               *usecase.LoginUserUseCase
+	loginUserSessionUseCase        *usecase.LoginUserSessionUseCase
+	logoutUseCase                  *usecase.LogoutUseCase
 	refreshTokenUseCase            *usecase.RefreshTokenUseCase
 	verifyEmailUseCase             *usecase.VerifyEmailUseCase
 	requestPasswordResetUseCase    *usecase.RequestPasswordResetUseCase
 	resetPasswordUseCase           *usecase.ResetPasswordUseCase
+	checkPasswordUseCase           *usecase.CheckPasswordUseCase
+	changeExpiredPasswordUseCase   *usecase.ChangeExpiredPasswordUseCase
+	restoreAccountUseCase          *usecase.RestoreAccountUseCase
 	requestVerificationCodeUseCase *usecase.RequestVerificationCodeUseCase
 	verifyCodeUseCase              *usecase.VerifyCodeUseCase
 	requestLoginOTPUseCase         *usecase.RequestLoginOTPUseCase
 	verifyLoginOTPUseCase          *usecase.VerifyLoginOTPUseCase
+	reauthenticateUseCase          *usecase.ReauthenticateUseCase
+	secureAccountUseCase           *usecase.SecureAccountUseCase
+	verifyMFAChallengeUseCase      *usecase.VerifyMFAChallengeUseCase
 }
 
 // NewAuthHandler creates a new auth handler object
 func NewAuthHandler(
 	logger *slog.Logger,
+	cookies CookiePolicy,
 	loginUC *usecase.LoginUserUseCase,
+	loginSessionUC *usecase.LoginUserSessionUseCase,
+	logoutUC *usecase.LogoutUseCase,
 	refreshUC *usecase.RefreshTokenUseCase,
 	verifyUC *usecase.VerifyEmailUseCase,
 	requestPasswordResetUC *usecase.RequestPasswordResetUseCase,
 	resetPasswordUC *usecase.ResetPasswordUseCase,
+	checkPasswordUC *usecase.CheckPasswordUseCase,
+	changeExpiredPasswordUC *usecase.ChangeExpiredPasswordUseCase,
+	restoreAccountUC *usecase.RestoreAccountUseCase,
 	requestVerificationCodeUC *usecase.RequestVerificationCodeUseCase,
 	verifyCodeUC *usecase.VerifyCodeUseCase,
 	requestLoginOTPUC *usecase.RequestLoginOTPUseCase,
 	verifyLoginOTPUC *usecase.VerifyLoginOTPUseCase,
+	reauthenticateUC *usecase.ReauthenticateUseCase,
+	secureAccountUC *usecase.SecureAccountUseCase,
+	verifyMFAChallengeUC *usecase.VerifyMFAChallengeUseCase,
 ) *AuthHandler {
 	return &AuthHandler{
 		logger:                         logger,
+		cookies:                        cookies,
 		loginUserUseCase:               loginUC,
+		loginUserSessionUseCase:        loginSessionUC,
+		logoutUseCase:                  logoutUC,
 		refreshTokenUseCase:            refreshUC,
 		verifyEmailUseCase:             verifyUC,
 		requestPasswordResetUseCase:    requestPasswordResetUC,
 		resetPasswordUseCase:           resetPasswordUC,
+		checkPasswordUseCase:           checkPasswordUC,
+		changeExpiredPasswordUseCase:   changeExpiredPasswordUC,
+		restoreAccountUseCase:          restoreAccountUC,
 		requestVerificationCodeUseCase: requestVerificationCodeUC,
 		verifyCodeUseCase:              verifyCodeUC,
 		requestLoginOTPUseCase:         requestLoginOTPUC,
 		verifyLoginOTPUseCase:          verifyLoginOTPUC,
+		reauthenticateUseCase:          reauthenticateUC,
+		secureAccountUseCase:           secureAccountUC,
+		verifyMFAChallengeUseCase:      verifyMFAChallengeUC,
 	}
 }
 
@@ -56,6 +84,8 @@ type LoginUserRequest struct {
 	Email      string `json:"email" example:"username@domain"`
 	Password   string `json:"password" example:"password"`
 	RememberMe bool   `json:"remember_me" example:"true"`
+	// UseSession sets an HttpOnly session cookie instead of returning tokens
+	UseSession bool `json:"use_session" example:"false"`
 }
 
 // LoginUserSuccessResponse represent the response body for login user success
@@ -64,10 +94,15 @@ type LoginUserSuccessResponse struct {
 	RememberToken string `json:"remember_token" example:"InR5cCI6IkpXVCJ9eyJhbGciOiJIUzI1NiIs"`
 }
 
+// LoginUserSessionSuccessResponse represent the response body for login user success in session mode
+type LoginUserSessionSuccessResponse struct {
+	SessionExpiresAt time.Time `json:"session_expires_at"`
+}
+
 // LoginUserFailResponse represent the response body for login user fail
 type LoginUserFailResponse struct {
 	Email    []string `json:"email" example:"email is required,email is invalid"`
-	Password []string `json:"password" example:"password is required,password too short"`
+	Password []string `json:"password" example:"password must be at least 8 characters long,password is too easy to guess"`
 }
 
 // RefreshTokenResponse represent the response body for refresh token
@@ -76,6 +111,18 @@ type RefreshTokenResponse struct {
 	RememberToken string `json:"remember_token" example:"InR5cCI6IkpXVCJ9eyJhbGciOiJIUzI1NiIs"`
 }
 
+// ReauthenticateRequest represent the request body for reauthenticate
+type ReauthenticateRequest struct {
+	Password string `json:"password" example:"password"`
+}
+
+// ReauthenticateResponse represent the response body for reauthenticate
+type ReauthenticateResponse struct {
+	// AccessToken replaces the access token of token clients, session users get a new session cookie instead
+	AccessToken string    `json:"access_token,omitempty" example:"eyJhbGciOiJIUzI1NiIs"`
+	AuthTime    time.Time `json:"auth_time"`
+}
+
 // PasswordResetRequest represent the request body for password reset
 type PasswordResetRequest struct {
 	Email string `json:"email" example:"username@domain"`
@@ -103,7 +150,57 @@ type ResetPasswordSuccessResponse struct {
 
 // ResetPasswordFailResponse represent the response body for reset password fail
 type ResetPasswordFailResponse struct {
-	Password []string `json:"password" example:"password is required,password too short"`
+	Password []string `json:"password" example:"password must be at least 8 characters long,password is too easy to guess"`
+}
+
+// CheckPasswordRequest represent the request body for check password
+type CheckPasswordRequest struct {
+	Password string `json:"password" example:"$fesf&idsie94"`
+	Name     string `json:"name" example:"John Doe"`
+	Email    string `json:"email" example:"username@domain"`
+}
+
+// ChangeExpiredPasswordRequest represent the request body for change expired password
+type ChangeExpiredPasswordRequest struct {
+	NewPassword string `json:"new_password" example:"$fesf&idsie94"`
+}
+
+// PasswordChangeRequiredResponse represent the response body for a login with an expired password
+type PasswordChangeRequiredResponse struct {
+	Message             string    `json:"message" example:"password has expired and must be changed"`
+	PasswordChangeToken string    `json:"password_change_token" example:"eyJhbGciOiJIUzI1NiIs"`
+	ExpiresAt           time.Time `json:"expires_at"`
+}
+
+// MFARequiredResponse represent the response body for a risky login that needs a second factor
+type MFARequiredResponse struct {
+	Message   string    `json:"message" example:"a second factor is required to finish signing in"`
+	MFAToken  string    `json:"mfa_token" example:"eyJhbGciOiJIUzI1NiIs"`
+	Channel   string    `json:"channel" example:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// VerifyMFARequest represent the request body for verify mfa
+type VerifyMFARequest struct {
+	Code string `json:"code" example:"123456"`
+	// TrustDevice lets the device skip the second factor of its next logins for a while
+	TrustDevice bool `json:"trust_device" example:"false"`
+}
+
+// VerifyMFASuccessResponse represent the response body for verify mfa success
+type VerifyMFASuccessResponse struct {
+	AccessToken   string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIs"`
+	RememberToken string `json:"remember_token" example:"InR5cCI6IkpXVCJ9eyJhbGciOiJIUzI1NiIs"`
+	// TrustedDeviceToken is sent back by non-web clients in the X-Trusted-Device-Token header, browsers get a cookie
+	TrustedDeviceToken string `json:"trusted_device_token,omitempty" example:"eyJhbGciOiJIUzI1NiIs"`
+}
+
+// AccountPendingDeletionResponse represent the response body for a login to an account scheduled for deletion
+type AccountPendingDeletionResponse struct {
+	Message      string    `json:"message" example:"account is scheduled for deletion"`
+	RestoreToken string    `json:"restore_token" example:"eyJhbGciOiJIUzI1NiIs"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	PurgeAt      time.Time `json:"purge_at"`
 }
 
 // RequestCodeRequest represent the request body for request code
@@ -138,7 +235,8 @@ type VerifyCodeFailResponse struct {
 
 // RequestLoginOTPRequest represent the request body for request login otp
 type RequestLoginOTPRequest struct {
-	Email string `json:"email" example:"username@domain"`
+	Email   string `json:"email" example:"username@domain"`
+	Channel string `json:"channel" example:"sms" enums:"email,sms,voice"`
 }
 
 // RequestLoginOTPSuccessResponse represent the response body for request login otp success
@@ -149,18 +247,35 @@ type RequestLoginOTPSuccessResponse struct {
 // RequestLoginOTPFailResponse represent the response body for request login otp fail
 type RequestLoginOTPFailResponse struct {
 	Message []string `json:"message" example:"email is required,email is invalid"`
+	Channel []string `json:"channel" example:"channel must be email, sms or voice"`
+}
+
+// VerifyLoginOTPRequest represent the request body for verify login otp
+type VerifyLoginOTPRequest struct {
+	Email      string `json:"email" example:"username@domain"`
+	Code       string `json:"code" example:"123456"`
+	RememberMe bool   `json:"remember_me" example:"false"`
 }
 
 // LoginUser godoc
 // @Summary			Logs in a user
 // @Description  Authenticates a user by email and password and returns a JWT token.
+// @Description  With use_session, an HttpOnly session cookie is set instead and no token is returned.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        credentials body LoginUserRequest true "User Login Credentials"
 // @Success      200 {object} SuccessResponse{data=LoginUserSuccessResponse}
+// @Success      201 {object} SuccessResponse{data=LoginUserSessionSuccessResponse}
 // @Failure      400 {object} FailResponse{data=LoginUserFailResponse}
 // @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} FailResponse{data=PasswordChangeRequiredResponse} "Password has expired, change it with the restricted token"
+// @Failure      403 {object} ErrorResponse "Password must be reset, e.g. after it appeared in a data breach"
+// @Failure      403 {object} FailResponse{data=AccountPendingDeletionResponse} "Account is scheduled for deletion, restore it with the restricted token"
+// @Failure      403 {object} ErrorResponse "Account has been disabled or suspended"
+// @Failure      403 {object} FailResponse{data=MFARequiredResponse} "Risky login, complete it with the code sent to the user and the restricted token"
+// @Failure      403 {object} ErrorResponse "Risky login has been blocked"
+// @Failure      429 {object} ErrorResponse "Too many logins waiting for a second factor"
 // @Failure      500 {object} ErrorResponse
 // @Router       /api/v1/auth [post]
 func (h *AuthHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
@@ -170,42 +285,116 @@ func (h *AuthHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.loginUserUseCase.Execute(r.Context(), req.Email, req.Password, req.RememberMe)
+	if req.UseSession {
+		h.loginUserSession(w, r, req)
+		return
+	}
 
+	result, err := h.loginUserUseCase.Execute(r.Context(), req.Email, req.Password, req.RememberMe)
 	if err != nil {
-		if errors.Is(err, usecase.ErrInvalidCredentials) {
-			writeError(w, http.StatusUnauthorized, usecase.ErrInvalidCredentials.Error())
-		} else {
-			writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
-		}
-
+		h.writeLoginError(w, err)
 		return
 	}
 
 	response := LoginUserSuccessResponse{AccessToken: result.AccessToken}
 
 	if result.RememberToken != "" {
-		setRememberCookie(w, result.RememberToken)    // for web client
-		response.RememberToken = result.RememberToken // for non-web client
+		h.cookies.setRememberCookie(w, result.RememberToken) // for web client
+		response.RememberToken = result.RememberToken        // for non-web client
 	}
 
 	writeSuccess(w, http.StatusOK, response)
 }
 
+// loginUserSession logs the user in with a server-side session
+func (h *AuthHandler) loginUserSession(w http.ResponseWriter, r *http.Request, req LoginUserRequest) {
+	result, err := h.loginUserSessionUseCase.Execute(r.Context(), req.Email, req.Password, req.RememberMe)
+	if err != nil {
+		h.writeLoginError(w, err)
+		return
+	}
+
+	h.cookies.setSessionCookie(w, result.Token, result.Session.ExpiresAt, result.Session.Persistent)
+	writeSuccess(w, http.StatusCreated, LoginUserSessionSuccessResponse{SessionExpiresAt: result.Session.ExpiresAt})
+}
+
+// writeLoginError writes the response of a login that didn't issue tokens, either because it was refused
+// or because the user must first complete a second factor, change their password or restore their account
+func (h *AuthHandler) writeLoginError(w http.ResponseWriter, err error) {
+	var changeRequired *usecase.PasswordChangeRequiredError
+	var pendingDeletion *usecase.AccountPendingDeletionError
+	var mfaRequired *usecase.MFARequiredError
+	if errors.Is(err, usecase.ErrInvalidCredentials) {
+		writeError(w, http.StatusUnauthorized, usecase.ErrInvalidCredentials.Error())
+	} else if errors.Is(err, usecase.ErrPasswordResetRequired) {
+		writeError(w, http.StatusForbidden, usecase.ErrPasswordResetRequired.Error())
+	} else if errors.Is(err, usecase.ErrAccountUnavailable) {
+		writeError(w, http.StatusForbidden, usecase.ErrAccountUnavailable.Error())
+	} else if errors.Is(err, usecase.ErrLoginBlocked) {
+		writeError(w, http.StatusForbidden, usecase.ErrLoginBlocked.Error())
+	} else if errors.Is(err, usecase.ErrTooManyMFAChallenges) {
+		writeError(w, http.StatusTooManyRequests, usecase.ErrTooManyMFAChallenges.Error())
+	} else if errors.As(err, &changeRequired) {
+		writePasswordChangeRequired(w, changeRequired)
+	} else if errors.As(err, &pendingDeletion) {
+		writeAccountPendingDeletion(w, pendingDeletion)
+	} else if errors.As(err, &mfaRequired) {
+		writeMFARequired(w, mfaRequired)
+	} else {
+		h.logger.Error("Failed to log in : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+	}
+}
+
+// Logout godoc
+// @Summary		Logs out a user
+// @Description Ends the session and deletes the remember token sent in cookies (web) or the X-Remember-Token header (non-web), then clears the cookies
+// @Tags		auth
+// @Produce		json
+// @Param        X-Remember-Token header string false "Remember Me Token for non-web clients"
+// @Param        X-CSRF-Token header string false "CSRF token from /api/v1/auth/csrf, required with cookies"
+// @Success      200 {object} SuccessResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionToken := ""
+	if cookie, err := r.Cookie(SessionCookieName); err == nil {
+		sessionToken = cookie.Value
+	}
+
+	rememberToken := r.Header.Get("X-Remember-Token")
+	if cookie, err := r.Cookie(RememberCookieName); err == nil {
+		rememberToken = cookie.Value
+	}
+
+	if err := h.logoutUseCase.Execute(r.Context(), sessionToken, rememberToken); err != nil {
+		h.logger.Error("Failed to logout : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	h.cookies.clearSessionCookie(w)
+	h.cookies.clearRememberCookie(w)
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "logged out"})
+}
+
 // RefreshToken godoc
 // @Summary		Refreshes a user's session
 // @Description Uses a remember token to generate a new JWT and a new remember token. The token can be provided via a cookie (for web) or an X-Remember-Token header (for non-web client)
 // @Tags		auth
 // @produce		json
 // @Param        X-Remember-Token header string false "Remember Me Token for non-web clients"
+// @Param        X-CSRF-Token header string false "CSRF token from /api/v1/auth/csrf, required with cookies"
 // @Success      200 {object} SuccessResponse{data=RefreshTokenResponse}
 // @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      403 {object} FailResponse{data=PasswordChangeRequiredResponse} "Password has expired, change it with the restricted token"
 // @Failure      500 {object} ErrorResponse
 // @Router       /api/v1/auth/refresh [post]
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	// Get token from cookie (web)
 	rawToken := ""
-	cookie, err := r.Cookie("remember_token")
+	cookie, err := r.Cookie(RememberCookieName)
 	if err == nil {
 		rawToken = cookie.Value
 	}
@@ -224,13 +413,24 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	// Call the use case to perform the refresh logic
 	result, err := h.refreshTokenUseCase.Execute(r.Context(), rawToken)
 	if err != nil {
-		clearRememberCookie(w)
+		h.cookies.clearRememberCookie(w)
+		var changeRequired *usecase.PasswordChangeRequiredError
+		if errors.As(err, &changeRequired) {
+			writePasswordChangeRequired(w, changeRequired)
+			return
+		}
+
+		if errors.Is(err, usecase.ErrAccountUnavailable) {
+			writeError(w, http.StatusForbidden, usecase.ErrAccountUnavailable.Error())
+			return
+		}
+
 		writeError(w, http.StatusUnauthorized, usecase.ErrInvalidToken.Error())
 		return
 	}
 
 	// For web client, set the new remember token in a new cookie
-	setRememberCookie(w, result.NewRememberToken)
+	h.cookies.setRememberCookie(w, result.NewRememberToken)
 
 	// For all clients: Send the new JWT and new remember token in the response body
 	response := RefreshTokenResponse{
@@ -241,6 +441,110 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	writeSuccess(w, http.StatusOK, response)
 }
 
+// Reauthenticate godoc
+// @Summary		Reauthenticates the current user
+// @Description Confirms the password of the logged-in user before a sensitive action, answering a 401 insufficient_user_authentication challenge.
+// @Description Token clients get a new access token with a fresh auth_time, session users keep their session with a rotated session cookie.
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Security	ApiKeyAuth
+// @Param		X-CSRF-Token header string false "CSRF token from /api/v1/auth/csrf, required with cookies"
+// @Param		credentials body ReauthenticateRequest true "Current password"
+// @Success      200 {object} SuccessResponse{data=ReauthenticateResponse}
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse "Personal access tokens, delegated and impersonation tokens can't be reauthenticated"
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/v1/auth/reauthenticate [post]
+func (h *AuthHandler) Reauthenticate(w http.ResponseWriter, r *http.Request) {
+	principal, err := GetPrincipalFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req ReauthenticateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	result, err := h.reauthenticateUseCase.Execute(r.Context(), principal, req.Password)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			writeError(w, http.StatusUnauthorized, usecase.ErrInvalidCredentials.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrReauthenticationNotAllowed) {
+			writeError(w, http.StatusForbidden, usecase.ErrReauthenticationNotAllowed.Error())
+			return
+		}
+
+		h.logger.Error("Failed to reauthenticate : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	if result.Session != nil {
+		h.cookies.setSessionCookie(w, result.SessionToken, result.Session.ExpiresAt, result.Session.Persistent)
+	}
+
+	writeSuccess(w, http.StatusOK, ReauthenticateResponse{AccessToken: result.AccessToken, AuthTime: result.AuthTime})
+}
+
+// SecureAccountPage godoc
+// @Summary		Confirm securing the account after an unrecognized sign-in
+// @Description Landing page of the "secure my account" link sent when a new device signs in. It asks the user to confirm, nothing is changed until the form is posted.
+// @Tags		auth
+// @Produce		html
+// @Param		token query string true "Secure account token"
+// @Success      200 {string} string "Confirmation page"
+// @Router       /api/v1/auth/secure-account [get]
+func (h *AuthHandler) SecureAccountPage(w http.ResponseWriter, r *http.Request) {
+	writeConfirmationPage(w, confirmationPageData{
+		Title:   "Secure your account",
+		Message: "If you didn't sign in from a new device, you will be signed out everywhere and must reset your password.",
+		Action:  r.URL.Path,
+		Button:  "Secure my account",
+		Token:   r.URL.Query().Get("token"),
+	})
+}
+
+// SecureAccount godoc
+// @Summary		Secure the account after an unrecognized sign-in
+// @Description Uses the token of the "secure my account" link sent when a new device signs in. The user is signed out everywhere and must reset their password.
+// @Description The token can only be used once.
+// @Tags		auth
+// @Accept		x-www-form-urlencoded
+// @Produce		json
+// @Param		token formData string true "Secure account token"
+// @Success      200 {object} SuccessResponse
+// @Failure      400 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/v1/auth/secure-account [post]
+func (h *AuthHandler) SecureAccount(w http.ResponseWriter, r *http.Request) {
+	token := r.PostFormValue("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, ErrTokenNotFound.Error())
+		return
+	}
+
+	if err := h.secureAccountUseCase.Execute(r.Context(), token); err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusBadRequest, usecase.ErrInvalidToken.Error())
+			return
+		}
+
+		h.logger.Error("Failed to secure account : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "you have been signed out of all devices, please reset your password"})
+}
+
 // VerifyEmail godoc
 // @Summary		Verifies a user's email
 // @Description Uses a verification token from a query parameter to verify a user's email and log them in
@@ -249,6 +553,9 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 // @Param		token query string true "Email verification token"
 // @Success     200 {object} SuccessResponse{data=LoginUserSuccessResponse}
 // @Failure     400 {object} ErrorResponse
+// @Failure     403 {object} ErrorResponse
+// @Failure     403 {object} FailResponse{data=MFARequiredResponse} "MFA is enabled, complete the login with the code sent to the user and the restricted token"
+// @Failure     429 {object} ErrorResponse "Too many logins waiting for a second factor"
 // @Failure     500 {object} ErrorResponse
 // @Router      /api/v1/auth/verify [get]
 func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
@@ -261,9 +568,19 @@ func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 
 	// Call the use case
 	result, err := h.verifyEmailUseCase.Execute(r.Context(), token)
+
+	var mfaRequired *usecase.MFARequiredError
 	if err != nil {
 		if errors.Is(err, usecase.ErrInvalidCredentials) {
 			writeError(w, http.StatusUnauthorized, usecase.ErrInvalidCredentials.Error())
+		} else if errors.Is(err, usecase.ErrAccountUnavailable) {
+			writeError(w, http.StatusForbidden, usecase.ErrAccountUnavailable.Error())
+		} else if errors.Is(err, usecase.ErrLoginBlocked) {
+			writeError(w, http.StatusForbidden, usecase.ErrLoginBlocked.Error())
+		} else if errors.Is(err, usecase.ErrTooManyMFAChallenges) {
+			writeError(w, http.StatusTooManyRequests, usecase.ErrTooManyMFAChallenges.Error())
+		} else if errors.As(err, &mfaRequired) {
+			writeMFARequired(w, mfaRequired)
 		} else {
 			writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
 		}
@@ -271,7 +588,7 @@ func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Set the remember me cookie and return the JWT
-	setRememberCookie(w, result.RememberToken)
+	h.cookies.setRememberCookie(w, result.RememberToken)
 	response := LoginUserSuccessResponse{
 		AccessToken:   result.AccessToken,
 		RememberToken: result.RememberToken,
@@ -338,17 +655,277 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if errors.Is(err, usecase.ErrPasswordTooShort) {
-			writeFail(w, http.StatusBadRequest, map[string][]string{"password": {usecase.ErrPasswordTooShort.Error()}})
+		if errors.Is(err, usecase.ErrEmptyPassword) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"password": {usecase.ErrEmptyPassword.Error()}})
+			return
+		}
+
+		if errors.Is(err, usecase.ErrWeakPassword) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"password": passwordPolicyMessages(err)})
+			return
+		}
+
+		if errors.Is(err, usecase.ErrPasswordReused) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"password": {usecase.ErrPasswordReused.Error()}})
 			return
 		}
 
+		h.logger.Error("Failed to reset password : ", "error", err)
 		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
 	}
 
 	writeSuccess(w, http.StatusOK, map[string]string{"message": "a password has been reset successfully"})
 }
 
+// CheckPassword godoc
+// @Summary		Check password strength
+// @Description Evaluate a password against the password policy without saving it. Name and email are optional and let the check reject passwords built from them.
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Param		password body CheckPasswordRequest true "Password to check"
+// @Success 200 {object} SuccessResponse{data=usecase.PasswordCheckResult}
+// @Failure 400 {object} ErrorResponse
+// @Router	/api/v1/auth/password/check [post]
+func (h *AuthHandler) CheckPassword(w http.ResponseWriter, r *http.Request) {
+	var req CheckPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, h.checkPasswordUseCase.Execute(req.Password, req.Name, req.Email))
+}
+
+// ChangeExpiredPassword godoc
+// @Summary		Change an expired password
+// @Description Set a new password with the restricted token returned by a login with an expired password, then log in
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Param		Authorization header string true "Bearer password change token"
+// @Param		password body ChangeExpiredPasswordRequest true "New password"
+// @Success 200 {object} SuccessResponse{data=LoginUserSuccessResponse}
+// @Failure 400 {object} FailResponse{data=ResetPasswordFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router	/api/v1/auth/password/change [post]
+func (h *AuthHandler) ChangeExpiredPassword(w http.ResponseWriter, r *http.Request) {
+	// The header should be in the format "Bearer <token>"
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		writeError(w, http.StatusUnauthorized, ErrMalformedAuthHeader.Error())
+		return
+	}
+
+	var req ChangeExpiredPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	result, err := h.changeExpiredPasswordUseCase.Execute(r.Context(), token, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrEmptyPassword) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"password": {usecase.ErrEmptyPassword.Error()}})
+			return
+		}
+
+		if errors.Is(err, usecase.ErrWeakPassword) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"password": passwordPolicyMessages(err)})
+			return
+		}
+
+		if errors.Is(err, usecase.ErrPasswordReused) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"password": {usecase.ErrPasswordReused.Error()}})
+			return
+		}
+
+		if errors.Is(err, usecase.ErrAccountUnavailable) {
+			writeError(w, http.StatusForbidden, usecase.ErrAccountUnavailable.Error())
+			return
+		}
+
+		h.logger.Error("Failed to change expired password : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, LoginUserSuccessResponse{AccessToken: result.AccessToken})
+}
+
+// writePasswordChangeRequired responds to a login with an expired password
+func writePasswordChangeRequired(w http.ResponseWriter, changeRequired *usecase.PasswordChangeRequiredError) {
+	writeFail(w, http.StatusForbidden, PasswordChangeRequiredResponse{
+		Message:             changeRequired.Error(),
+		PasswordChangeToken: changeRequired.Token,
+		ExpiresAt:           changeRequired.ExpiresAt,
+	})
+}
+
+// VerifyMFA godoc
+// @Summary		Complete a login with a second factor
+// @Description Check the code sent to the user with the restricted token returned by a risky login, then log in.
+// @Description The login ends like the one it completes: tokens, or an HttpOnly session cookie with use_session.
+// @Description With trust_device, the device skips the second factor until the trusted device token expires.
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Param		Authorization header string true "Bearer mfa token"
+// @Param		code body VerifyMFARequest true "Code sent to the user"
+// @Success 200 {object} SuccessResponse{data=VerifyMFASuccessResponse}
+// @Success 201 {object} SuccessResponse{data=LoginUserSessionSuccessResponse}
+// @Failure 400 {object} FailResponse{data=VerifyCodeFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} FailResponse{data=PasswordChangeRequiredResponse} "Password has expired, change it with the restricted token"
+// @Failure 403 {object} FailResponse{data=AccountPendingDeletionResponse} "Account is scheduled for deletion, restore it with the restricted token"
+// @Failure 403 {object} ErrorResponse "Account has been disabled or suspended"
+// @Failure 500 {object} ErrorResponse
+// @Router	/api/v1/auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	// The header should be in the format "Bearer <token>"
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		writeError(w, http.StatusUnauthorized, ErrMalformedAuthHeader.Error())
+		return
+	}
+
+	var req VerifyMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if req.Code == "" {
+		writeFail(w, http.StatusBadRequest, map[string][]string{"code": {"code is required"}})
+		return
+	}
+
+	result, err := h.verifyMFAChallengeUseCase.Execute(r.Context(), token, req.Code, req.TrustDevice)
+
+	var changeRequired *usecase.PasswordChangeRequiredError
+	var pendingDeletion *usecase.AccountPendingDeletionError
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrInvalidMFACode) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"code": {usecase.ErrInvalidMFACode.Error()}})
+			return
+		}
+
+		if errors.Is(err, usecase.ErrAccountUnavailable) {
+			writeError(w, http.StatusForbidden, usecase.ErrAccountUnavailable.Error())
+			return
+		}
+
+		if errors.As(err, &changeRequired) {
+			writePasswordChangeRequired(w, changeRequired)
+			return
+		}
+
+		if errors.As(err, &pendingDeletion) {
+			writeAccountPendingDeletion(w, pendingDeletion)
+			return
+		}
+
+		h.logger.Error("Failed to verify mfa challenge : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	if result.TrustedDevice != nil {
+		h.cookies.setTrustedDeviceCookie(w, result.TrustedDevice.Token, result.TrustedDevice.ExpiresAt)
+	}
+
+	if result.Session != nil {
+		h.cookies.setSessionCookie(w, result.Session.Token, result.Session.Session.ExpiresAt, result.Session.Session.Persistent)
+		writeSuccess(w, http.StatusCreated, LoginUserSessionSuccessResponse{SessionExpiresAt: result.Session.Session.ExpiresAt})
+		return
+	}
+
+	response := VerifyMFASuccessResponse{AccessToken: result.Login.AccessToken}
+
+	if result.Login.RememberToken != "" {
+		h.cookies.setRememberCookie(w, result.Login.RememberToken) // for web client
+		response.RememberToken = result.Login.RememberToken        // for non-web client
+	}
+
+	if result.TrustedDevice != nil {
+		response.TrustedDeviceToken = result.TrustedDevice.Token // for non-web client
+	}
+
+	writeSuccess(w, http.StatusOK, response)
+}
+
+// writeMFARequired responds to a risky login that needs a second factor
+func writeMFARequired(w http.ResponseWriter, mfaRequired *usecase.MFARequiredError) {
+	writeFail(w, http.StatusForbidden, MFARequiredResponse{
+		Message:   mfaRequired.Error(),
+		MFAToken:  mfaRequired.Token,
+		Channel:   mfaRequired.Channel,
+		ExpiresAt: mfaRequired.ExpiresAt,
+	})
+}
+
+// RestoreAccount godoc
+// @Summary		Restore an account scheduled for deletion
+// @Description Cancel the deletion of an account with the restricted token returned by a login during the grace period, then log in
+// @Tags		auth
+// @Produce		json
+// @Param		Authorization header string true "Bearer account restore token"
+// @Success 200 {object} SuccessResponse{data=LoginUserSuccessResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router	/api/v1/auth/account/restore [post]
+func (h *AuthHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	// The header should be in the format "Bearer <token>"
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		writeError(w, http.StatusUnauthorized, ErrMalformedAuthHeader.Error())
+		return
+	}
+
+	result, err := h.restoreAccountUseCase.Execute(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrAccountUnavailable) {
+			writeError(w, http.StatusForbidden, usecase.ErrAccountUnavailable.Error())
+			return
+		}
+
+		h.logger.Error("Failed to restore account : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, LoginUserSuccessResponse{AccessToken: result.AccessToken})
+}
+
+// writeAccountPendingDeletion responds to a login to an account scheduled for deletion
+func writeAccountPendingDeletion(w http.ResponseWriter, pendingDeletion *usecase.AccountPendingDeletionError) {
+	writeFail(w, http.StatusForbidden, AccountPendingDeletionResponse{
+		Message:      pendingDeletion.Error(),
+		RestoreToken: pendingDeletion.Token,
+		ExpiresAt:    pendingDeletion.ExpiresAt,
+		PurgeAt:      pendingDeletion.PurgeAt,
+	})
+}
+
 // RequestVerificationCode godoc
 // @Summary		Request a verification code
 // @Description Send a 6-digit verification code to email
@@ -444,7 +1021,7 @@ func (h *AuthHandler) VerifyCode(w http.ResponseWriter, r *http.Request) {
 
 // RequestLoginOTP godoc
 // @Summary		Request a login OTP
-// @Description Send a 6-digit login OTP to email
+// @Description Send a 6-digit login OTP to email, or by sms or voice call to the verified phone number of the account
 // @Tags		auth
 // @Produce		json
 // @Param		email body RequestLoginOTPRequest true "Email to verify"
@@ -459,7 +1036,7 @@ func (h *AuthHandler) RequestLoginOTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.requestLoginOTPUseCase.Execute(r.Context(), req.Email)
+	err := h.requestLoginOTPUseCase.Execute(r.Context(), req.Email, req.Channel)
 	if err != nil {
 		validationErrors := make(map[string][]string)
 		if errors.Is(err, usecase.ErrEmptyEmail) {
@@ -470,6 +1047,10 @@ func (h *AuthHandler) RequestLoginOTP(w http.ResponseWriter, r *http.Request) {
 			validationErrors["email"] = append(validationErrors["email"], usecase.ErrInvalidEmail.Error())
 		}
 
+		if errors.Is(err, usecase.ErrInvalidOTPChannel) {
+			validationErrors["channel"] = append(validationErrors["channel"], usecase.ErrInvalidOTPChannel.Error())
+		}
+
 		if len(validationErrors) > 0 {
 			writeFail(w, http.StatusBadRequest, validationErrors)
 			return
@@ -477,35 +1058,63 @@ func (h *AuthHandler) RequestLoginOTP(w http.ResponseWriter, r *http.Request) {
 
 		h.logger.Error("Failed to send OTP login : ", "error", err)
 		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
 	}
+
+	response := RequestLoginOTPSuccessResponse{
+		Message: "If the account exists, a login code has been sent",
+	}
+
+	writeSuccess(w, http.StatusAccepted, response)
 }
 
-// Helper function
-func setRememberCookie(w http.ResponseWriter, rememberToken string) {
-	cookie := http.Cookie{
-		Name:     "remember_token",
-		Value:    rememberToken,
-		Expires:  time.Now().Add(30 * 24 * time.Hour),
-		HttpOnly: true,
-		Secure:   true,
-		Path:     "/",
-		SameSite: http.SameSiteLaxMode,
+// VerifyLoginOTP godoc
+// @Summary		Log in with a login OTP
+// @Description Check the code sent by /api/v1/auth/otp/request to the email or phone number of the account, then log in.
+// @Description Each email gets a limited number of attempts, asking for a new code doesn't reset them.
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Param		code body VerifyLoginOTPRequest true "Email and code"
+// @Success 200 {object} SuccessResponse{data=LoginUserSuccessResponse}
+// @Failure 400 {object} FailResponse{data=VerifyCodeFailResponse}
+// @Failure 403 {object} FailResponse{data=PasswordChangeRequiredResponse} "Password has expired, change it with the restricted token"
+// @Failure 403 {object} FailResponse{data=AccountPendingDeletionResponse} "Account is scheduled for deletion, restore it with the restricted token"
+// @Failure 403 {object} FailResponse{data=MFARequiredResponse} "Risky login or MFA enabled, complete it with the code sent to the user and the restricted token"
+// @Failure 403 {object} ErrorResponse "Account is unavailable or the login has been blocked"
+// @Failure 429 {object} ErrorResponse "Too many logins waiting for a second factor"
+// @Failure 500 {object} ErrorResponse
+// @Router	/api/v1/auth/otp/verify [post]
+func (h *AuthHandler) VerifyLoginOTP(w http.ResponseWriter, r *http.Request) {
+	var req VerifyLoginOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if req.Code == "" {
+		writeFail(w, http.StatusBadRequest, map[string][]string{"code": {"code is required"}})
+		return
 	}
 
-	http.SetCookie(w, &cookie)
+	result, err := h.verifyLoginOTPUseCase.Execute(r.Context(), req.Email, req.Code, req.RememberMe)
 
-}
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidVerificationCode) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"code": {usecase.ErrInvalidVerificationCode.Error()}})
+		} else {
+			h.writeLoginError(w, err)
+		}
+
+		return
+	}
 
-func clearRememberCookie(w http.ResponseWriter) {
-	cookie := http.Cookie{
-		Name:     "remember_token",
-		Value:    "",
-		Expires:  time.Now(),
-		HttpOnly: true,
-		Secure:   true,
-		Path:     "/",
-		SameSite: http.SameSiteLaxMode,
+	response := LoginUserSuccessResponse{AccessToken: result.AccessToken}
+
+	if result.RememberToken != "" {
+		h.cookies.setRememberCookie(w, result.RememberToken) // for web client
+		response.RememberToken = result.RememberToken        // for non-web client
 	}
 
-	http.SetCookie(w, &cookie)
+	writeSuccess(w, http.StatusOK, response)
 }
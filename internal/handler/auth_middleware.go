@@ -1,14 +1,16 @@
 package handler
 
 import (
+	"auth/internal/domain"
 	"auth/internal/usecase"
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
-	"os"
 	"strings"
-
-	"github.com/golang-jwt/jwt/v5"
+	"time"
 )
 
 // Define a custom key type to avoid collisions in context
@@ -17,13 +19,73 @@ type contextKey string
 // UserIDContextKey is the key for the user ID in the context
 const UserIDContextKey = contextKey("UserID")
 
-// AuthMiddleware create a new Chi middleware for JWT authentication
-func AuthMiddleware(next http.Handler) http.Handler {
+// PrincipalContextKey is the key for the authenticated principal in the context
+const PrincipalContextKey = contextKey("Principal")
+
+// AuthMiddleware represents the authentication middleware object
+type AuthMiddleware struct {
+	logger                                 *slog.Logger
+	cookies                                CookiePolicy
+	authenticateAccessTokenUseCase         *usecase.AuthenticateAccessTokenUseCase
+	authenticatePersonalAccessTokenUseCase *usecase.AuthenticatePersonalAccessTokenUseCase
+	authenticateSessionUseCase             *usecase.AuthenticateSessionUseCase
+	checkUserStatusUseCase                 *usecase.CheckUserStatusUseCase
+	checkServiceAccountStatusUseCase       *usecase.CheckServiceAccountStatusUseCase
+	auditImpersonatedRequestUseCase        *usecase.AuditImpersonatedRequestUseCase
+}
+
+// NewAuthMiddleware creates a new authentication middleware object
+func NewAuthMiddleware(
+	logger *slog.Logger,
+	cookies CookiePolicy,
+	authenticateAccessTokenUC *usecase.AuthenticateAccessTokenUseCase,
+	authenticatePersonalAccessTokenUC *usecase.AuthenticatePersonalAccessTokenUseCase,
+	authenticateSessionUC *usecase.AuthenticateSessionUseCase,
+	checkUserStatusUC *usecase.CheckUserStatusUseCase,
+	checkServiceAccountStatusUC *usecase.CheckServiceAccountStatusUseCase,
+	auditImpersonatedRequestUC *usecase.AuditImpersonatedRequestUseCase,
+) *AuthMiddleware {
+	return &AuthMiddleware{
+		logger:                                 logger,
+		cookies:                                cookies,
+		authenticateAccessTokenUseCase:         authenticateAccessTokenUC,
+		authenticatePersonalAccessTokenUseCase: authenticatePersonalAccessTokenUC,
+		authenticateSessionUseCase:             authenticateSessionUC,
+		checkUserStatusUseCase:                 checkUserStatusUC,
+		checkServiceAccountStatusUseCase:       checkServiceAccountStatusUC,
+		auditImpersonatedRequestUseCase:        auditImpersonatedRequestUC,
+	}
+}
+
+// Handle is the Chi middleware authenticating the bearer token, which is either a JWT or a personal access token,
+// or the session cookie when no Authorization header is sent.
+// The user ID is only added to the context for user principals, service principals are available through the principal.
+func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Get the Authorization header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			writeError(w, http.StatusUnauthorized, ErrMissingAuthHeader.Error())
+			cookie, err := r.Cookie(SessionCookieName)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, ErrMissingAuthHeader.Error())
+				return
+			}
+
+			principal, err := m.authenticateSession(w, r, cookie.Value)
+			if principal == nil {
+				// The browser already holds the cookie of a rotated session, clearing it would sign the user out
+				if !errors.Is(err, usecase.ErrSessionRotated) {
+					m.cookies.clearSessionCookie(w)
+				}
+				writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+				return
+			}
+
+			if !m.checkUserStatus(w, r, principal) {
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(contextWithPrincipal(r.Context(), principal)))
 			return
 		}
 
@@ -35,45 +97,224 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		tokenString := headerPorts[1]
-		secretKey := []byte(os.Getenv("SECRET_KEY"))
 
-		// Parse and validate the token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Ensure the signing method is what we expect (HMAC)
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-
-			return secretKey, nil
-		})
+		var principal *domain.Principal
+		if strings.HasPrefix(tokenString, domain.PersonalAccessTokenPrefix) {
+			principal = m.authenticatePersonalAccessToken(r, tokenString)
+		} else {
+			principal, _ = m.authenticateAccessTokenUseCase.Execute(tokenString)
+		}
 
-		if err != nil || !token.Valid {
+		if principal == nil {
 			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
 			return
 		}
 
-		// Extract the user ID from the token c laims
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+		if !m.checkUserStatus(w, r, principal) {
 			return
 		}
 
-		// The 'sub' in claim hold our user ID. JWT stores numbers as float64
-		userIDFloat, ok := claims["sub"].(float64)
-		if !ok {
-			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+		if principal.IsImpersonated() && !m.auditImpersonatedRequest(w, r, principal) {
 			return
 		}
 
-		// Convert float to integer
-		userID := int64(userIDFloat)
+		// Call the next handler in the chain with the new context
+		next.ServeHTTP(w, r.WithContext(contextWithPrincipal(r.Context(), principal)))
+	})
+}
 
-		// Add user ID to request context
-		ctx := context.WithValue(r.Context(), UserIDContextKey, userID)
+// checkUserStatus rejects user principals whose account has been blocked or deleted and loads their current roles.
+// Service principals are rejected when the owner of the service account is no longer active.
+// It writes the error response and returns false when the request must not proceed.
+func (m *AuthMiddleware) checkUserStatus(w http.ResponseWriter, r *http.Request, principal *domain.Principal) bool {
+	var user *domain.User
+	var err error
+	if principal.IsUser() {
+		user, err = m.checkUserStatusUseCase.Execute(r.Context(), principal.ID)
+	} else {
+		_, err = m.checkServiceAccountStatusUseCase.Execute(r.Context(), principal.ID)
+	}
+	if err != nil {
+		if errors.Is(err, usecase.ErrAccountUnavailable) {
+			writeError(w, http.StatusForbidden, usecase.ErrAccountUnavailable.Error())
+			return false
+		}
 
-		// Call the next handler in the chain with the new context
-		next.ServeHTTP(w, r.WithContext(ctx))
+		if errors.Is(err, usecase.ErrUserUnauthorized) {
+			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+			return false
+		}
+
+		m.logger.Error("Failed to check user status", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return false
+	}
+
+	if user != nil {
+		principal.Roles = user.Roles
+	}
+
+	return true
+}
+
+// auditImpersonatedRequest records a request made with an impersonation token, rejecting it once the impersonation has ended.
+// It writes the error response and returns false when the request must not proceed.
+func (m *AuthMiddleware) auditImpersonatedRequest(w http.ResponseWriter, r *http.Request, principal *domain.Principal) bool {
+	err := m.auditImpersonatedRequestUseCase.Execute(r.Context(), principal, usecase.ImpersonatedRequest{
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		IPAddress: clientIP(r),
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, ErrInvalidToken.Error())
+			return false
+		}
+
+		// An unaudited request is not let through
+		m.logger.Error("Failed to audit impersonated request", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return false
+	}
+
+	return true
+}
+
+// contextWithPrincipal adds the principal and, for users, the user ID to the request context
+func contextWithPrincipal(ctx context.Context, principal *domain.Principal) context.Context {
+	ctx = context.WithValue(ctx, PrincipalContextKey, principal)
+	if principal.IsUser() {
+		ctx = context.WithValue(ctx, UserIDContextKey, principal.ID)
+	}
+
+	return ctx
+}
+
+// authenticateSession resolves the principal of a session cookie, replacing the cookie when the session was rotated
+func (m *AuthMiddleware) authenticateSession(w http.ResponseWriter, r *http.Request, sessionToken string) (*domain.Principal, error) {
+	result, err := m.authenticateSessionUseCase.Execute(r.Context(), sessionToken)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrInvalidToken) && !errors.Is(err, usecase.ErrSessionRotated) {
+			m.logger.Error("Failed to authenticate session", "error", err)
+		}
+
+		return nil, err
+	}
+
+	if result.RotatedToken != "" {
+		m.cookies.setSessionCookie(w, result.RotatedToken, result.Session.ExpiresAt, result.Session.Persistent)
+	}
+
+	// Sessions are interactive, like user JWTs
+	return &domain.Principal{
+		Type:      domain.PrincipalTypeUser,
+		ID:        result.Session.UserID,
+		Scopes:    []string{domain.ScopeAll},
+		SessionID: result.Session.ID,
+		AuthTime:  result.Session.AuthTime,
+		AMR:       result.Session.AMR,
+	}, nil
+}
+
+// authenticatePersonalAccessToken resolves the principal of a personal access token
+func (m *AuthMiddleware) authenticatePersonalAccessToken(r *http.Request, tokenString string) *domain.Principal {
+	token, err := m.authenticatePersonalAccessTokenUseCase.Execute(r.Context(), tokenString, clientIP(r))
+	if err != nil {
+		if !errors.Is(err, usecase.ErrInvalidToken) {
+			m.logger.Error("Failed to authenticate personal access token", "error", err)
+		}
+
+		return nil
+	}
+
+	return &domain.Principal{
+		Type:         domain.PrincipalTypeUser,
+		ID:           token.UserID,
+		Scopes:       token.Scopes,
+		CredentialID: token.ID,
+	}
+}
+
+// RequireScope creates a Chi middleware rejecting principals that weren't granted the scope
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := GetPrincipalFromContext(r.Context())
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+				return
+			}
+
+			if !principal.HasScope(scope) {
+				writeError(w, http.StatusForbidden, ErrInsufficientScope.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole creates a Chi middleware rejecting principals that weren't granted the role
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := GetPrincipalFromContext(r.Context())
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+				return
+			}
+
+			if !principal.HasRole(role) {
+				writeError(w, http.StatusForbidden, ErrInsufficientRole.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireFreshAuth creates a Chi middleware rejecting users who didn't authenticate within maxAge,
+// or, when methods are given, didn't use any of them. Clients answer the challenge with a reauthentication (RFC 9470).
+func RequireFreshAuth(maxAge time.Duration, methods ...string) func(http.Handler) http.Handler {
+	challenge := fmt.Sprintf(`Bearer error="insufficient_user_authentication", error_description=%q, max_age=%d`,
+		ErrReauthenticationRequired.Error(), int64(maxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := GetPrincipalFromContext(r.Context())
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+				return
+			}
+
+			if !principal.IsAuthenticatedWithin(maxAge) || (len(methods) > 0 && !principal.HasAMR(methods...)) {
+				w.Header().Set("WWW-Authenticate", challenge)
+				writeError(w, http.StatusUnauthorized, ErrReauthenticationRequired.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DenyImpersonation is the Chi middleware keeping administrators acting as a user away from the security settings of the account
+func DenyImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := GetPrincipalFromContext(r.Context())
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+			return
+		}
+
+		if principal.IsImpersonated() {
+			writeError(w, http.StatusForbidden, ErrImpersonationNotAllowed.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r)
 	})
 }
 
@@ -86,3 +327,23 @@ func GetUserIDFromContext(ctx context.Context) (int64, error) {
 
 	return userID, nil
 }
+
+// GetPrincipalFromContext is a helper function to safely retrieve the authenticated principal from the context
+func GetPrincipalFromContext(ctx context.Context) (*domain.Principal, error) {
+	principal, ok := ctx.Value(PrincipalContextKey).(*domain.Principal)
+	if !ok {
+		return nil, usecase.ErrInvalidCredentials
+	}
+
+	return principal, nil
+}
+
+// clientIP returns the IP address of the client that sent the request
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
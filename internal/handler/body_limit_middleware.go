@@ -0,0 +1,17 @@
+package handler
+
+import "net/http"
+
+// LimitRequestBody is the Chi middleware capping the size of request bodies.
+// Reading past maxBytes fails, so handlers decoding the body answer with a bad request.
+func LimitRequestBody(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
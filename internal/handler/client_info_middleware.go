@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"auth/internal/domain"
+	"auth/internal/usecase"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+)
+
+// DeviceIDHeaderName is the header identifying the device of clients that don't keep cookies, such as mobile apps
+const DeviceIDHeaderName = "X-Device-ID"
+
+// TrustedDeviceHeaderName is the header carrying the trusted device token of clients that don't keep cookies
+const TrustedDeviceHeaderName = "X-Trusted-Device-Token"
+
+// Device IDs chosen by clients must be long enough not to be guessed and short enough to store
+const (
+	minDeviceIDLength = 16
+	maxDeviceIDLength = 128
+)
+
+// ClientInfoMiddleware represents the middleware identifying the client and device a request comes from
+type ClientInfoMiddleware struct {
+	cookies CookiePolicy
+}
+
+// NewClientInfoMiddleware creates a new client info middleware object
+func NewClientInfoMiddleware(cookies CookiePolicy) *ClientInfoMiddleware {
+	return &ClientInfoMiddleware{cookies: cookies}
+}
+
+// Handle is the Chi middleware adding the client info to the request context for the use cases signing users in.
+// The device ID comes from the X-Device-ID header or the device cookie. Devices without one get a new ID,
+// set as a long-lived cookie for browsers and returned in the X-Device-ID response header for other clients.
+// The trusted device token comes from the trusted device cookie or the X-Trusted-Device-Token header.
+func (m *ClientInfoMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deviceID := r.Header.Get(DeviceIDHeaderName)
+		if deviceID == "" {
+			if cookie, err := r.Cookie(DeviceCookieName); err == nil {
+				deviceID = cookie.Value
+			}
+		}
+
+		if len(deviceID) < minDeviceIDLength || len(deviceID) > maxDeviceIDLength {
+			b := make([]byte, 32)
+			if _, err := rand.Read(b); err != nil {
+				writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+				return
+			}
+
+			deviceID = base64.RawURLEncoding.EncodeToString(b)
+			m.cookies.setDeviceCookie(w, deviceID)
+			w.Header().Set(DeviceIDHeaderName, deviceID)
+		}
+
+		trustedDeviceToken := r.Header.Get(TrustedDeviceHeaderName)
+		if cookie, err := r.Cookie(TrustedDeviceCookieName); err == nil {
+			trustedDeviceToken = cookie.Value
+		}
+
+		info := domain.ClientInfo{
+			IPAddress:          clientIP(r),
+			UserAgent:          r.UserAgent(),
+			DeviceID:           deviceID,
+			TrustedDeviceToken: trustedDeviceToken,
+		}
+
+		next.ServeHTTP(w, r.WithContext(usecase.ContextWithClientInfo(r.Context(), info)))
+	})
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+)
+
+// confirmationPage asks the user to confirm the action of an emailed link.
+// Links are opened by mail scanners and previews, so the action itself is only performed by the form submission.
+var confirmationPage = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">{{.Button}}</button>
+</form>
+</body>
+</html>`))
+
+// confirmationPageData represents the content of a confirmation page
+type confirmationPageData struct {
+	Title   string
+	Message string
+	// Action is the path the token is posted to
+	Action string
+	Button string
+	Token  string
+}
+
+// writeConfirmationPage renders a confirmation page. The token is in the URL, so the page is neither cached,
+// framed nor leaked through the Referer header.
+func writeConfirmationPage(w http.ResponseWriter, data confirmationPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; form-action 'self'; frame-ancestors 'none'")
+	w.WriteHeader(http.StatusOK)
+	if err := confirmationPage.Execute(w, data); err != nil {
+		slog.Error("Failed to render confirmation page", slog.Any("error", err))
+	}
+}
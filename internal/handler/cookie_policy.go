@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+)
+
+// Names of the cookies set by the service
+const (
+	RememberCookieName = "remember_token"
+	SessionCookieName  = "session"
+	CSRFCookieName     = "csrf_token"
+	DeviceCookieName   = "device_id"
+	// TrustedDeviceCookieName holds the signed token of a device allowed to skip the second factor
+	TrustedDeviceCookieName = "trusted_device"
+)
+
+// rememberCookieDuration matches the lifetime of remember tokens
+const rememberCookieDuration = 30 * 24 * time.Hour
+
+// deviceCookieDuration is the longest lifetime browsers allow cookies to have
+const deviceCookieDuration = 400 * 24 * time.Hour
+
+// CookiePolicy holds the attributes shared by every cookie the service sets
+type CookiePolicy struct {
+	// Domain lets the cookies be shared with sibling hosts, e.g. "example.com". Empty restricts them to the API host.
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// DefaultCookiePolicy returns the policy used when nothing is configured
+func DefaultCookiePolicy() CookiePolicy {
+	return CookiePolicy{Secure: true, SameSite: http.SameSiteLaxMode}
+}
+
+// setRememberCookie sets the remember token cookie of web clients
+func (p CookiePolicy) setRememberCookie(w http.ResponseWriter, rememberToken string) {
+	p.setCookie(w, RememberCookieName, rememberToken, time.Now().Add(rememberCookieDuration))
+}
+
+// clearRememberCookie removes the remember token cookie
+func (p CookiePolicy) clearRememberCookie(w http.ResponseWriter) {
+	p.clearCookie(w, RememberCookieName)
+}
+
+// setSessionCookie sets the session cookie. Non persistent sessions get a browser session cookie.
+func (p CookiePolicy) setSessionCookie(w http.ResponseWriter, sessionToken string, expiresAt time.Time, persistent bool) {
+	if !persistent {
+		expiresAt = time.Time{}
+	}
+
+	p.setCookie(w, SessionCookieName, sessionToken, expiresAt)
+}
+
+// clearSessionCookie removes the session cookie
+func (p CookiePolicy) clearSessionCookie(w http.ResponseWriter) {
+	p.clearCookie(w, SessionCookieName)
+}
+
+// setDeviceCookie sets the cookie identifying the browser across logins
+func (p CookiePolicy) setDeviceCookie(w http.ResponseWriter, deviceID string) {
+	p.setCookie(w, DeviceCookieName, deviceID, time.Now().Add(deviceCookieDuration))
+}
+
+// setTrustedDeviceCookie sets the cookie letting the browser skip the second factor until expiresAt
+func (p CookiePolicy) setTrustedDeviceCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
+	p.setCookie(w, TrustedDeviceCookieName, token, expiresAt)
+}
+
+func (p CookiePolicy) setCookie(w http.ResponseWriter, name string, value string, expires time.Time) {
+	cookie := http.Cookie{
+		Name:     name,
+		Value:    value,
+		Expires:  expires,
+		Domain:   p.Domain,
+		HttpOnly: true,
+		Secure:   p.Secure,
+		Path:     "/",
+		SameSite: p.SameSite,
+	}
+
+	http.SetCookie(w, &cookie)
+}
+
+func (p CookiePolicy) clearCookie(w http.ResponseWriter, name string) {
+	cookie := http.Cookie{
+		Name:     name,
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		Domain:   p.Domain,
+		HttpOnly: true,
+		Secure:   p.Secure,
+		Path:     "/",
+		SameSite: p.SameSite,
+	}
+
+	http.SetCookie(w, &cookie)
+}
+
+// setCSRFCookie sets the CSRF token cookie. It is readable by scripts, which echo it in the X-CSRF-Token header.
+func (p CookiePolicy) setCSRFCookie(w http.ResponseWriter, token string) {
+	cookie := http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    token,
+		Domain:   p.Domain,
+		Secure:   p.Secure,
+		Path:     "/",
+		SameSite: p.SameSite,
+	}
+
+	http.SetCookie(w, &cookie)
+}
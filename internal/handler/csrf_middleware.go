@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CSRFHeaderName is the header the CSRF token is echoed in
+const CSRFHeaderName = "X-CSRF-Token"
+
+// CSRFMiddleware represents the CSRF protection of cookie-authenticated routes.
+// It implements the signed double-submit cookie pattern: the token cookie must be echoed in a header,
+// and its signature binds it to the session or remember token cookie, so a token planted by a sibling host
+// or issued for another session is refused.
+type CSRFMiddleware struct {
+	cookies        CookiePolicy
+	secret         []byte
+	trustedOrigins []string
+}
+
+// NewCSRFMiddleware creates a new CSRF middleware object. Requests may come from the API host itself or a trusted origin.
+func NewCSRFMiddleware(cookies CookiePolicy, secret []byte, trustedOrigins []string) *CSRFMiddleware {
+	origins := make([]string, 0, len(trustedOrigins))
+	for _, origin := range trustedOrigins {
+		if origin = strings.TrimSuffix(strings.ToLower(origin), "/"); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	return &CSRFMiddleware{
+		cookies:        cookies,
+		secret:         secret,
+		trustedOrigins: origins,
+	}
+}
+
+// CSRFTokenResponse represent the response body for issue csrf token
+type CSRFTokenResponse struct {
+	CSRFToken string `json:"csrf_token" example:"q1b8K0rD6f4t.kH3s9aQ2mZ"`
+}
+
+// IssueToken godoc
+// @Summary Issue a CSRF token
+// @Description Set the csrf_token cookie and return its value. Browser clients echo it in the X-CSRF-Token header
+// @Description of state-changing requests authenticated by the session or remember_token cookie.
+// @Description The token is bound to those cookies, fetch a new one after signing in, refreshing or a 403.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} SuccessResponse{data=CSRFTokenResponse}
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/csrf [get]
+func (m *CSRFMiddleware) IssueToken(w http.ResponseWriter, r *http.Request) {
+	// Keep a valid token so concurrent tabs don't invalidate each other
+	binding := authCookieValue(r)
+	if cookie, err := r.Cookie(CSRFCookieName); err == nil && m.isValid(cookie.Value, binding) {
+		writeSuccess(w, http.StatusOK, CSRFTokenResponse{CSRFToken: cookie.Value})
+		return
+	}
+
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(nonce) + "." + base64.RawURLEncoding.EncodeToString(m.sign(nonce, binding))
+	m.cookies.setCSRFCookie(w, token)
+	writeSuccess(w, http.StatusOK, CSRFTokenResponse{CSRFToken: token})
+}
+
+// Protect is the Chi middleware rejecting state-changing requests authenticated by cookie
+// without a matching CSRF token, or from an untrusted or unknown origin.
+// Requests without auth cookies, such as API clients sending an Authorization header, are not exposed and pass through.
+func (m *CSRFMiddleware) Protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		binding := authCookieValue(r)
+		if binding == "" || r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !m.isTrustedSource(r) {
+			writeError(w, http.StatusForbidden, ErrOriginNotAllowed.Error())
+			return
+		}
+
+		cookie, err := r.Cookie(CSRFCookieName)
+		header := r.Header.Get(CSRFHeaderName)
+		if err != nil || header == "" || !hmac.Equal([]byte(cookie.Value), []byte(header)) || !m.isValid(header, binding) {
+			writeError(w, http.StatusForbidden, ErrInvalidCSRFToken.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isTrustedSource verifies the Origin header, or the Referer when browsers omit the origin.
+// Browsers send one of them on state-changing requests, so requests carrying neither are refused.
+func (m *CSRFMiddleware) isTrustedSource(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if source == "" || source == "null" {
+		source = r.Header.Get("Referer")
+	}
+	if source == "" {
+		return false
+	}
+
+	parsed, err := url.Parse(source)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	if strings.EqualFold(parsed.Host, r.Host) || strings.EqualFold(parsed.Host, r.Header.Get("X-Forwarded-Host")) {
+		return true
+	}
+
+	origin := strings.ToLower(parsed.Scheme + "://" + parsed.Host)
+	return slices.Contains(m.trustedOrigins, origin)
+}
+
+// isValid checks the signature of the token against the auth cookie it was issued for
+func (m *CSRFMiddleware) isValid(token string, binding string) bool {
+	encodedNonce, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	nonce, err := base64.RawURLEncoding.DecodeString(encodedNonce)
+	if err != nil {
+		return false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(signature, m.sign(nonce, binding))
+}
+
+// sign computes the signature of a nonce bound to an auth cookie value.
+// The cookie is hashed first so its length can't shift bytes between the two parts.
+func (m *CSRFMiddleware) sign(nonce []byte, binding string) []byte {
+	bindingHash := sha256.Sum256([]byte(binding))
+
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write(nonce)
+	mac.Write(bindingHash[:])
+
+	return mac.Sum(nil)
+}
+
+// authCookieValue returns the cookie that authenticates the request, the session cookie first, or empty without one
+func authCookieValue(r *http.Request) string {
+	for _, name := range []string{SessionCookieName, RememberCookieName} {
+		if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DataExportHandler represents the personal data export handler object
+type DataExportHandler struct {
+	logger                    *slog.Logger
+	requestDataExportUseCase  *usecase.RequestDataExportUseCase
+	listDataExportsUseCase    *usecase.ListDataExportsUseCase
+	getDataExportUseCase      *usecase.GetDataExportUseCase
+	downloadDataExportUseCase *usecase.DownloadDataExportUseCase
+}
+
+// NewDataExportHandler creates a new personal data export handler object
+func NewDataExportHandler(
+	logger *slog.Logger,
+	requestUC *usecase.RequestDataExportUseCase,
+	listUC *usecase.ListDataExportsUseCase,
+	getUC *usecase.GetDataExportUseCase,
+	downloadUC *usecase.DownloadDataExportUseCase,
+) *DataExportHandler {
+	return &DataExportHandler{
+		logger:                    logger,
+		requestDataExportUseCase:  requestUC,
+		listDataExportsUseCase:    listUC,
+		getDataExportUseCase:      getUC,
+		downloadDataExportUseCase: downloadUC,
+	}
+}
+
+// RequestDataExport godoc
+// @Summary Request a personal data export
+// @Description Starts collecting the personal data of the current user, including the access they granted to clients. A download link is sent by email once the export is ready.
+// @Description Personal access tokens need the profile:write scope.
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 202 {object} SuccessResponse{data=domain.DataExport}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/exports [post]
+func (h *DataExportHandler) RequestDataExport(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	export, err := h.requestDataExportUseCase.Execute(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrDataExportInProgress) {
+			writeError(w, http.StatusConflict, usecase.ErrDataExportInProgress.Error())
+			return
+		}
+
+		h.logger.Error("Failed to request data export : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusAccepted, export)
+}
+
+// ListDataExports godoc
+// @Summary List personal data exports
+// @Description List the personal data exports of the current user and their status
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} SuccessResponse{data=[]domain.DataExport}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/exports [get]
+func (h *DataExportHandler) ListDataExports(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	exports, err := h.listDataExportsUseCase.Execute(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list data exports : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, exports)
+}
+
+// GetDataExport godoc
+// @Summary Get a personal data export
+// @Description Get the status of a personal data export of the current user
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Export ID"
+// @Success 200 {object} SuccessResponse{data=domain.DataExport}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/exports/{id} [get]
+func (h *DataExportHandler) GetDataExport(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	exportID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	export, err := h.getDataExportUseCase.Execute(r.Context(), userID, exportID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrDataExportNotFound) {
+			writeError(w, http.StatusNotFound, usecase.ErrDataExportNotFound.Error())
+			return
+		}
+
+		h.logger.Error("Failed to get data export : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, export)
+}
+
+// DownloadDataExport godoc
+// @Summary Download a personal data export
+// @Description Uses the token sent by email to download the export, as a zip archive or as the bare JSON document
+// @Tags user
+// @Produce application/zip
+// @Produce json
+// @Param token query string true "Data export download token"
+// @Param format query string false "Download format" Enums(zip, json) default(zip)
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/exports/download [get]
+func (h *DataExportHandler) DownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, ErrTokenNotFound.Error())
+		return
+	}
+
+	file, err := h.downloadDataExportUseCase.Execute(r.Context(), token, r.URL.Query().Get("format"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidDataExportFormat) {
+			writeError(w, http.StatusBadRequest, usecase.ErrInvalidDataExportFormat.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusBadRequest, usecase.ErrInvalidToken.Error())
+			return
+		}
+
+		h.logger.Error("Failed to download data export : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	// The link grants access to personal data, nothing along the way may keep a copy
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Data)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(file.Data); err != nil {
+		h.logger.Error("Failed to write data export : ", "error", err)
+	}
+}
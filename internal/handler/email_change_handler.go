@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// EmailChangeHandler represents the email change handler object
+type EmailChangeHandler struct {
+	logger                    *slog.Logger
+	requestEmailChangeUseCase *usecase.RequestEmailChangeUseCase
+	confirmEmailChangeUseCase *usecase.ConfirmEmailChangeUseCase
+	revertEmailChangeUseCase  *usecase.RevertEmailChangeUseCase
+}
+
+// NewEmailChangeHandler creates a new email change handler object
+func NewEmailChangeHandler(
+	logger *slog.Logger,
+	requestUC *usecase.RequestEmailChangeUseCase,
+	confirmUC *usecase.ConfirmEmailChangeUseCase,
+	revertUC *usecase.RevertEmailChangeUseCase,
+) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		logger:                    logger,
+		requestEmailChangeUseCase: requestUC,
+		confirmEmailChangeUseCase: confirmUC,
+		revertEmailChangeUseCase:  revertUC,
+	}
+}
+
+// RequestEmailChangeRequest represent the request body for request email change
+type RequestEmailChangeRequest struct {
+	Email           string `json:"email" example:"new.address@example.com"`
+	CurrentPassword string `json:"current_password" example:"@fg8s64gf!"`
+}
+
+// RequestEmailChangeFailResponse represent the response body for request email change fail
+type RequestEmailChangeFailResponse struct {
+	Email           []string `json:"email" example:"email already exists"`
+	CurrentPassword []string `json:"current_password" example:"current password is incorrect"`
+}
+
+// RequestEmailChange godoc
+// @Summary Request an email address change
+// @Description Send a confirmation link to the new address and a notice with a revert link to the current one. The address changes once the link is confirmed.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param email body RequestEmailChangeRequest true "New email and current password"
+// @Success 202 {object} SuccessResponse
+// @Failure 400 {object} FailResponse{data=RequestEmailChangeFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/email [post]
+func (h *EmailChangeHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	principal, err := GetPrincipalFromContext(r.Context())
+	if err != nil || !principal.IsUser() {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req RequestEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	err = h.requestEmailChangeUseCase.Execute(r.Context(), principal.ID, req.Email, req.CurrentPassword)
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrEmptyEmail) {
+			validationErrors["email"] = append(validationErrors["email"], usecase.ErrEmptyEmail.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidEmail) {
+			validationErrors["email"] = append(validationErrors["email"], usecase.ErrInvalidEmail.Error())
+		}
+		if errors.Is(err, usecase.ErrSameEmail) {
+			validationErrors["email"] = append(validationErrors["email"], usecase.ErrSameEmail.Error())
+		}
+		if errors.Is(err, usecase.ErrEmailExists) {
+			validationErrors["email"] = append(validationErrors["email"], usecase.ErrEmailExists.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidCurrentPassword) {
+			validationErrors["current_password"] = append(validationErrors["current_password"], usecase.ErrInvalidCurrentPassword.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		if errors.Is(err, usecase.ErrEmailManagedExternally) {
+			writeError(w, http.StatusForbidden, usecase.ErrEmailManagedExternally.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+			return
+		}
+
+		h.logger.Error("Failed to request email change : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusAccepted, map[string]string{"message": "a confirmation link has been sent to the new email address"})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm an email address change
+// @Description Uses the token sent to the new address to apply the email change
+// @Tags auth
+// @Produce json
+// @Param token query string true "Email change confirmation token"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/email/confirm [get]
+func (h *EmailChangeHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, ErrTokenNotFound.Error())
+		return
+	}
+
+	err := h.confirmEmailChangeUseCase.Execute(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusBadRequest, usecase.ErrInvalidToken.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrEmailExists) {
+			writeError(w, http.StatusConflict, usecase.ErrEmailExists.Error())
+			return
+		}
+
+		h.logger.Error("Failed to confirm email change : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "email address has been changed"})
+}
+
+// RevertEmailChangePage godoc
+// @Summary Confirm reverting an email address change
+// @Description Landing page of the link sent to the old address. It asks the user to confirm, nothing is changed until the form is posted.
+// @Tags auth
+// @Produce html
+// @Param token query string true "Email change revert token"
+// @Success 200 {string} string "Confirmation page"
+// @Router /api/v1/auth/email/revert [get]
+func (h *EmailChangeHandler) RevertEmailChangePage(w http.ResponseWriter, r *http.Request) {
+	writeConfirmationPage(w, confirmationPageData{
+		Title:   "Revert the email change",
+		Message: "If you didn't change your email address, it will be restored. You will be signed out everywhere and must reset your password.",
+		Action:  r.URL.Path,
+		Button:  "Revert the change",
+		Token:   r.URL.Query().Get("token"),
+	})
+}
+
+// RevertEmailChange godoc
+// @Summary Revert an email address change
+// @Description Uses the token sent to the old address to cancel or undo an email change. The user is signed out everywhere and must reset their password.
+// @Tags auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Email change revert token"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/email/revert [post]
+func (h *EmailChangeHandler) RevertEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.PostFormValue("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, ErrTokenNotFound.Error())
+		return
+	}
+
+	err := h.revertEmailChangeUseCase.Execute(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusBadRequest, usecase.ErrInvalidToken.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrEmailExists) {
+			writeError(w, http.StatusConflict, usecase.ErrEmailExists.Error())
+			return
+		}
+
+		h.logger.Error("Failed to revert email change : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "email change has been reverted, please reset your password"})
+}
@@ -20,4 +20,25 @@ var (
 
 	// ErrInvalidToken is returned when the token is invalid or expired
 	ErrInvalidToken = errors.New("invalid or expired token")
+
+	// ErrInsufficientScope is returned when the credential lacks the scope required by the route
+	ErrInsufficientScope = errors.New("insufficient scope")
+
+	// ErrInsufficientRole is returned when the user lacks the role required by the route
+	ErrInsufficientRole = errors.New("insufficient role")
+
+	// ErrImpersonationNotAllowed is returned when an administrator acting as a user attempts a sensitive action
+	ErrImpersonationNotAllowed = errors.New("this action is not allowed while impersonating a user")
+
+	// ErrReauthenticationRequired is returned when the route requires a more recent or stronger authentication
+	ErrReauthenticationRequired = errors.New("recent authentication required, reauthenticate to continue")
+
+	// ErrInvalidID is returned when a path parameter is not a valid ID
+	ErrInvalidID = errors.New("invalid id")
+
+	// ErrInvalidCSRFToken is returned when a cookie-authenticated request lacks a valid CSRF token
+	ErrInvalidCSRFToken = errors.New("missing or invalid csrf token")
+
+	// ErrOriginNotAllowed is returned when a cookie-authenticated request comes from an untrusted origin
+	ErrOriginNotAllowed = errors.New("request origin is not allowed")
 )
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ForwardAuthHandler represents the forward auth handler object used by reverse proxies such as Traefik and nginx
+type ForwardAuthHandler struct {
+	logger                  *slog.Logger
+	cookies                 CookiePolicy
+	checkForwardAuthUseCase *usecase.CheckForwardAuthUseCase
+	loginURL                string
+}
+
+// NewForwardAuthHandler creates a new forward auth handler object.
+// When loginURL is set, unauthenticated browsers are redirected to it instead of receiving a 401.
+func NewForwardAuthHandler(
+	logger *slog.Logger,
+	cookies CookiePolicy,
+	checkForwardAuthUC *usecase.CheckForwardAuthUseCase,
+	loginURL string,
+) *ForwardAuthHandler {
+	return &ForwardAuthHandler{
+		logger:                  logger,
+		cookies:                 cookies,
+		checkForwardAuthUseCase: checkForwardAuthUC,
+		loginURL:                loginURL,
+	}
+}
+
+// Check godoc
+// @Summary Check a request forwarded by a reverse proxy
+// @Description Validate the bearer token, session cookie or remember_token cookie of a request to a protected application (Traefik ForwardAuth, nginx auth_request).
+// @Description A rotated session cookie is returned in Set-Cookie, the proxy must pass it on to the browser.
+// @Description The original destination is read from X-Forwarded-Host and X-Forwarded-Uri (or X-Original-URI) to apply per-host access rules.
+// @Description On success the user is described by the X-Auth-User-Id, X-Auth-Email and X-Auth-Roles response headers,
+// @Description and X-Auth-Scopes lists the space separated scopes of the credential, "*" for interactive logins.
+// @Tags auth
+// @Security ApiKeyAuth
+// @Param X-Forwarded-Host header string false "Host of the original request"
+// @Param X-Forwarded-Uri header string false "URI of the original request"
+// @Success 200
+// @Failure 302
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/check [get]
+func (h *ForwardAuthHandler) Check(w http.ResponseWriter, r *http.Request) {
+	req := usecase.ForwardAuthRequest{
+		Host:     forwardedHost(r),
+		URI:      forwardedURI(r),
+		ClientIP: clientIP(r),
+	}
+
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		scheme, token, ok := strings.Cut(authHeader, " ")
+		if !ok || !strings.EqualFold(scheme, "bearer") {
+			writeError(w, http.StatusUnauthorized, ErrMalformedAuthHeader.Error())
+			return
+		}
+		req.BearerToken = token
+	} else if cookie, err := r.Cookie(SessionCookieName); err == nil {
+		req.SessionToken = cookie.Value
+	} else if cookie, err := r.Cookie(RememberCookieName); err == nil {
+		req.RememberToken = cookie.Value
+	}
+
+	identity, err := h.checkForwardAuthUseCase.Execute(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserUnauthorized):
+			h.writeUnauthorized(w, r, req)
+		case errors.Is(err, usecase.ErrForbidden):
+			writeError(w, http.StatusForbidden, usecase.ErrForbidden.Error())
+		default:
+			h.logger.Error("Failed to check forwarded request : ", "error", err)
+			writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		}
+
+		return
+	}
+
+	if rotated := identity.RotatedSession; rotated != nil {
+		h.cookies.setSessionCookie(w, rotated.RotatedToken, rotated.Session.ExpiresAt, rotated.Session.Persistent)
+	}
+
+	w.Header().Set("X-Auth-User-Id", strconv.FormatInt(identity.UserID, 10))
+	w.Header().Set("X-Auth-Email", identity.Email)
+	w.Header().Set("X-Auth-Roles", strings.Join(identity.Roles, ","))
+	w.Header().Set("X-Auth-Scopes", strings.Join(identity.Scopes, " "))
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeUnauthorized redirects browsers to the login page when one is configured, and answers 401 otherwise.
+// nginx auth_request only understands 2xx, 401 and 403, so it should be used without a login URL.
+func (h *ForwardAuthHandler) writeUnauthorized(w http.ResponseWriter, r *http.Request, req usecase.ForwardAuthRequest) {
+	if h.loginURL == "" || !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	scheme := r.Header.Get("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	redirectURL := h.loginURL + "?rd=" + url.QueryEscape(scheme+"://"+req.Host+req.URI)
+	if strings.Contains(h.loginURL, "?") {
+		redirectURL = h.loginURL + "&rd=" + url.QueryEscape(scheme+"://"+req.Host+req.URI)
+	}
+
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+// forwardedHost returns the host of the original request
+func forwardedHost(r *http.Request) string {
+	if host := r.Header.Get("X-Forwarded-Host"); host != "" {
+		return host
+	}
+
+	return r.Host
+}
+
+// forwardedURI returns the URI of the original request, set by Traefik or nginx
+func forwardedURI(r *http.Request) string {
+	if uri := r.Header.Get("X-Forwarded-Uri"); uri != "" {
+		return uri
+	}
+
+	if uri := r.Header.Get("X-Original-URI"); uri != "" {
+		return uri
+	}
+
+	return "/"
+}
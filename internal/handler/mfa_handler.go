@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MFAHandler represents the handler of the MFA settings and trusted devices of the current user
+type MFAHandler struct {
+	logger                     *slog.Logger
+	updateMFASettingsUseCase   *usecase.UpdateMFASettingsUseCase
+	listTrustedDevicesUseCase  *usecase.ListTrustedDevicesUseCase
+	revokeTrustedDeviceUseCase *usecase.RevokeTrustedDeviceUseCase
+}
+
+// NewMFAHandler creates a new MFA handler object
+func NewMFAHandler(
+	logger *slog.Logger,
+	updateMFASettingsUC *usecase.UpdateMFASettingsUseCase,
+	listTrustedDevicesUC *usecase.ListTrustedDevicesUseCase,
+	revokeTrustedDeviceUC *usecase.RevokeTrustedDeviceUseCase,
+) *MFAHandler {
+	return &MFAHandler{
+		logger:                     logger,
+		updateMFASettingsUseCase:   updateMFASettingsUC,
+		listTrustedDevicesUseCase:  listTrustedDevicesUC,
+		revokeTrustedDeviceUseCase: revokeTrustedDeviceUC,
+	}
+}
+
+// UpdateMFASettingsRequest represent the request body for update mfa settings
+type UpdateMFASettingsRequest struct {
+	Enabled bool   `json:"enabled" example:"true"`
+	Channel string `json:"channel" example:"sms" enums:"email,sms,voice"`
+}
+
+// UpdateMFASettings godoc
+// @Summary Turn MFA on or off
+// @Description With MFA enabled, every password login completes a second factor unless the device is trusted.
+// @Description The code is sent with the channel, email when empty. The sms and voice channels need a verified phone number.
+// @Description Turning it off forgets the trusted devices.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param settings body UpdateMFASettingsRequest true "MFA settings"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/mfa [put]
+func (h *MFAHandler) UpdateMFASettings(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req UpdateMFASettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := h.updateMFASettingsUseCase.Execute(r.Context(), userID, req.Enabled, req.Channel); err != nil {
+		if errors.Is(err, usecase.ErrInvalidOTPChannel) || errors.Is(err, usecase.ErrPhoneNumberRequired) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		h.logger.Error("Failed to update mfa settings : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	message := "mfa has been disabled"
+	if req.Enabled {
+		message = "mfa has been enabled"
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// ListTrustedDevices godoc
+// @Summary List trusted devices
+// @Description List the devices of the current user that skip the second factor until they expire
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} SuccessResponse{data=[]domain.TrustedDevice}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/trusted-devices [get]
+func (h *MFAHandler) ListTrustedDevices(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	devices, err := h.listTrustedDevicesUseCase.Execute(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list trusted devices : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, devices)
+}
+
+// RevokeTrustedDevice godoc
+// @Summary Revoke a trusted device
+// @Description Stop trusting a device of the current user, its next login completes a second factor again
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Trusted device ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/trusted-devices/{id} [delete]
+func (h *MFAHandler) RevokeTrustedDevice(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	deviceID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	err = h.revokeTrustedDeviceUseCase.Execute(r.Context(), userID, deviceID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrTrustedDeviceNotFound) {
+			writeError(w, http.StatusNotFound, usecase.ErrTrustedDeviceNotFound.Error())
+			return
+		}
+
+		h.logger.Error("Failed to revoke trusted device : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "trusted device has been revoked"})
+}
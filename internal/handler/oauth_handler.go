@@ -0,0 +1,501 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GrantTypeDeviceCode is the grant type of the device authorization grant (RFC 8628)
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// GrantTypeTokenExchange is the grant type of the token exchange grant (RFC 8693)
+const GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// OAuthHandler represents the OAuth 2.0 endpoints handler object
+type OAuthHandler struct {
+	logger                            *slog.Logger
+	clientCredentialsGrantUseCase     *usecase.ClientCredentialsGrantUseCase
+	requestDeviceAuthorizationUseCase *usecase.RequestDeviceAuthorizationUseCase
+	getDeviceAuthorizationUseCase     *usecase.GetDeviceAuthorizationUseCase
+	approveDeviceAuthorizationUseCase *usecase.ApproveDeviceAuthorizationUseCase
+	deviceCodeGrantUseCase            *usecase.DeviceCodeGrantUseCase
+	tokenExchangeGrantUseCase         *usecase.TokenExchangeGrantUseCase
+	introspectTokenUseCase            *usecase.IntrospectTokenUseCase
+	revokeTokenUseCase                *usecase.RevokeTokenUseCase
+}
+
+// NewOAuthHandler creates a new OAuth handler object
+func NewOAuthHandler(
+	logger *slog.Logger,
+	clientCredentialsGrantUC *usecase.ClientCredentialsGrantUseCase,
+	requestDeviceAuthorizationUC *usecase.RequestDeviceAuthorizationUseCase,
+	getDeviceAuthorizationUC *usecase.GetDeviceAuthorizationUseCase,
+	approveDeviceAuthorizationUC *usecase.ApproveDeviceAuthorizationUseCase,
+	deviceCodeGrantUC *usecase.DeviceCodeGrantUseCase,
+	tokenExchangeGrantUC *usecase.TokenExchangeGrantUseCase,
+	introspectTokenUC *usecase.IntrospectTokenUseCase,
+	revokeTokenUC *usecase.RevokeTokenUseCase,
+) *OAuthHandler {
+	return &OAuthHandler{
+		logger:                            logger,
+		clientCredentialsGrantUseCase:     clientCredentialsGrantUC,
+		requestDeviceAuthorizationUseCase: requestDeviceAuthorizationUC,
+		getDeviceAuthorizationUseCase:     getDeviceAuthorizationUC,
+		approveDeviceAuthorizationUseCase: approveDeviceAuthorizationUC,
+		deviceCodeGrantUseCase:            deviceCodeGrantUC,
+		tokenExchangeGrantUseCase:         tokenExchangeGrantUC,
+		introspectTokenUseCase:            introspectTokenUC,
+		revokeTokenUseCase:                revokeTokenUC,
+	}
+}
+
+// DeviceAuthorizationResponse represents the response of the device authorization endpoint (RFC 8628 section 3.2)
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code" example:"GmRhmhcxhwAzkoEqiMEg_DnyEysNkuNhszIySk9eS"`
+	UserCode                string `json:"user_code" example:"WDJB-MJHT"`
+	VerificationURI         string `json:"verification_uri" example:"https://example.com/device"`
+	VerificationURIComplete string `json:"verification_uri_complete" example:"https://example.com/device?user_code=WDJB-MJHT"`
+	ExpiresIn               int64  `json:"expires_in" example:"600"`
+	Interval                int    `json:"interval" example:"5"`
+}
+
+// DeviceAuthorizationDetailResponse represents a pending device authorization shown to the user before approval
+type DeviceAuthorizationDetailResponse struct {
+	ClientID  string    `json:"client_id" example:"cli"`
+	Scope     string    `json:"scope" example:"profile:read"`
+	Status    string    `json:"status" example:"pending"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IntrospectionResponse represents the response of the introspection endpoint (RFC 7662 section 2.2)
+type IntrospectionResponse struct {
+	Active    bool           `json:"active" example:"true"`
+	Scope     string         `json:"scope,omitempty" example:"profile:read"`
+	ClientID  string         `json:"client_id,omitempty" example:"sa_k2Vd8Qx0"`
+	Sub       string         `json:"sub,omitempty" example:"42"`
+	TokenType string         `json:"token_type,omitempty" example:"Bearer"`
+	Exp       int64          `json:"exp,omitempty" example:"1735689600"`
+	Iat       int64          `json:"iat,omitempty" example:"1735603200"`
+	Aud       []string       `json:"aud,omitempty" example:"billing"`
+	Act       map[string]any `json:"act,omitempty"`
+}
+
+// ApproveDeviceAuthorizationRequest represents the request body for approve device authorization
+type ApproveDeviceAuthorizationRequest struct {
+	UserCode string `json:"user_code" example:"WDJB-MJHT"`
+	Approve  bool   `json:"approve" example:"true"`
+}
+
+// Token godoc
+// @Summary OAuth 2.0 token endpoint
+// @Description Issue an access token. Supports the client_credentials grant for service accounts, authenticated with a client secret (HTTP Basic or form) or a private key JWT client assertion,
+// @Description the device code grant (urn:ietf:params:oauth:grant-type:device_code) polled by devices after a device authorization request,
+// @Description and the token exchange grant (urn:ietf:params:oauth:grant-type:token-exchange) letting service accounts with the tokens:exchange scope swap a caller's access token for a downscoped token targeted at an internal service.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Grant type" Enums(client_credentials, urn:ietf:params:oauth:grant-type:device_code, urn:ietf:params:oauth:grant-type:token-exchange)
+// @Param scope formData string false "Space separated scopes"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+// @Param client_assertion formData string false "Signed client assertion"
+// @Param device_code formData string false "Device code of the device code grant"
+// @Param subject_token formData string false "Access token to exchange"
+// @Param subject_token_type formData string false "Subject token type" Enums(urn:ietf:params:oauth:token-type:access_token, urn:ietf:params:oauth:token-type:jwt)
+// @Param actor_token formData string false "Access token of the party acting on behalf of the subject, issued to the calling client"
+// @Param actor_token_type formData string false "Actor token type" Enums(urn:ietf:params:oauth:token-type:access_token, urn:ietf:params:oauth:token-type:jwt)
+// @Param audience formData []string false "Services the exchanged token is meant for, one of TOKEN_EXCHANGE_AUDIENCES" collectionFormat(multi)
+// @Param resource formData []string false "URIs of the services the exchanged token is meant for" collectionFormat(multi)
+// @Success 200 {object} OAuthTokenResponse
+// @Failure 400 {object} OAuthErrorResponse
+// @Failure 401 {object} OAuthErrorResponse
+// @Failure 500 {object} OAuthErrorResponse
+// @Router /api/v1/oauth/token [post]
+func (h *OAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", ErrInvalidRequestBody.Error())
+		return
+	}
+
+	switch grantType := r.PostForm.Get("grant_type"); grantType {
+	case "client_credentials":
+		h.clientCredentialsGrant(w, r)
+	case GrantTypeDeviceCode:
+		h.deviceCodeGrant(w, r)
+	case GrantTypeTokenExchange:
+		h.tokenExchangeGrant(w, r)
+	case "":
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "grant_type is required")
+	default:
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "unsupported grant type: "+grantType)
+	}
+}
+
+// clientCredentialsGrant handles the client_credentials grant
+func (h *OAuthHandler) clientCredentialsGrant(w http.ResponseWriter, r *http.Request) {
+	credentials, ok := clientCredentialsFromRequest(r)
+	if !ok {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "multiple client authentication methods used")
+		return
+	}
+
+	token, err := h.clientCredentialsGrantUseCase.Execute(r.Context(), credentials, r.PostForm.Get("scope"))
+	if err != nil {
+		h.writeGrantError(w, err)
+		return
+	}
+
+	writeOAuthToken(w, token)
+}
+
+// deviceCodeGrant handles the device code grant polled by public clients
+func (h *OAuthHandler) deviceCodeGrant(w http.ResponseWriter, r *http.Request) {
+	token, err := h.deviceCodeGrantUseCase.Execute(r.Context(), r.PostForm.Get("client_id"), r.PostForm.Get("device_code"))
+	if err != nil {
+		h.writeGrantError(w, err)
+		return
+	}
+
+	writeOAuthToken(w, token)
+}
+
+// tokenExchangeGrant handles the token exchange grant used by services to act on behalf of their callers
+func (h *OAuthHandler) tokenExchangeGrant(w http.ResponseWriter, r *http.Request) {
+	credentials, ok := clientCredentialsFromRequest(r)
+	if !ok {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "multiple client authentication methods used")
+		return
+	}
+
+	req := usecase.TokenExchangeRequest{
+		Credentials:  credentials,
+		SubjectToken: r.PostForm.Get("subject_token"),
+		ActorToken:   r.PostForm.Get("actor_token"),
+		Audience:     append(r.PostForm["audience"], r.PostForm["resource"]...),
+		Scope:        r.PostForm.Get("scope"),
+	}
+
+	if req.SubjectToken == "" {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "subject_token is required")
+		return
+	}
+
+	if !isExchangeableTokenType(r.PostForm.Get("subject_token_type")) {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "unsupported subject_token_type")
+		return
+	}
+
+	if req.ActorToken != "" && !isExchangeableTokenType(r.PostForm.Get("actor_token_type")) {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "unsupported actor_token_type")
+		return
+	}
+
+	if len(req.Audience) == 0 {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "audience or resource is required")
+		return
+	}
+
+	token, err := h.tokenExchangeGrantUseCase.Execute(r.Context(), req)
+	if err != nil {
+		h.writeGrantError(w, err)
+		return
+	}
+
+	writeOAuthToken(w, token)
+}
+
+// isExchangeableTokenType reports whether a subject or actor token of the given type can be exchanged
+func isExchangeableTokenType(tokenType string) bool {
+	return tokenType == usecase.TokenTypeAccessToken || tokenType == usecase.TokenTypeJWT
+}
+
+// DeviceAuthorization godoc
+// @Summary OAuth 2.0 device authorization endpoint
+// @Description Start a device authorization grant for input-constrained clients such as CLIs and TVs.
+// @Description The device shows the user code and polls the token endpoint while the user approves it on the verification page.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param client_id formData string true "Client ID"
+// @Param scope formData string false "Space separated scopes, full access when omitted"
+// @Success 200 {object} DeviceAuthorizationResponse
+// @Failure 400 {object} OAuthErrorResponse
+// @Failure 401 {object} OAuthErrorResponse
+// @Failure 500 {object} OAuthErrorResponse
+// @Router /api/v1/oauth/device_authorization [post]
+func (h *OAuthHandler) DeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", ErrInvalidRequestBody.Error())
+		return
+	}
+
+	result, err := h.requestDeviceAuthorizationUseCase.Execute(r.Context(), r.PostForm.Get("client_id"), r.PostForm.Get("scope"))
+	if err != nil {
+		h.writeGrantError(w, err)
+		return
+	}
+
+	writeOAuthJSON(w, http.StatusOK, DeviceAuthorizationResponse{
+		DeviceCode:              result.DeviceCode,
+		UserCode:                result.UserCode,
+		VerificationURI:         result.VerificationURI,
+		VerificationURIComplete: result.VerificationURIComplete,
+		ExpiresIn:               result.ExpiresIn,
+		Interval:                result.Interval,
+	})
+}
+
+// GetDeviceAuthorization godoc
+// @Summary Get a pending device authorization
+// @Description Look up the client and scope requesting access, so the user can review them before approving
+// @Tags oauth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_code query string true "User code shown on the device"
+// @Success 200 {object} SuccessResponse{data=DeviceAuthorizationDetailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/oauth/device [get]
+func (h *OAuthHandler) GetDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	authorization, err := h.getDeviceAuthorizationUseCase.Execute(r.Context(), r.URL.Query().Get("user_code"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidUserCode) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		h.logger.Error("Failed to get device authorization : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, DeviceAuthorizationDetailResponse{
+		ClientID:  authorization.ClientID,
+		Scope:     authorization.Scope,
+		Status:    authorization.Status,
+		ExpiresAt: authorization.ExpiresAt,
+	})
+}
+
+// ApproveDeviceAuthorization godoc
+// @Summary Approve or deny a device authorization
+// @Description Approve or deny the device showing the user code. Requires an interactive session.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param decision body ApproveDeviceAuthorizationRequest true "Decision"
+// @Success 200 {object} SuccessResponse{data=DeviceAuthorizationDetailResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/oauth/device [post]
+func (h *OAuthHandler) ApproveDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req ApproveDeviceAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	authorization, err := h.approveDeviceAuthorizationUseCase.Execute(r.Context(), userID, req.UserCode, req.Approve)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidUserCode) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		h.logger.Error("Failed to decide device authorization : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, DeviceAuthorizationDetailResponse{
+		ClientID:  authorization.ClientID,
+		Scope:     authorization.Scope,
+		Status:    authorization.Status,
+		ExpiresAt: authorization.ExpiresAt,
+	})
+}
+
+// Introspect godoc
+// @Summary OAuth 2.0 token introspection endpoint
+// @Description Describe an access token, remember token or personal access token for resource servers that can't validate tokens locally.
+// @Description The caller authenticates as a service account granted the tokens:introspect scope by an administrator.
+// @Description Unknown, expired and revoked tokens are reported as inactive, as are the tokens of blocked or deleted users.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to introspect"
+// @Param token_type_hint formData string false "Token type hint" Enums(access_token, refresh_token)
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+// @Param client_assertion formData string false "Signed client assertion"
+// @Success 200 {object} IntrospectionResponse
+// @Failure 400 {object} OAuthErrorResponse
+// @Failure 401 {object} OAuthErrorResponse
+// @Failure 500 {object} OAuthErrorResponse
+// @Router /api/v1/oauth/introspect [post]
+func (h *OAuthHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", ErrInvalidRequestBody.Error())
+		return
+	}
+
+	credentials, ok := clientCredentialsFromRequest(r)
+	if !ok {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "multiple client authentication methods used")
+		return
+	}
+
+	introspection, err := h.introspectTokenUseCase.Execute(r.Context(), credentials, r.PostForm.Get("token"), r.PostForm.Get("token_type_hint"))
+	if err != nil {
+		h.writeGrantError(w, err)
+		return
+	}
+
+	writeOAuthJSON(w, http.StatusOK, IntrospectionResponse{
+		Active:    introspection.Active,
+		Scope:     introspection.Scope,
+		ClientID:  introspection.ClientID,
+		Sub:       introspection.Subject,
+		TokenType: introspection.TokenType,
+		Exp:       introspection.ExpiresAt,
+		Iat:       introspection.IssuedAt,
+		Aud:       introspection.Audience,
+		Act:       introspection.Actor,
+	})
+}
+
+// Revoke godoc
+// @Summary OAuth 2.0 token revocation endpoint
+// @Description Revoke a refresh token issued to the calling service account, e.g. by the device code grant.
+// @Description Unknown tokens and tokens issued to other clients are ignored. JWT access tokens can't be revoked and expire on their own.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to revoke"
+// @Param token_type_hint formData string false "Token type hint" Enums(access_token, refresh_token)
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+// @Param client_assertion formData string false "Signed client assertion"
+// @Success 200
+// @Failure 400 {object} OAuthErrorResponse
+// @Failure 401 {object} OAuthErrorResponse
+// @Failure 500 {object} OAuthErrorResponse
+// @Router /api/v1/oauth/revoke [post]
+func (h *OAuthHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", ErrInvalidRequestBody.Error())
+		return
+	}
+
+	credentials, ok := clientCredentialsFromRequest(r)
+	if !ok {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "multiple client authentication methods used")
+		return
+	}
+
+	if err := h.revokeTokenUseCase.Execute(r.Context(), credentials, r.PostForm.Get("token")); err != nil {
+		h.writeGrantError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeGrantError maps use case errors to OAuth error codes
+func (h *OAuthHandler) writeGrantError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidClient):
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+	case errors.Is(err, usecase.ErrInvalidScope):
+		writeOAuthError(w, http.StatusBadRequest, "invalid_scope", err.Error())
+	case errors.Is(err, usecase.ErrInvalidGrant):
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant", err.Error())
+	case errors.Is(err, usecase.ErrAuthorizationPending):
+		writeOAuthError(w, http.StatusBadRequest, "authorization_pending", err.Error())
+	case errors.Is(err, usecase.ErrSlowDown):
+		writeOAuthError(w, http.StatusBadRequest, "slow_down", err.Error())
+	case errors.Is(err, usecase.ErrExpiredToken):
+		writeOAuthError(w, http.StatusBadRequest, "expired_token", err.Error())
+	case errors.Is(err, usecase.ErrAccessDenied):
+		writeOAuthError(w, http.StatusBadRequest, "access_denied", err.Error())
+	case errors.Is(err, usecase.ErrUnsupportedTokenType):
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_token_type", err.Error())
+	case errors.Is(err, usecase.ErrUnauthorizedClient):
+		writeOAuthError(w, http.StatusBadRequest, "unauthorized_client", err.Error())
+	case errors.Is(err, usecase.ErrInvalidTarget):
+		writeOAuthError(w, http.StatusBadRequest, "invalid_target", err.Error())
+	default:
+		h.logger.Error("Failed to issue oauth token : ", "error", err)
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", usecase.ErrInternalServer.Error())
+	}
+}
+
+// writeOAuthToken writes an issued token as a token endpoint response
+func writeOAuthToken(w http.ResponseWriter, token *usecase.OAuthToken) {
+	writeOAuthJSON(w, http.StatusOK, OAuthTokenResponse{
+		AccessToken:     token.AccessToken,
+		TokenType:       token.TokenType,
+		ExpiresIn:       token.ExpiresIn,
+		RefreshToken:    token.RefreshToken,
+		Scope:           token.Scope,
+		IssuedTokenType: token.IssuedTokenType,
+	})
+}
+
+// clientCredentialsFromRequest reads the client credentials from the Authorization header or the form.
+// It reports false when the client used more than one authentication method.
+func clientCredentialsFromRequest(r *http.Request) (usecase.ClientCredentials, bool) {
+	credentials := usecase.ClientCredentials{
+		ClientID:            r.PostForm.Get("client_id"),
+		ClientSecret:        r.PostForm.Get("client_secret"),
+		ClientAssertionType: r.PostForm.Get("client_assertion_type"),
+		ClientAssertion:     r.PostForm.Get("client_assertion"),
+	}
+
+	username, password, hasBasic := r.BasicAuth()
+	if !hasBasic {
+		return credentials, credentials.ClientSecret == "" || credentials.ClientAssertion == ""
+	}
+
+	if credentials.ClientSecret != "" || credentials.ClientAssertion != "" {
+		return credentials, false
+	}
+
+	// Basic credentials are form-urlencoded before being base64 encoded (RFC 6749 section 2.3.1)
+	clientID, err := url.QueryUnescape(username)
+	if err != nil {
+		clientID = username
+	}
+	clientSecret, err := url.QueryUnescape(password)
+	if err != nil {
+		clientSecret = password
+	}
+
+	if credentials.ClientID != "" && credentials.ClientID != clientID {
+		return credentials, false
+	}
+
+	credentials.ClientID = clientID
+	credentials.ClientSecret = clientSecret
+
+	return credentials, true
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PersonalAccessTokenHandler represents the personal access token handler object
+type PersonalAccessTokenHandler struct {
+	logger                           *slog.Logger
+	createPersonalAccessTokenUseCase *usecase.CreatePersonalAccessTokenUseCase
+	listPersonalAccessTokensUseCase  *usecase.ListPersonalAccessTokensUseCase
+	revokePersonalAccessTokenUseCase *usecase.RevokePersonalAccessTokenUseCase
+}
+
+// NewPersonalAccessTokenHandler creates a new personal access token handler object
+func NewPersonalAccessTokenHandler(
+	logger *slog.Logger,
+	createUC *usecase.CreatePersonalAccessTokenUseCase,
+	listUC *usecase.ListPersonalAccessTokensUseCase,
+	revokeUC *usecase.RevokePersonalAccessTokenUseCase,
+) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		logger:                           logger,
+		createPersonalAccessTokenUseCase: createUC,
+		listPersonalAccessTokensUseCase:  listUC,
+		revokePersonalAccessTokenUseCase: revokeUC,
+	}
+}
+
+// CreatePersonalAccessTokenRequest represent the request body for create personal access token
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" example:"deploy script"`
+	Scopes        []string `json:"scopes" example:"profile:read"`
+	ExpiresInDays int      `json:"expires_in_days" example:"90"`
+}
+
+// CreatePersonalAccessTokenSuccessResponse represent the response body for create personal access token success
+type CreatePersonalAccessTokenSuccessResponse struct {
+	ID        int64     `json:"id" example:"1"`
+	Name      string    `json:"name" example:"deploy script"`
+	Scopes    []string  `json:"scopes" example:"profile:read"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Token     string    `json:"token" example:"pat_3q2-7wEjX0aQ"`
+}
+
+// CreatePersonalAccessTokenFailResponse represent the response body for create personal access token fail
+type CreatePersonalAccessTokenFailResponse struct {
+	Name          []string `json:"name" example:"token name is required"`
+	Scopes        []string `json:"scopes" example:"invalid or unsupported scope"`
+	ExpiresInDays []string `json:"expires_in_days" example:"token expiry must be between 1 and 365 days"`
+}
+
+// CreatePersonalAccessToken godoc
+// @Summary Create a personal access token
+// @Description Create a long-lived token for scripts and CLI tools. The raw token is only shown in this response.
+// @Tags token
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param token body CreatePersonalAccessTokenRequest true "Token details"
+// @Success 201 {object} SuccessResponse{data=CreatePersonalAccessTokenSuccessResponse}
+// @Failure 400 {object} FailResponse{data=CreatePersonalAccessTokenFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/tokens [post]
+func (h *PersonalAccessTokenHandler) CreatePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req CreatePersonalAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	token, rawToken, err := h.createPersonalAccessTokenUseCase.Execute(r.Context(), userID, req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrEmptyTokenName) {
+			validationErrors["name"] = append(validationErrors["name"], usecase.ErrEmptyTokenName.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidScope) {
+			validationErrors["scopes"] = append(validationErrors["scopes"], usecase.ErrInvalidScope.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidTokenExpiry) {
+			validationErrors["expires_in_days"] = append(validationErrors["expires_in_days"], usecase.ErrInvalidTokenExpiry.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		h.logger.Error("Failed to create personal access token : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	response := CreatePersonalAccessTokenSuccessResponse{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		ExpiresAt: token.ExpiresAt,
+		Token:     rawToken,
+	}
+
+	writeSuccess(w, http.StatusCreated, response)
+}
+
+// ListPersonalAccessTokens godoc
+// @Summary List personal access tokens
+// @Description List the personal access tokens of the current user, without their raw values
+// @Tags token
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} SuccessResponse{data=[]domain.PersonalAccessToken}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/tokens [get]
+func (h *PersonalAccessTokenHandler) ListPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	tokens, err := h.listPersonalAccessTokensUseCase.Execute(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list personal access tokens : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, tokens)
+}
+
+// RevokePersonalAccessToken godoc
+// @Summary Revoke a personal access token
+// @Description Delete a personal access token of the current user
+// @Tags token
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Token ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/tokens/{id} [delete]
+func (h *PersonalAccessTokenHandler) RevokePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	tokenID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	err = h.revokePersonalAccessTokenUseCase.Execute(r.Context(), userID, tokenID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrTokenNotFound) {
+			writeError(w, http.StatusNotFound, usecase.ErrTokenNotFound.Error())
+			return
+		}
+
+		h.logger.Error("Failed to revoke personal access token : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "token has been revoked"})
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"auth/internal/usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// PhoneHandler represents the handler of the phone number of the current user
+type PhoneHandler struct {
+	logger                          *slog.Logger
+	requestPhoneVerificationUseCase *usecase.RequestPhoneVerificationUseCase
+	verifyPhoneNumberUseCase        *usecase.VerifyPhoneNumberUseCase
+	removePhoneNumberUseCase        *usecase.RemovePhoneNumberUseCase
+}
+
+// NewPhoneHandler creates a new phone handler object
+func NewPhoneHandler(
+	logger *slog.Logger,
+	requestPhoneVerificationUC *usecase.RequestPhoneVerificationUseCase,
+	verifyPhoneNumberUC *usecase.VerifyPhoneNumberUseCase,
+	removePhoneNumberUC *usecase.RemovePhoneNumberUseCase,
+) *PhoneHandler {
+	return &PhoneHandler{
+		logger:                          logger,
+		requestPhoneVerificationUseCase: requestPhoneVerificationUC,
+		verifyPhoneNumberUseCase:        verifyPhoneNumberUC,
+		removePhoneNumberUseCase:        removePhoneNumberUC,
+	}
+}
+
+// RequestPhoneVerificationRequest represent the request body for request phone verification
+type RequestPhoneVerificationRequest struct {
+	PhoneNumber string `json:"phone_number" example:"+6281234567890"`
+	Channel     string `json:"channel" example:"sms" enums:"sms,voice"`
+}
+
+// RequestPhoneVerificationFailResponse represent the response body for request phone verification fail
+type RequestPhoneVerificationFailResponse struct {
+	PhoneNumber []string `json:"phone_number" example:"phone number field is required"`
+	Channel     []string `json:"channel" example:"channel must be email, sms or voice"`
+}
+
+// RequestPhoneVerification godoc
+// @Summary Add a phone number
+// @Description Send a verification code to the phone number by sms or voice call, sms when the channel is empty.
+// @Description The phone number replaces the current one once the code is verified.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param phone body RequestPhoneVerificationRequest true "Phone number in E.164 format and channel"
+// @Success 202 {object} SuccessResponse
+// @Failure 400 {object} FailResponse{data=RequestPhoneVerificationFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/phone [post]
+func (h *PhoneHandler) RequestPhoneVerification(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req RequestPhoneVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	err = h.requestPhoneVerificationUseCase.Execute(r.Context(), userID, req.PhoneNumber, req.Channel)
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrEmptyPhoneNumber) {
+			validationErrors["phone_number"] = append(validationErrors["phone_number"], usecase.ErrEmptyPhoneNumber.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidPhoneNumber) {
+			validationErrors["phone_number"] = append(validationErrors["phone_number"], usecase.ErrInvalidPhoneNumber.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidOTPChannel) {
+			validationErrors["channel"] = append(validationErrors["channel"], usecase.ErrInvalidOTPChannel.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		if errors.Is(err, usecase.ErrTooManyPhoneCodes) {
+			writeError(w, http.StatusTooManyRequests, usecase.ErrTooManyPhoneCodes.Error())
+			return
+		}
+
+		h.logger.Error("Failed to request phone verification : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusAccepted, map[string]string{"message": "a verification code has been sent to the phone number"})
+}
+
+// VerifyPhoneNumberRequest represent the request body for verify phone number
+type VerifyPhoneNumberRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+// VerifyPhoneNumberSuccessResponse represent the response body for verify phone number success
+type VerifyPhoneNumberSuccessResponse struct {
+	PhoneNumber string `json:"phone_number" example:"+6281234567890"`
+}
+
+// VerifyPhoneNumber godoc
+// @Summary Verify a phone number
+// @Description Use the code sent to the phone number to set it as the phone number of the current user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param code body VerifyPhoneNumberRequest true "Verification code"
+// @Success 200 {object} SuccessResponse{data=VerifyPhoneNumberSuccessResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/phone/verify [post]
+func (h *PhoneHandler) VerifyPhoneNumber(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req VerifyPhoneNumberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	phoneNumber, err := h.verifyPhoneNumberUseCase.Execute(r.Context(), userID, req.Code)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidVerificationCode) {
+			writeError(w, http.StatusBadRequest, usecase.ErrInvalidVerificationCode.Error())
+			return
+		}
+
+		h.logger.Error("Failed to verify phone number : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, VerifyPhoneNumberSuccessResponse{PhoneNumber: phoneNumber})
+}
+
+// RemovePhoneNumber godoc
+// @Summary Remove the phone number
+// @Description Remove the phone number of the current user, login and MFA codes are emailed from then on
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/phone [delete]
+func (h *PhoneHandler) RemovePhoneNumber(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	if err := h.removePhoneNumberUseCase.Execute(r.Context(), userID); err != nil {
+		h.logger.Error("Failed to remove phone number : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "phone number has been removed"})
+}
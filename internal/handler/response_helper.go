@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"auth/internal/usecase"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 )
@@ -71,3 +73,60 @@ func writeError(w http.ResponseWriter, httpStatus int, message string) {
 		return
 	}
 }
+
+// OAuthTokenResponse represents the successful token endpoint response (RFC 6749 section 5.1)
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"3600"`
+	RefreshToken string `json:"refresh_token,omitempty" example:"Zm9vYmFyYmF6cXV4"`
+	Scope        string `json:"scope,omitempty" example:"orders:read"`
+	// IssuedTokenType is only set by the token exchange grant (RFC 8693 section 2.2.1)
+	IssuedTokenType string `json:"issued_token_type,omitempty" example:"urn:ietf:params:oauth:token-type:access_token"`
+}
+
+// OAuthErrorResponse represents the error response of the OAuth endpoints (RFC 6749 section 5.2)
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_client"`
+	ErrorDescription string `json:"error_description,omitempty" example:"invalid client credentials"`
+}
+
+func writeOAuthJSON(w http.ResponseWriter, httpStatus int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	// Token responses must never be cached
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(httpStatus)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		slog.Error(
+			"Failed to encode response",
+			slog.Any("error", err),
+			slog.Int("status", httpStatus),
+		)
+
+		return
+	}
+}
+
+func writeOAuthError(w http.ResponseWriter, httpStatus int, code string, description string) {
+	if code == "invalid_client" {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+
+	writeOAuthJSON(w, httpStatus, OAuthErrorResponse{Error: code, ErrorDescription: description})
+}
+
+// passwordPolicyMessages returns the policy violation messages carried by a weak password error
+func passwordPolicyMessages(err error) []string {
+	var policyErr *usecase.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return []string{usecase.ErrWeakPassword.Error()}
+	}
+
+	messages := make([]string, 0, len(policyErr.Result.Violations))
+	for _, violation := range policyErr.Result.Violations {
+		messages = append(messages, violation.Message)
+	}
+
+	return messages
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"auth/internal/domain"
+	"auth/internal/usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ServiceAccountHandler represents the service account handler object
+type ServiceAccountHandler struct {
+	logger                            *slog.Logger
+	createServiceAccountUseCase       *usecase.CreateServiceAccountUseCase
+	listServiceAccountsUseCase        *usecase.ListServiceAccountsUseCase
+	rotateServiceAccountSecretUseCase *usecase.RotateServiceAccountSecretUseCase
+	addServiceAccountKeyUseCase       *usecase.AddServiceAccountKeyUseCase
+}
+
+// NewServiceAccountHandler creates a new service account handler object
+func NewServiceAccountHandler(
+	logger *slog.Logger,
+	createUC *usecase.CreateServiceAccountUseCase,
+	listUC *usecase.ListServiceAccountsUseCase,
+	rotateSecretUC *usecase.RotateServiceAccountSecretUseCase,
+	addKeyUC *usecase.AddServiceAccountKeyUseCase,
+) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		logger:                            logger,
+		createServiceAccountUseCase:       createUC,
+		listServiceAccountsUseCase:        listUC,
+		rotateServiceAccountSecretUseCase: rotateSecretUC,
+		addServiceAccountKeyUseCase:       addKeyUC,
+	}
+}
+
+// CreateServiceAccountRequest represent the request body for create service account
+type CreateServiceAccountRequest struct {
+	Name         string `json:"name" example:"billing worker"`
+	Organization string `json:"organization" example:"acme"`
+	// Scopes can't include tokens:write, tokens:exchange and tokens:introspect can only be granted by an administrator
+	Scopes []string `json:"scopes" example:"orders:read"`
+}
+
+// CreateServiceAccountSuccessResponse represent the response body for create service account success
+type CreateServiceAccountSuccessResponse struct {
+	domain.ServiceAccount
+	ClientSecret string `json:"client_secret" example:"Xk2-9qvTz0aP"`
+}
+
+// CreateServiceAccountFailResponse represent the response body for create service account fail
+type CreateServiceAccountFailResponse struct {
+	Name         []string `json:"name" example:"name field is required"`
+	Organization []string `json:"organization" example:"organization field is required"`
+	Scopes       []string `json:"scopes" example:"invalid or unsupported scope"`
+}
+
+// RotateServiceAccountSecretRequest represent the request body for rotate service account secret
+type RotateServiceAccountSecretRequest struct {
+	// OverlapHours is how long the previous secrets stay valid, 24 hours when omitted
+	OverlapHours *int `json:"overlap_hours" example:"24"`
+}
+
+// RotateServiceAccountSecretSuccessResponse represent the response body for rotate service account secret success
+type RotateServiceAccountSecretSuccessResponse struct {
+	ClientSecret string `json:"client_secret" example:"Xk2-9qvTz0aP"`
+}
+
+// AddServiceAccountKeyRequest represent the request body for add service account key
+type AddServiceAccountKeyRequest struct {
+	KeyID     string `json:"key_id" example:"2025-01"`
+	PublicKey string `json:"public_key" example:"-----BEGIN PUBLIC KEY-----\n...\n-----END PUBLIC KEY-----"`
+}
+
+// CreateServiceAccount godoc
+// @Summary Create a service account
+// @Description Create a machine identity for a backend service. The client secret is only shown in this response.
+// @Tags service-account
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param account body CreateServiceAccountRequest true "Service account details"
+// @Success 201 {object} SuccessResponse{data=CreateServiceAccountSuccessResponse}
+// @Failure 400 {object} FailResponse{data=CreateServiceAccountFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts [post]
+func (h *ServiceAccountHandler) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req CreateServiceAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	account, secret, err := h.createServiceAccountUseCase.Execute(r.Context(), userID, req.Name, req.Organization, req.Scopes)
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrEmptyName) {
+			validationErrors["name"] = append(validationErrors["name"], usecase.ErrEmptyName.Error())
+		}
+		if errors.Is(err, usecase.ErrEmptyOrganization) {
+			validationErrors["organization"] = append(validationErrors["organization"], usecase.ErrEmptyOrganization.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidScope) {
+			validationErrors["scopes"] = append(validationErrors["scopes"], usecase.ErrInvalidScope.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		h.logger.Error("Failed to create service account : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusCreated, CreateServiceAccountSuccessResponse{ServiceAccount: *account, ClientSecret: secret})
+}
+
+// ListServiceAccounts godoc
+// @Summary List service accounts
+// @Description List the service accounts owned by the current user
+// @Tags service-account
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} SuccessResponse{data=[]domain.ServiceAccount}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts [get]
+func (h *ServiceAccountHandler) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	accounts, err := h.listServiceAccountsUseCase.Execute(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list service accounts : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, accounts)
+}
+
+// RotateServiceAccountSecret godoc
+// @Summary Rotate a service account secret
+// @Description Issue a new client secret. Previous secrets keep working for the overlap so clients can be redeployed.
+// @Tags service-account
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Service account ID"
+// @Param rotation body RotateServiceAccountSecretRequest false "Rotation options"
+// @Success 201 {object} SuccessResponse{data=RotateServiceAccountSecretSuccessResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts/{id}/secrets [post]
+func (h *ServiceAccountHandler) RotateServiceAccountSecret(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	serviceAccountID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	// The body is optional
+	var req RotateServiceAccountSecretRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+			return
+		}
+	}
+
+	overlap := usecase.DefaultSecretRotationOverlap
+	if req.OverlapHours != nil {
+		overlap = time.Duration(*req.OverlapHours) * time.Hour
+	}
+
+	secret, err := h.rotateServiceAccountSecretUseCase.Execute(r.Context(), userID, serviceAccountID, overlap)
+	if err != nil {
+		h.writeServiceAccountError(w, err, "Failed to rotate service account secret : ")
+		return
+	}
+
+	writeSuccess(w, http.StatusCreated, RotateServiceAccountSecretSuccessResponse{ClientSecret: secret})
+}
+
+// AddServiceAccountKey godoc
+// @Summary Add a service account key
+// @Description Register a PEM encoded RSA, ECDSA or Ed25519 public key for private key JWT client authentication
+// @Tags service-account
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Service account ID"
+// @Param key body AddServiceAccountKeyRequest true "Public key"
+// @Success 201 {object} SuccessResponse{data=domain.ServiceAccountKey}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts/{id}/keys [post]
+func (h *ServiceAccountHandler) AddServiceAccountKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	serviceAccountID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	var req AddServiceAccountKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	key, err := h.addServiceAccountKeyUseCase.Execute(r.Context(), userID, serviceAccountID, req.KeyID, req.PublicKey)
+	if err != nil {
+		h.writeServiceAccountError(w, err, "Failed to add service account key : ")
+		return
+	}
+
+	writeSuccess(w, http.StatusCreated, key)
+}
+
+// writeServiceAccountError maps the errors of the service account management use cases
+func (h *ServiceAccountHandler) writeServiceAccountError(w http.ResponseWriter, err error, logMessage string) {
+	switch {
+	case errors.Is(err, usecase.ErrServiceAccountNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidPublicKey), errors.Is(err, usecase.ErrInvalidRotationOverlap):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(logMessage, "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 )
 
 // UserHandler represents the user handler object
@@ -14,6 +15,9 @@ type UserHandler struct {
 	registerUserUseCase         *usecase.RegisterUserUseCase
 	registerUserWithCodeUseCase *usecase.RegisterUserWithCodeUseCase
 	getUserProfileUseCase       *usecase.GetUserProfileUseCase
+	changeUserPasswordUseCase   *usecase.ChangeUserPasswordUseCase
+	updateUserProfileUseCase    *usecase.UpdateUserProfileUseCase
+	deleteAccountUseCase        *usecase.DeleteAccountUseCase
 }
 
 // NewUserHandler creates a new user handler object
@@ -22,12 +26,18 @@ func NewUserHandler(
 	registerUC *usecase.RegisterUserUseCase,
 	registerWithCodeUC *usecase.RegisterUserWithCodeUseCase,
 	getProfileUC *usecase.GetUserProfileUseCase,
+	changePasswordUC *usecase.ChangeUserPasswordUseCase,
+	updateProfileUC *usecase.UpdateUserProfileUseCase,
+	deleteAccountUC *usecase.DeleteAccountUseCase,
 ) *UserHandler {
 	return &UserHandler{
 		logger:                      logger,
 		registerUserUseCase:         registerUC,
 		registerUserWithCodeUseCase: registerWithCodeUC,
 		getUserProfileUseCase:       getProfileUC,
+		changeUserPasswordUseCase:   changePasswordUC,
+		updateUserProfileUseCase:    updateProfileUC,
+		deleteAccountUseCase:        deleteAccountUC,
 	}
 }
 
@@ -42,7 +52,7 @@ type RegisterUserRequest struct {
 type RegisterUserFailResponse struct {
 	Name     []string `json:"name" example:"name is required,"`
 	Email    []string `json:"email" example:"email is required,email is invalid"`
-	Password []string `json:"password" example:"password is required,password too short"`
+	Password []string `json:"password" example:"password is required,password must be at least 8 characters long"`
 }
 
 // RegisterUserWithCodeSuccessResponse represent the response body for register user with code success
@@ -92,8 +102,8 @@ func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, usecase.ErrInvalidEmail) {
 			validationErrors["email"] = append(validationErrors["email"], usecase.ErrInvalidEmail.Error())
 		}
-		if errors.Is(err, usecase.ErrPasswordTooShort) {
-			validationErrors["password"] = append(validationErrors["password"], usecase.ErrPasswordTooShort.Error())
+		if errors.Is(err, usecase.ErrWeakPassword) {
+			validationErrors["password"] = append(validationErrors["password"], passwordPolicyMessages(err)...)
 		}
 		if len(validationErrors) > 0 {
 			writeFail(w, http.StatusBadRequest, validationErrors)
@@ -157,8 +167,8 @@ func (h *UserHandler) RegisterUserWithCode(w http.ResponseWriter, r *http.Reques
 		if errors.Is(err, usecase.ErrInvalidEmail) {
 			validationErrors["email"] = append(validationErrors["email"], usecase.ErrInvalidEmail.Error())
 		}
-		if errors.Is(err, usecase.ErrPasswordTooShort) {
-			validationErrors["password"] = append(validationErrors["password"], usecase.ErrPasswordTooShort.Error())
+		if errors.Is(err, usecase.ErrWeakPassword) {
+			validationErrors["password"] = append(validationErrors["password"], passwordPolicyMessages(err)...)
 		}
 		if len(validationErrors) > 0 {
 			writeFail(w, http.StatusBadRequest, validationErrors)
@@ -186,6 +196,58 @@ func (h *UserHandler) RegisterUserWithCode(w http.ResponseWriter, r *http.Reques
 	writeSuccess(w, http.StatusCreated, response)
 }
 
+// ChangePasswordRequest represent the request body for change password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"@fg8s64gf!"`
+	NewPassword     string `json:"new_password" example:"$fesf&idsie94"`
+}
+
+// ChangePasswordFailResponse represent the response body for change password fail
+type ChangePasswordFailResponse struct {
+	CurrentPassword []string `json:"current_password" example:"current password is incorrect"`
+	NewPassword     []string `json:"new_password" example:"password must be at least 8 characters long"`
+}
+
+// UpdateUserProfileRequest represent the request body for update user profile, omitted fields are left unchanged
+type UpdateUserProfileRequest struct {
+	Name      *string              `json:"name" example:"Egi"`
+	Locale    *string              `json:"locale" example:"en-US"`
+	Timezone  *string              `json:"timezone" example:"Asia/Jakarta"`
+	AvatarURL *string              `json:"avatar_url" example:"https://example.com/avatar.png"`
+	Metadata  *UserMetadataRequest `json:"metadata"`
+}
+
+// UserMetadataRequest represent the metadata changes of update user profile, keys set to null are removed
+type UserMetadataRequest struct {
+	Public  map[string]any `json:"public"`
+	Private map[string]any `json:"private"`
+}
+
+// UpdateUserProfileFailResponse represent the response body for update user profile fail
+type UpdateUserProfileFailResponse struct {
+	Name      []string `json:"name" example:"name must be at most 100 characters long"`
+	Locale    []string `json:"locale" example:"locale must be a valid BCP 47 language tag"`
+	Timezone  []string `json:"timezone" example:"timezone must be a valid IANA time zone"`
+	AvatarURL []string `json:"avatar_url" example:"avatar url must be an absolute http or https url"`
+	Metadata  []string `json:"metadata" example:"metadata must be at most 4096 bytes when encoded as JSON"`
+}
+
+// DeleteAccountRequest represent the request body for delete account
+type DeleteAccountRequest struct {
+	Password string `json:"password" example:"@fg8s64gf!"`
+}
+
+// DeleteAccountSuccessResponse represent the response body for delete account success
+type DeleteAccountSuccessResponse struct {
+	Message string    `json:"message" example:"account is scheduled for deletion"`
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// DeleteAccountFailResponse represent the response body for delete account fail
+type DeleteAccountFailResponse struct {
+	Password []string `json:"password" example:"current password is incorrect"`
+}
+
 // GetUserProfile godoc
 // @Summary Get user profile
 // @Description Get detail user
@@ -222,3 +284,218 @@ func (h *UserHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 	// Send a successful response
 	writeSuccess(w, http.StatusOK, user)
 }
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the password of the current user. Other devices are signed out, the current session or remember token stays valid, and a notification email is sent.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param X-Remember-Token header string false "Remember token of the current non-web client, kept valid"
+// @Param password body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} FailResponse{data=ChangePasswordFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/password [post]
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	principal, err := GetPrincipalFromContext(r.Context())
+	if err != nil || !principal.IsUser() {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	rememberToken := r.Header.Get("X-Remember-Token")
+	if cookie, err := r.Cookie(RememberCookieName); err == nil {
+		rememberToken = cookie.Value
+	}
+
+	err = h.changeUserPasswordUseCase.Execute(r.Context(), usecase.ChangeUserPasswordInput{
+		UserID:          principal.ID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+		SessionID:       principal.SessionID,
+		RememberToken:   rememberToken,
+	})
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrInvalidCurrentPassword) {
+			validationErrors["current_password"] = append(validationErrors["current_password"], usecase.ErrInvalidCurrentPassword.Error())
+		}
+		if errors.Is(err, usecase.ErrEmptyPassword) {
+			validationErrors["new_password"] = append(validationErrors["new_password"], usecase.ErrEmptyPassword.Error())
+		}
+		if errors.Is(err, usecase.ErrWeakPassword) {
+			validationErrors["new_password"] = append(validationErrors["new_password"], passwordPolicyMessages(err)...)
+		}
+		if errors.Is(err, usecase.ErrPasswordReused) {
+			validationErrors["new_password"] = append(validationErrors["new_password"], usecase.ErrPasswordReused.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		if errors.Is(err, usecase.ErrPasswordManagedExternally) {
+			writeError(w, http.StatusForbidden, usecase.ErrPasswordManagedExternally.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+			return
+		}
+
+		h.logger.Error("Failed to change password : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, map[string]string{"message": "password has been changed"})
+}
+
+// UpdateUserProfile godoc
+// @Summary Update user profile
+// @Description Update the name, locale, timezone, avatar and metadata of the current user. Omitted fields are left unchanged.
+// @Description Metadata keys are merged into the stored metadata, keys set to null are removed. Users edit their own metadata, so it is never forwarded to the applications behind forward auth.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param profile body UpdateUserProfileRequest true "Profile changes"
+// @Success 200 {object} SuccessResponse{data=domain.User}
+// @Failure 400 {object} FailResponse{data=UpdateUserProfileFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me [patch]
+func (h *UserHandler) UpdateUserProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req UpdateUserProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	input := usecase.UpdateUserProfileInput{
+		UserID:    userID,
+		Name:      req.Name,
+		Locale:    req.Locale,
+		Timezone:  req.Timezone,
+		AvatarURL: req.AvatarURL,
+	}
+	if req.Metadata != nil {
+		input.PublicMetadata = req.Metadata.Public
+		input.PrivateMetadata = req.Metadata.Private
+	}
+
+	user, err := h.updateUserProfileUseCase.Execute(r.Context(), input)
+	if err != nil {
+		validationErrors := make(map[string][]string)
+
+		if errors.Is(err, usecase.ErrEmptyName) {
+			validationErrors["name"] = append(validationErrors["name"], usecase.ErrEmptyName.Error())
+		}
+		if errors.Is(err, usecase.ErrNameTooLong) {
+			validationErrors["name"] = append(validationErrors["name"], usecase.ErrNameTooLong.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidLocale) {
+			validationErrors["locale"] = append(validationErrors["locale"], usecase.ErrInvalidLocale.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidTimezone) {
+			validationErrors["timezone"] = append(validationErrors["timezone"], usecase.ErrInvalidTimezone.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidAvatarURL) {
+			validationErrors["avatar_url"] = append(validationErrors["avatar_url"], usecase.ErrInvalidAvatarURL.Error())
+		}
+		if errors.Is(err, usecase.ErrMetadataTooLarge) {
+			validationErrors["metadata"] = append(validationErrors["metadata"], usecase.ErrMetadataTooLarge.Error())
+		}
+		if len(validationErrors) > 0 {
+			writeFail(w, http.StatusBadRequest, validationErrors)
+			return
+		}
+
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+			return
+		}
+
+		h.logger.Error("Failed to update user profile : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, user)
+}
+
+// DeleteAccount godoc
+// @Summary Delete account
+// @Description Schedule the account of the current user for deletion after confirming their password. The user is signed out everywhere,
+// @Description and the account is permanently deleted once the grace period ends unless it is restored by logging in.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param password body DeleteAccountRequest true "Current password"
+// @Success 202 {object} SuccessResponse{data=DeleteAccountSuccessResponse}
+// @Failure 400 {object} FailResponse{data=DeleteAccountFailResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me [delete]
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetUserIDFromContext(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequestBody.Error())
+		return
+	}
+
+	purgeAt, err := h.deleteAccountUseCase.Execute(r.Context(), userID, req.Password)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCurrentPassword) {
+			writeFail(w, http.StatusBadRequest, map[string][]string{"password": {usecase.ErrInvalidCurrentPassword.Error()}})
+			return
+		}
+
+		if errors.Is(err, usecase.ErrAccountPendingDeletion) {
+			writeError(w, http.StatusConflict, usecase.ErrAccountPendingDeletion.Error())
+			return
+		}
+
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			writeError(w, http.StatusUnauthorized, usecase.ErrUserUnauthorized.Error())
+			return
+		}
+
+		h.logger.Error("Failed to delete account : ", "error", err)
+		writeError(w, http.StatusInternalServerError, usecase.ErrInternalServer.Error())
+		return
+	}
+
+	writeSuccess(w, http.StatusAccepted, DeleteAccountSuccessResponse{
+		Message: usecase.ErrAccountPendingDeletion.Error(),
+		PurgeAt: purgeAt,
+	})
+}
@@ -1,7 +1,7 @@
 package repository
 
 import (
-	"os"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,29 +12,54 @@ type JWTAuthRepository struct {
 	secretKey string
 }
 
-// NewJWTAuthRepository creates a new JWT auth repository object
-func NewJWTAuthRepository() *JWTAuthRepository {
-	secret := os.Getenv("SECRET_KEY")
-	if secret == "" {
-		// Provide a default for local development. In production, this MUST be set.
-		secret = "a-very-secure-and-long-secret-key-for-dev"
-	}
-
-	return &JWTAuthRepository{secretKey: secret}
+// NewJWTAuthRepository creates a new JWT auth repository object signing tokens with the secret key
+func NewJWTAuthRepository(secretKey string) *JWTAuthRepository {
+	return &JWTAuthRepository{secretKey: secretKey}
 }
 
 // GenerateToken generates a JWT token
 func (r *JWTAuthRepository) GenerateToken(subject any, purpose string) (string, error) {
+	return r.GenerateTokenWithClaims(subject, purpose, time.Hour*24, nil)
+}
+
+// GenerateTokenWithClaims generates a JWT token with the given lifetime and additional claims
+func (r *JWTAuthRepository) GenerateTokenWithClaims(subject any, purpose string, duration time.Duration, extraClaims map[string]any) (string, error) {
 	// Create the token claims
-	claims := jwt.MapClaims{
-		"sub":     subject,                               // Subject
-		"iat":     time.Now().Unix(),                     // Issued At
-		"exp":     time.Now().Add(time.Hour * 24).Unix(), // Expiration Time
-		"purpose": purpose,
+	claims := jwt.MapClaims{}
+	for key, value := range extraClaims {
+		claims[key] = value
 	}
 
+	// Registered claims always win over additional claims
+	claims["sub"] = subject                         // Subject
+	claims["iat"] = time.Now().Unix()               // Issued At
+	claims["exp"] = time.Now().Add(duration).Unix() // Expiration Time
+	claims["purpose"] = purpose
+
 	// Create a new token object, specifying signing method and the claims
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	return token.SignedString([]byte(r.secretKey))
 }
+
+// ParseToken verifies a JWT token and returns its claims
+func (r *JWTAuthRepository) ParseToken(tokenString string) (map[string]any, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Ensure the signing method is what we expect (HMAC)
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+
+		return []byte(r.secretKey), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token claims")
+	}
+
+	return claims, nil
+}
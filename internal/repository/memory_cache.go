@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryCacheSweepSize is the number of entries above which expired entries are purged on write
+const memoryCacheSweepSize = 1024
+
+// memoryCacheEntry represents a cached value and its expiry
+type memoryCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// MemoryCache represents an in-process cache whose entries expire after a fixed TTL.
+// It is local to each instance, so it is only suited to short-lived values that are cheap to recompute.
+type MemoryCache[V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]memoryCacheEntry[V]
+}
+
+// NewMemoryCache creates a new in-memory cache object
+func NewMemoryCache[V any](ttl time.Duration) *MemoryCache[V] {
+	return &MemoryCache[V]{
+		ttl:     ttl,
+		entries: make(map[string]memoryCacheEntry[V]),
+	}
+}
+
+// Get returns the cached value if it hasn't expired
+func (c *MemoryCache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+// Set caches the value for the TTL of the cache, or until expiresAt when it is sooner and not zero
+func (c *MemoryCache[V]) Set(key string, value V, expiresAt time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= memoryCacheSweepSize {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	entryExpiresAt := now.Add(c.ttl)
+	if !expiresAt.IsZero() && expiresAt.Before(entryExpiresAt) {
+		entryExpiresAt = expiresAt
+	}
+
+	c.entries[key] = memoryCacheEntry[V]{value: value, expiresAt: entryExpiresAt}
+}
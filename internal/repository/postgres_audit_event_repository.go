@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditEventColumns lists the audit_events columns in the order expected by scanAuditEvent.
+// The user of the events of purged accounts is NULL, it is read as zero.
+const auditEventColumns = "id, COALESCE(user_id, 0), actor_id, action, details, ip_address, created_at"
+
+// PostgresAuditEventRepository represents the Postgres audit event repository object
+type PostgresAuditEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresAuditEventRepository creates a new Postgres audit event repository object
+func NewPostgresAuditEventRepository(db *pgxpool.Pool) *PostgresAuditEventRepository {
+	return &PostgresAuditEventRepository{db: db}
+}
+
+// Save saves the audit event
+func (r *PostgresAuditEventRepository) Save(ctx context.Context, event *domain.AuditEvent) error {
+	if event.Details == nil {
+		event.Details = map[string]any{}
+	}
+
+	sql := "INSERT INTO audit_events (user_id, actor_id, action, details, ip_address) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at"
+	return r.db.QueryRow(ctx, sql,
+		event.UserID,
+		event.ActorID,
+		event.Action,
+		event.Details,
+		event.IPAddress,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+// scanAuditEvent scans a single audit_events row selected with auditEventColumns
+func scanAuditEvent(row pgx.Row) (*domain.AuditEvent, error) {
+	var event domain.AuditEvent
+	err := row.Scan(
+		&event.ID,
+		&event.UserID,
+		&event.ActorID,
+		&event.Action,
+		&event.Details,
+		&event.IPAddress,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dataExportColumns lists the data_exports columns in the order expected by scanDataExport
+const dataExportColumns = "id, user_id, status, COALESCE(token_hash, ''), COALESCE(storage_key, ''), size_bytes, created_at, completed_at, expires_at"
+
+// failedDataExportRetention is how long failed exports stay visible in the export status
+const failedDataExportRetention = 7 * 24 * time.Hour
+
+// PostgresDataExportRepository represents the Postgres data export repository object
+type PostgresDataExportRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresDataExportRepository creates a new Postgres data export repository object
+func NewPostgresDataExportRepository(db *pgxpool.Pool) *PostgresDataExportRepository {
+	return &PostgresDataExportRepository{db: db}
+}
+
+// Generate generates a random download token
+func (r *PostgresDataExportRepository) Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash hashes the given token
+func (r *PostgresDataExportRepository) Hash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves a new data export
+func (r *PostgresDataExportRepository) Save(ctx context.Context, export *domain.DataExport) error {
+	sql := "INSERT INTO data_exports (user_id, status) VALUES ($1, $2) RETURNING id, created_at"
+	return r.db.QueryRow(ctx, sql, export.UserID, export.Status).Scan(&export.ID, &export.CreatedAt)
+}
+
+// FindByID finds the data export by id
+func (r *PostgresDataExportRepository) FindByID(ctx context.Context, id int64) (*domain.DataExport, error) {
+	sql := "SELECT " + dataExportColumns + " FROM data_exports WHERE id = $1"
+	return scanDataExport(r.db.QueryRow(ctx, sql, id))
+}
+
+// FindByUserID finds the data exports of the user, most recent first
+func (r *PostgresDataExportRepository) FindByUserID(ctx context.Context, userID int64) ([]domain.DataExport, error) {
+	sql := "SELECT " + dataExportColumns + " FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exports := make([]domain.DataExport, 0)
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, *export)
+	}
+
+	return exports, rows.Err()
+}
+
+// FindByToken finds the ready, unexpired data export by download token hash
+func (r *PostgresDataExportRepository) FindByToken(ctx context.Context, tokenHash string) (*domain.DataExport, error) {
+	sql := "SELECT " + dataExportColumns + " FROM data_exports WHERE token_hash = $1 AND status = $2 AND expires_at > NOW()"
+	return scanDataExport(r.db.QueryRow(ctx, sql, tokenHash, domain.DataExportStatusReady))
+}
+
+// UpdateStatus updates the status of the data export
+func (r *PostgresDataExportRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	sql := "UPDATE data_exports SET status = $1 WHERE id = $2"
+	_, err := r.db.Exec(ctx, sql, status, id)
+	return err
+}
+
+// Complete marks the data export as ready to be downloaded
+func (r *PostgresDataExportRepository) Complete(ctx context.Context, export *domain.DataExport) error {
+	sql := `UPDATE data_exports SET status = $1, token_hash = $2, storage_key = $3, size_bytes = $4, completed_at = NOW(), expires_at = $5
+		WHERE id = $6 RETURNING completed_at`
+	return r.db.QueryRow(ctx, sql,
+		domain.DataExportStatusReady,
+		export.TokenHash,
+		export.StorageKey,
+		export.SizeBytes,
+		export.ExpiresAt,
+		export.ID,
+	).Scan(&export.CompletedAt)
+}
+
+// DeleteExpired deletes the expired and long failed data exports and returns the storage keys of their archives
+func (r *PostgresDataExportRepository) DeleteExpired(ctx context.Context) ([]string, error) {
+	sql := "DELETE FROM data_exports WHERE expires_at <= NOW() OR (status = $1 AND created_at <= $2) RETURNING COALESCE(storage_key, '')"
+	rows, err := r.db.Query(ctx, sql, domain.DataExportStatusFailed, time.Now().Add(-failedDataExportRetention))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, rows.Err()
+}
+
+// scanDataExport scans a single data_exports row selected with dataExportColumns
+func scanDataExport(row pgx.Row) (*domain.DataExport, error) {
+	var export domain.DataExport
+	err := row.Scan(
+		&export.ID,
+		&export.UserID,
+		&export.Status,
+		&export.TokenHash,
+		&export.StorageKey,
+		&export.SizeBytes,
+		&export.CreatedAt,
+		&export.CompletedAt,
+		&export.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
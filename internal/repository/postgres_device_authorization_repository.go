@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// userCodeAlphabet avoids vowels and look-alike characters so user codes are easy to read and type (RFC 8628 section 6.1)
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// deviceAuthorizationColumns lists the device_authorizations columns in the order expected by scanDeviceAuthorization
+const deviceAuthorizationColumns = "id, device_code_hash, user_code_hash, client_id, scope, status, user_id, poll_interval, last_polled_at, expires_at, created_at"
+
+// PostgresDeviceAuthorizationRepository represents the Postgres device authorization repository object
+type PostgresDeviceAuthorizationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresDeviceAuthorizationRepository creates a new Postgres device authorization repository object
+func NewPostgresDeviceAuthorizationRepository(db *pgxpool.Pool) *PostgresDeviceAuthorizationRepository {
+	return &PostgresDeviceAuthorizationRepository{db: db}
+}
+
+// GenerateDeviceCode generates a random URL-safe device code
+func (r *PostgresDeviceAuthorizationRepository) GenerateDeviceCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// GenerateUserCode generates a random user code formatted as XXXX-XXXX
+func (r *PostgresDeviceAuthorizationRepository) GenerateUserCode() (string, error) {
+	// Bytes from 240 up are discarded: 240 is the largest multiple of the alphabet length below 256,
+	// so the modulo doesn't bias the characters
+	limit := byte(256 - 256%len(userCodeAlphabet))
+
+	code := make([]byte, 0, 9)
+	b := make([]byte, 16)
+	for len(code) < 9 {
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+
+		for _, value := range b {
+			if value >= limit || len(code) == 9 {
+				continue
+			}
+			if len(code) == 4 {
+				code = append(code, '-')
+			}
+			code = append(code, userCodeAlphabet[int(value)%len(userCodeAlphabet)])
+		}
+	}
+
+	return string(code), nil
+}
+
+// Hash hashes the code
+func (r *PostgresDeviceAuthorizationRepository) Hash(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves the device authorization and purges the expired ones
+func (r *PostgresDeviceAuthorizationRepository) Save(ctx context.Context, authorization *domain.DeviceAuthorization) error {
+	if _, err := r.db.Exec(ctx, "DELETE FROM device_authorizations WHERE expires_at < NOW() - INTERVAL '1 day'"); err != nil {
+		return err
+	}
+
+	sql := "INSERT INTO device_authorizations (device_code_hash, user_code_hash, client_id, scope, poll_interval, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, status, created_at"
+	return r.db.QueryRow(
+		ctx,
+		sql,
+		authorization.DeviceCodeHash,
+		authorization.UserCodeHash,
+		authorization.ClientID,
+		authorization.Scope,
+		authorization.Interval,
+		authorization.ExpiresAt,
+	).Scan(&authorization.ID, &authorization.Status, &authorization.CreatedAt)
+}
+
+// FindByDeviceCode finds the device authorization by device code hash
+func (r *PostgresDeviceAuthorizationRepository) FindByDeviceCode(ctx context.Context, deviceCodeHash string) (*domain.DeviceAuthorization, error) {
+	sql := "SELECT " + deviceAuthorizationColumns + " FROM device_authorizations WHERE device_code_hash = $1"
+	return scanDeviceAuthorization(r.db.QueryRow(ctx, sql, deviceCodeHash))
+}
+
+// FindPendingByUserCode finds the unexpired pending device authorization by user code hash
+func (r *PostgresDeviceAuthorizationRepository) FindPendingByUserCode(ctx context.Context, userCodeHash string) (*domain.DeviceAuthorization, error) {
+	sql := "SELECT " + deviceAuthorizationColumns + " FROM device_authorizations WHERE user_code_hash = $1 AND status = 'pending' AND expires_at > NOW()"
+	return scanDeviceAuthorization(r.db.QueryRow(ctx, sql, userCodeHash))
+}
+
+// Decide sets the outcome of a pending device authorization
+func (r *PostgresDeviceAuthorizationRepository) Decide(ctx context.Context, id int64, userID int64, status string) error {
+	query := "UPDATE device_authorizations SET status = $1, user_id = $2 WHERE id = $3 AND status = 'pending' AND expires_at > NOW()"
+	tag, err := r.db.Exec(ctx, query, status, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RecordPoll updates the last poll time and interval of the device authorization
+func (r *PostgresDeviceAuthorizationRepository) RecordPoll(ctx context.Context, id int64, interval int) error {
+	sql := "UPDATE device_authorizations SET last_polled_at = NOW(), poll_interval = $1 WHERE id = $2"
+	_, err := r.db.Exec(ctx, sql, interval, id)
+
+	return err
+}
+
+// Delete deletes the device authorization
+func (r *PostgresDeviceAuthorizationRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM device_authorizations WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// scanDeviceAuthorization scans a row selected with deviceAuthorizationColumns
+func scanDeviceAuthorization(row pgx.Row) (*domain.DeviceAuthorization, error) {
+	var authorization domain.DeviceAuthorization
+	err := row.Scan(
+		&authorization.ID,
+		&authorization.DeviceCodeHash,
+		&authorization.UserCodeHash,
+		&authorization.ClientID,
+		&authorization.Scope,
+		&authorization.Status,
+		&authorization.UserID,
+		&authorization.Interval,
+		&authorization.LastPolledAt,
+		&authorization.ExpiresAt,
+		&authorization.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authorization, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// emailChangeColumns lists the email_changes columns in the order expected by scanEmailChange
+const emailChangeColumns = "id, user_id, old_email, new_email, confirm_token_hash, revert_token_hash, expires_at, revert_expires_at, confirmed_at, created_at"
+
+// uniqueViolation is the Postgres error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// PostgresEmailChangeRepository represents the Postgres email change repository object
+type PostgresEmailChangeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresEmailChangeRepository creates a new Postgres email change repository object
+func NewPostgresEmailChangeRepository(db *pgxpool.Pool) *PostgresEmailChangeRepository {
+	return &PostgresEmailChangeRepository{db: db}
+}
+
+// Generate generates a random confirmation or revert token
+func (r *PostgresEmailChangeRepository) Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash hashes the given token
+func (r *PostgresEmailChangeRepository) Hash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves the email change, replacing the user's other unconfirmed changes
+func (r *PostgresEmailChangeRepository) Save(ctx context.Context, change *domain.EmailChange) error {
+	sql := "DELETE FROM email_changes WHERE user_id = $1 AND confirmed_at IS NULL"
+	if _, err := r.db.Exec(ctx, sql, change.UserID); err != nil {
+		return err
+	}
+
+	sql = `INSERT INTO email_changes (user_id, old_email, new_email, confirm_token_hash, revert_token_hash, expires_at, revert_expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	return r.db.QueryRow(ctx, sql,
+		change.UserID,
+		change.OldEmail,
+		change.NewEmail,
+		change.ConfirmTokenHash,
+		change.RevertTokenHash,
+		change.ExpiresAt,
+		change.RevertExpiresAt,
+	).Scan(&change.ID, &change.CreatedAt)
+}
+
+// FindByConfirmToken finds the unconfirmed, unexpired email change by confirmation token hash
+func (r *PostgresEmailChangeRepository) FindByConfirmToken(ctx context.Context, tokenHash string) (*domain.EmailChange, error) {
+	sql := "SELECT " + emailChangeColumns + " FROM email_changes WHERE confirm_token_hash = $1 AND confirmed_at IS NULL AND expires_at > NOW()"
+	return scanEmailChange(r.db.QueryRow(ctx, sql, tokenHash))
+}
+
+// FindByRevertToken finds the email change by revert token hash, while it can still be reverted
+func (r *PostgresEmailChangeRepository) FindByRevertToken(ctx context.Context, tokenHash string) (*domain.EmailChange, error) {
+	sql := "SELECT " + emailChangeColumns + " FROM email_changes WHERE revert_token_hash = $1 AND revert_expires_at > NOW()"
+	return scanEmailChange(r.db.QueryRow(ctx, sql, tokenHash))
+}
+
+// Confirm applies the email change in a single transaction. An unverified account holding the new address,
+// i.e. an abandoned registration, is released. It returns false when a verified user already owns the address.
+func (r *PostgresEmailChangeRepository) Confirm(ctx context.Context, change *domain.EmailChange) (bool, error) {
+	applied := false
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := "DELETE FROM users WHERE email = $1 AND verified = FALSE AND id <> $2"
+		if _, err := tx.Exec(ctx, query, change.NewEmail, change.UserID); err != nil {
+			return err
+		}
+
+		query = "UPDATE users SET email = $1, verified = TRUE WHERE id = $2 AND email = $3"
+		tag, err := tx.Exec(ctx, query, change.NewEmail, change.UserID, change.OldEmail)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			// The email was changed some other way in the meantime
+			return sql.ErrNoRows
+		}
+
+		query = "UPDATE email_changes SET confirmed_at = NOW() WHERE id = $1"
+		if _, err := tx.Exec(ctx, query, change.ID); err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return false, nil
+	}
+
+	return applied, err
+}
+
+// Revert restores the old email address if the change was applied, whatever the address is now,
+// then deletes every email change of the user so the links of later changes can't undo it.
+// It returns false when the old address has been taken by another account since, and sql.ErrNoRows without the user.
+func (r *PostgresEmailChangeRepository) Revert(ctx context.Context, change *domain.EmailChange) (bool, error) {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if change.IsConfirmed() {
+			query := "UPDATE users SET email = $1, verified = TRUE WHERE id = $2"
+			tag, err := tx.Exec(ctx, query, change.OldEmail, change.UserID)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return sql.ErrNoRows
+			}
+		}
+
+		_, err := tx.Exec(ctx, "DELETE FROM email_changes WHERE user_id = $1", change.UserID)
+		return err
+	})
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// scanEmailChange scans a single email_changes row selected with emailChangeColumns
+func scanEmailChange(row pgx.Row) (*domain.EmailChange, error) {
+	var change domain.EmailChange
+	err := row.Scan(
+		&change.ID,
+		&change.UserID,
+		&change.OldEmail,
+		&change.NewEmail,
+		&change.ConfirmTokenHash,
+		&change.RevertTokenHash,
+		&change.ExpiresAt,
+		&change.RevertExpiresAt,
+		&change.ConfirmedAt,
+		&change.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &change, nil
+}
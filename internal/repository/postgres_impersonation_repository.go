@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// impersonationColumns lists the impersonations columns in the order expected by scanImpersonation
+const impersonationColumns = "id, COALESCE(admin_id, 0), user_id, reason, scopes, created_at, expires_at, ended_at"
+
+// PostgresImpersonationRepository represents the Postgres impersonation repository object
+type PostgresImpersonationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresImpersonationRepository creates a new Postgres impersonation repository object
+func NewPostgresImpersonationRepository(db *pgxpool.Pool) *PostgresImpersonationRepository {
+	return &PostgresImpersonationRepository{db: db}
+}
+
+// Save saves a new impersonation
+func (r *PostgresImpersonationRepository) Save(ctx context.Context, impersonation *domain.Impersonation) error {
+	sql := "INSERT INTO impersonations (admin_id, user_id, reason, scopes, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at"
+	return r.db.QueryRow(ctx, sql,
+		impersonation.AdminID,
+		impersonation.UserID,
+		impersonation.Reason,
+		impersonation.Scopes,
+		impersonation.ExpiresAt,
+	).Scan(&impersonation.ID, &impersonation.CreatedAt)
+}
+
+// FindByID finds the impersonation by id
+func (r *PostgresImpersonationRepository) FindByID(ctx context.Context, id int64) (*domain.Impersonation, error) {
+	sql := "SELECT " + impersonationColumns + " FROM impersonations WHERE id = $1"
+	return scanImpersonation(r.db.QueryRow(ctx, sql, id))
+}
+
+// End marks the impersonation as ended, it returns false when it had already ended
+func (r *PostgresImpersonationRepository) End(ctx context.Context, id int64) (bool, error) {
+	sql := "UPDATE impersonations SET ended_at = NOW() WHERE id = $1 AND ended_at IS NULL"
+	tag, err := r.db.Exec(ctx, sql, id)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// scanImpersonation scans a single impersonations row selected with impersonationColumns
+func scanImpersonation(row pgx.Row) (*domain.Impersonation, error) {
+	var impersonation domain.Impersonation
+	err := row.Scan(
+		&impersonation.ID,
+		&impersonation.AdminID,
+		&impersonation.UserID,
+		&impersonation.Reason,
+		&impersonation.Scopes,
+		&impersonation.CreatedAt,
+		&impersonation.ExpiresAt,
+		&impersonation.EndedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &impersonation, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// knownDeviceColumns lists the known_devices columns in the order expected by scanKnownDevice
+const knownDeviceColumns = "id, user_id, device_id_hash, user_agent_family, ip_address, location, first_seen_at, last_seen_at"
+
+// PostgresKnownDeviceRepository represents the Postgres known device repository object
+type PostgresKnownDeviceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresKnownDeviceRepository creates a new Postgres known device repository object
+func NewPostgresKnownDeviceRepository(db *pgxpool.Pool) *PostgresKnownDeviceRepository {
+	return &PostgresKnownDeviceRepository{db: db}
+}
+
+// Hash hashes the given device ID
+func (r *PostgresKnownDeviceRepository) Hash(deviceID string) string {
+	hash := sha256.Sum256([]byte(deviceID))
+	return fmt.Sprintf("%x", hash)
+}
+
+// HasAny reports whether any device has been recorded for the user
+func (r *PostgresKnownDeviceRepository) HasAny(ctx context.Context, userID int64) (bool, error) {
+	sql := "SELECT EXISTS (SELECT 1 FROM known_devices WHERE user_id = $1)"
+
+	var exists bool
+	err := r.db.QueryRow(ctx, sql, userID).Scan(&exists)
+	return exists, err
+}
+
+// HasSimilar reports whether the user signed in from the IP address with the same user agent family before
+func (r *PostgresKnownDeviceRepository) HasSimilar(ctx context.Context, userID int64, userAgentFamily string, ipAddress string) (bool, error) {
+	sql := "SELECT EXISTS (SELECT 1 FROM known_devices WHERE user_id = $1 AND user_agent_family = $2 AND ip_address = $3)"
+
+	var exists bool
+	err := r.db.QueryRow(ctx, sql, userID, userAgentFamily, ipAddress).Scan(&exists)
+	return exists, err
+}
+
+// Upsert records a sign-in from the device, updating its last address, and reports whether the device is new
+func (r *PostgresKnownDeviceRepository) Upsert(ctx context.Context, device *domain.KnownDevice) (bool, error) {
+	// xmax is only zero for rows inserted by this statement
+	sql := `INSERT INTO known_devices (user_id, device_id_hash, user_agent_family, ip_address, location) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, device_id_hash, user_agent_family)
+		DO UPDATE SET ip_address = EXCLUDED.ip_address, location = EXCLUDED.location, last_seen_at = NOW()
+		RETURNING id, first_seen_at, last_seen_at, xmax = 0`
+
+	var inserted bool
+	err := r.db.QueryRow(ctx, sql,
+		device.UserID,
+		device.DeviceIDHash,
+		device.UserAgentFamily,
+		device.IPAddress,
+		device.Location,
+	).Scan(&device.ID, &device.FirstSeenAt, &device.LastSeenAt, &inserted)
+
+	return inserted, err
+}
+
+// Delete forgets the device of the user
+func (r *PostgresKnownDeviceRepository) Delete(ctx context.Context, userID int64, deviceID int64) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM known_devices WHERE id = $1 AND user_id = $2", deviceID, userID)
+	return err
+}
+
+// MarkSecureAccountTokenUsed records the hash of a "secure my account" token, expired entries are purged along the way
+func (r *PostgresKnownDeviceRepository) MarkSecureAccountTokenUsed(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) (bool, error) {
+	if _, err := r.db.Exec(ctx, "DELETE FROM used_secure_account_tokens WHERE expires_at < NOW()"); err != nil {
+		return false, err
+	}
+
+	sql := "INSERT INTO used_secure_account_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+	tag, err := r.db.Exec(ctx, sql, tokenHash, userID, expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// scanKnownDevice scans a single known_devices row selected with knownDeviceColumns
+func scanKnownDevice(row pgx.Row) (*domain.KnownDevice, error) {
+	var device domain.KnownDevice
+	err := row.Scan(
+		&device.ID,
+		&device.UserID,
+		&device.DeviceIDHash,
+		&device.UserAgentFamily,
+		&device.IPAddress,
+		&device.Location,
+		&device.FirstSeenAt,
+		&device.LastSeenAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// loginEventColumns lists the login_events columns in the order expected by scanLoginEvent
+const loginEventColumns = "id, user_id, ip_address, country_code, latitude, longitude, risk_score, risk_reasons, decision, succeeded, created_at"
+
+// PostgresLoginEventRepository represents the Postgres login event repository object
+type PostgresLoginEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresLoginEventRepository creates a new Postgres login event repository object
+func NewPostgresLoginEventRepository(db *pgxpool.Pool) *PostgresLoginEventRepository {
+	return &PostgresLoginEventRepository{db: db}
+}
+
+// Save saves the login event
+func (r *PostgresLoginEventRepository) Save(ctx context.Context, event *domain.LoginEvent) error {
+	reasons := event.RiskReasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	sql := `INSERT INTO login_events (user_id, ip_address, country_code, latitude, longitude, risk_score, risk_reasons, decision, succeeded)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+	return r.db.QueryRow(ctx, sql,
+		event.UserID,
+		event.IPAddress,
+		event.CountryCode,
+		event.Latitude,
+		event.Longitude,
+		event.RiskScore,
+		reasons,
+		event.Decision,
+		event.Succeeded,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+// FindLastSucceeded finds the latest login that signed the user in
+func (r *PostgresLoginEventRepository) FindLastSucceeded(ctx context.Context, userID int64) (*domain.LoginEvent, error) {
+	sql := "SELECT " + loginEventColumns + " FROM login_events WHERE user_id = $1 AND succeeded = TRUE ORDER BY created_at DESC LIMIT 1"
+	return scanLoginEvent(r.db.QueryRow(ctx, sql, userID))
+}
+
+// FindSucceededCountries returns the countries the user signed in from
+func (r *PostgresLoginEventRepository) FindSucceededCountries(ctx context.Context, userID int64) ([]string, error) {
+	sql := "SELECT DISTINCT country_code FROM login_events WHERE user_id = $1 AND succeeded = TRUE AND country_code <> ''"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	countries := make([]string, 0)
+	for rows.Next() {
+		var country string
+		if err := rows.Scan(&country); err != nil {
+			return nil, err
+		}
+		countries = append(countries, country)
+	}
+
+	return countries, rows.Err()
+}
+
+// CountSince counts the logins of the user since the given time
+func (r *PostgresLoginEventRepository) CountSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	sql := "SELECT COUNT(*) FROM login_events WHERE user_id = $1 AND created_at >= $2"
+
+	var count int
+	err := r.db.QueryRow(ctx, sql, userID, since).Scan(&count)
+	return count, err
+}
+
+// MarkSucceeded records that the login signed the user in
+func (r *PostgresLoginEventRepository) MarkSucceeded(ctx context.Context, eventID int64) error {
+	_, err := r.db.Exec(ctx, "UPDATE login_events SET succeeded = TRUE WHERE id = $1", eventID)
+	return err
+}
+
+// scanLoginEvent scans a single login_events row selected with loginEventColumns
+func scanLoginEvent(row pgx.Row) (*domain.LoginEvent, error) {
+	var event domain.LoginEvent
+	err := row.Scan(
+		&event.ID,
+		&event.UserID,
+		&event.IPAddress,
+		&event.CountryCode,
+		&event.Latitude,
+		&event.Longitude,
+		&event.RiskScore,
+		&event.RiskReasons,
+		&event.Decision,
+		&event.Succeeded,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
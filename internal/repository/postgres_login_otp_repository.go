@@ -8,6 +8,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -47,28 +48,41 @@ func (r *PostgresLoginOTPRepository) Hash(code string) string {
 	return fmt.Sprintf("%x", hash)
 }
 
-// Save saves the code
+// Save saves the code, replacing the previous ones of the email.
+// The attempts of a live previous code carry over, so asking for a new code doesn't reset them.
 func (r *PostgresLoginOTPRepository) Save(ctx context.Context, email string, codeHash string, duration time.Duration) error {
-	sql := "INSERT INTO login_otps (email, code_hash, expires_at) VALUES ($1, $2, $3)"
-	_, err := r.db.Exec(ctx, sql, email, codeHash, time.Now().Add(duration))
-
-	if err != nil {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var attempts int
+		sql := "SELECT COALESCE(MAX(attempts), 0) FROM login_otps WHERE email = $1 AND expires_at > NOW()"
+		if err := tx.QueryRow(ctx, sql, email).Scan(&attempts); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM login_otps WHERE email = $1", email); err != nil {
+			return err
+		}
+
+		sql = "INSERT INTO login_otps (email, code_hash, attempts, expires_at) VALUES ($1, $2, $3, $4)"
+		_, err := tx.Exec(ctx, sql, email, codeHash, attempts, time.Now().Add(duration))
 		return err
-	}
+	})
+}
 
-	return nil
+// IncrementAttempts spends an attempt on the live code of the email, the row lock serializes parallel guesses
+func (r *PostgresLoginOTPRepository) IncrementAttempts(ctx context.Context, email string, maxAttempts int) error {
+	sql := `UPDATE login_otps SET attempts = attempts + 1
+		WHERE email = $1 AND expires_at > NOW() AND attempts < $2 RETURNING id`
+
+	var id int64
+	return r.db.QueryRow(ctx, sql, email, maxAttempts).Scan(&id)
 }
 
-// IsCodeExist checks if the code exists
-func (r *PostgresLoginOTPRepository) IsCodeExist(ctx context.Context, codeHash string) error {
-	sql := "SELECT EXISTS (SELECT 1 FROM login_otps WHERE code_hash = $1)"
-	var exist bool
-	err := r.db.QueryRow(ctx, sql, codeHash).Scan(&exist)
-	if err != nil {
-		return err
-	}
+// DeleteByHash deletes the code of the email if it matches, so it can only be used once
+func (r *PostgresLoginOTPRepository) DeleteByHash(ctx context.Context, email string, codeHash string) error {
+	sql := "DELETE FROM login_otps WHERE email = $1 AND code_hash = $2 AND expires_at > NOW() RETURNING id"
 
-	return nil
+	var id int64
+	return r.db.QueryRow(ctx, sql, email, codeHash).Scan(&id)
 }
 
 // Delete deletes the code
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresMFAChallengeRepository represents the Postgres MFA challenge repository object
+type PostgresMFAChallengeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresMFAChallengeRepository creates a new Postgres MFA challenge repository object
+func NewPostgresMFAChallengeRepository(db *pgxpool.Pool) *PostgresMFAChallengeRepository {
+	return &PostgresMFAChallengeRepository{db: db}
+}
+
+// Generate generates a random numeric code of length
+func (r *PostgresMFAChallengeRepository) Generate(length int) (string, error) {
+	var numbers = [...]byte{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}
+	b := make([]byte, length)
+	n, err := io.ReadAtLeast(rand.Reader, b, length)
+
+	if n != length {
+		return "", err
+	}
+
+	for i := 0; i < len(b); i++ {
+		b[i] = numbers[int(b[i])%len(numbers)]
+	}
+
+	return string(b), nil
+}
+
+// Hash hashes the code
+func (r *PostgresMFAChallengeRepository) Hash(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return fmt.Sprintf("%x", hash)
+}
+
+// SaveIfAllowed saves the challenge, discarding the expired challenges of the user.
+// Challenges that ran out of attempts still count until they expire, which caps the guesses per user.
+func (r *PostgresMFAChallengeRepository) SaveIfAllowed(ctx context.Context, challenge *domain.MFAChallenge, maxOpen int) (bool, error) {
+	allowed := false
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// Locking the user serializes the logins waiting for a second factor
+		if _, err := tx.Exec(ctx, "SELECT 1 FROM users WHERE id = $1 FOR UPDATE", challenge.UserID); err != nil {
+			return err
+		}
+
+		sql := "DELETE FROM mfa_challenges WHERE user_id = $1 AND expires_at <= NOW()"
+		if _, err := tx.Exec(ctx, sql, challenge.UserID); err != nil {
+			return err
+		}
+
+		var open int
+		sql = "SELECT COUNT(*) FROM mfa_challenges WHERE user_id = $1"
+		if err := tx.QueryRow(ctx, sql, challenge.UserID).Scan(&open); err != nil {
+			return err
+		}
+		if open >= maxOpen {
+			return nil
+		}
+
+		sql = `INSERT INTO mfa_challenges (user_id, login_event_id, code_hash, channel, remember_me, use_session, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, attempts, created_at`
+		err := tx.QueryRow(ctx, sql,
+			challenge.UserID,
+			challenge.LoginEventID,
+			challenge.CodeHash,
+			challenge.Channel,
+			challenge.RememberMe,
+			challenge.UseSession,
+			challenge.ExpiresAt,
+		).Scan(&challenge.ID, &challenge.Attempts, &challenge.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		allowed = true
+		return nil
+	})
+
+	return allowed, err
+}
+
+// FindByID finds the challenge by ID
+func (r *PostgresMFAChallengeRepository) FindByID(ctx context.Context, id int64) (*domain.MFAChallenge, error) {
+	sql := `SELECT id, user_id, login_event_id, code_hash, channel, remember_me, use_session, attempts, expires_at, created_at
+		FROM mfa_challenges WHERE id = $1`
+
+	var challenge domain.MFAChallenge
+	err := r.db.QueryRow(ctx, sql, id).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.LoginEventID,
+		&challenge.CodeHash,
+		&challenge.Channel,
+		&challenge.RememberMe,
+		&challenge.UseSession,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// IncrementAttempts spends an attempt on the challenge, the row lock serializes parallel guesses
+func (r *PostgresMFAChallengeRepository) IncrementAttempts(ctx context.Context, id int64, maxAttempts int) error {
+	sql := `UPDATE mfa_challenges SET attempts = attempts + 1
+		WHERE id = $1 AND expires_at > NOW() AND attempts < $2 RETURNING id`
+	return r.db.QueryRow(ctx, sql, id, maxAttempts).Scan(&id)
+}
+
+// DeleteByHash deletes the challenge if the code matches, so only one request can complete it
+func (r *PostgresMFAChallengeRepository) DeleteByHash(ctx context.Context, id int64, codeHash string) error {
+	sql := "DELETE FROM mfa_challenges WHERE id = $1 AND code_hash = $2 AND expires_at > NOW() RETURNING id"
+	return r.db.QueryRow(ctx, sql, id, codeHash).Scan(&id)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// personalAccessTokenColumns lists the personal_access_tokens columns in the order expected by scanPersonalAccessToken
+const personalAccessTokenColumns = "id, user_id, name, token_hash, scopes, expires_at, last_used_at, last_used_ip, created_at"
+
+// PostgresPersonalAccessTokenRepository represents the Postgres personal access token repository object
+type PostgresPersonalAccessTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresPersonalAccessTokenRepository creates a new Postgres personal access token repository object
+func NewPostgresPersonalAccessTokenRepository(db *pgxpool.Pool) *PostgresPersonalAccessTokenRepository {
+	return &PostgresPersonalAccessTokenRepository{db: db}
+}
+
+// Generate generates a random prefixed token
+func (r *PostgresPersonalAccessTokenRepository) Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return domain.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash hashes the given token
+func (r *PostgresPersonalAccessTokenRepository) Hash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves the personal access token to the database
+func (r *PostgresPersonalAccessTokenRepository) Save(ctx context.Context, token *domain.PersonalAccessToken) error {
+	sql := "INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at"
+	return r.db.QueryRow(ctx, sql, token.UserID, token.Name, token.TokenHash, token.Scopes, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+}
+
+// FindByToken finds the personal access token by token hash
+func (r *PostgresPersonalAccessTokenRepository) FindByToken(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	sql := "SELECT " + personalAccessTokenColumns + " FROM personal_access_tokens WHERE token_hash = $1 AND expires_at > NOW()"
+	return scanPersonalAccessToken(r.db.QueryRow(ctx, sql, tokenHash))
+}
+
+// FindByUserID finds the personal access tokens owned by the user
+func (r *PostgresPersonalAccessTokenRepository) FindByUserID(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error) {
+	sql := "SELECT " + personalAccessTokenColumns + " FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]domain.PersonalAccessToken, 0)
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// UpdateLastUsed records the last time and IP address the token was used from
+func (r *PostgresPersonalAccessTokenRepository) UpdateLastUsed(ctx context.Context, tokenID int64, ip string) error {
+	sql := "UPDATE personal_access_tokens SET last_used_at = NOW(), last_used_ip = $1 WHERE id = $2"
+	_, err := r.db.Exec(ctx, sql, ip, tokenID)
+	return err
+}
+
+// Delete deletes the personal access token owned by the user
+func (r *PostgresPersonalAccessTokenRepository) Delete(ctx context.Context, userID int64, tokenID int64) error {
+	query := "DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2"
+	tag, err := r.db.Exec(ctx, query, tokenID, userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteByUserID deletes every token owned by the user
+func (r *PostgresPersonalAccessTokenRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM personal_access_tokens WHERE user_id = $1", userID)
+	return err
+}
+
+// scanPersonalAccessToken scans a single personal_access_tokens row selected with personalAccessTokenColumns
+func scanPersonalAccessToken(row pgx.Row) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenHash,
+		&token.Scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.LastUsedIP,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresPersonalDataRepository represents the Postgres repository collecting everything stored about a user
+type PostgresPersonalDataRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresPersonalDataRepository creates a new Postgres personal data repository object
+func NewPostgresPersonalDataRepository(db *pgxpool.Pool) *PostgresPersonalDataRepository {
+	return &PostgresPersonalDataRepository{db: db}
+}
+
+// Collect gathers the personal data of the user from every table that references them
+func (r *PostgresPersonalDataRepository) Collect(ctx context.Context, userID int64) (*domain.PersonalData, error) {
+	user, err := scanUser(r.db.QueryRow(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", userID))
+	if err != nil {
+		return nil, err
+	}
+
+	data := &domain.PersonalData{
+		ExportedAt: time.Now(),
+		User:       user,
+		Identities: []domain.PersonalDataIdentity{{Provider: user.AuthSource, Subject: user.Email}},
+	}
+
+	if data.Sessions, err = r.findSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.RememberTokens, err = r.findRememberTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.PersonalAccessTokens, err = r.findPersonalAccessTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.ServiceAccounts, err = r.findServiceAccounts(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.EmailChanges, err = r.findEmailChanges(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.PasswordChanges, err = r.findPasswordChanges(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.AuditEvents, err = r.findAuditEvents(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.KnownDevices, err = r.findKnownDevices(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.LoginEvents, err = r.findLoginEvents(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.TrustedDevices, err = r.findTrustedDevices(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.Consents, err = r.findConsents(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *PostgresPersonalDataRepository) findSessions(ctx context.Context, userID int64) ([]domain.PersonalDataSession, error) {
+	sql := "SELECT id, persistent, created_at, last_seen_at, expires_at FROM sessions WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]domain.PersonalDataSession, 0)
+	for rows.Next() {
+		var session domain.PersonalDataSession
+		if err := rows.Scan(&session.ID, &session.Persistent, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findRememberTokens(ctx context.Context, userID int64) ([]domain.PersonalDataRememberToken, error) {
+	sql := "SELECT id, expires_at FROM remember_tokens WHERE user_id = $1 ORDER BY id DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]domain.PersonalDataRememberToken, 0)
+	for rows.Next() {
+		var token domain.PersonalDataRememberToken
+		if err := rows.Scan(&token.ID, &token.ExpiresAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findPersonalAccessTokens(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error) {
+	sql := "SELECT " + personalAccessTokenColumns + " FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]domain.PersonalAccessToken, 0)
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findServiceAccounts(ctx context.Context, userID int64) ([]domain.ServiceAccount, error) {
+	sql := "SELECT " + serviceAccountColumns + " FROM service_accounts WHERE owner_user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make([]domain.ServiceAccount, 0)
+	for rows.Next() {
+		account, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findEmailChanges(ctx context.Context, userID int64) ([]domain.PersonalDataEmailChange, error) {
+	sql := "SELECT old_email, new_email, created_at, confirmed_at FROM email_changes WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]domain.PersonalDataEmailChange, 0)
+	for rows.Next() {
+		var change domain.PersonalDataEmailChange
+		if err := rows.Scan(&change.OldEmail, &change.NewEmail, &change.CreatedAt, &change.ConfirmedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// findPasswordChanges returns when the user's previous passwords were replaced, the hashes are left out
+func (r *PostgresPersonalDataRepository) findPasswordChanges(ctx context.Context, userID int64) ([]time.Time, error) {
+	sql := "SELECT created_at FROM password_histories WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]time.Time, 0)
+	for rows.Next() {
+		var changedAt time.Time
+		if err := rows.Scan(&changedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, changedAt)
+	}
+
+	return changes, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findAuditEvents(ctx context.Context, userID int64) ([]domain.AuditEvent, error) {
+	sql := "SELECT " + auditEventColumns + " FROM audit_events WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]domain.AuditEvent, 0)
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findKnownDevices(ctx context.Context, userID int64) ([]domain.KnownDevice, error) {
+	sql := "SELECT " + knownDeviceColumns + " FROM known_devices WHERE user_id = $1 ORDER BY last_seen_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]domain.KnownDevice, 0)
+	for rows.Next() {
+		device, err := scanKnownDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, *device)
+	}
+
+	return devices, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findLoginEvents(ctx context.Context, userID int64) ([]domain.LoginEvent, error) {
+	sql := "SELECT " + loginEventColumns + " FROM login_events WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]domain.LoginEvent, 0)
+	for rows.Next() {
+		event, err := scanLoginEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findTrustedDevices(ctx context.Context, userID int64) ([]domain.TrustedDevice, error) {
+	sql := "SELECT " + trustedDeviceColumns + " FROM trusted_devices WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]domain.TrustedDevice, 0)
+	for rows.Next() {
+		device, err := scanTrustedDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, *device)
+	}
+
+	return devices, rows.Err()
+}
+
+func (r *PostgresPersonalDataRepository) findConsents(ctx context.Context, userID int64) ([]domain.PersonalDataConsent, error) {
+	sql := "SELECT client_id, scope, status, created_at FROM device_authorizations WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consents := make([]domain.PersonalDataConsent, 0)
+	for rows.Next() {
+		var consent domain.PersonalDataConsent
+		if err := rows.Scan(&consent.ClientID, &consent.Scope, &consent.Status, &consent.CreatedAt); err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+
+	return consents, rows.Err()
+}
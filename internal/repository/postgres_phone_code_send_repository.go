@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresPhoneCodeSendRepository represents the Postgres phone code send repository object
+type PostgresPhoneCodeSendRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresPhoneCodeSendRepository creates a new Postgres phone code send repository object
+func NewPostgresPhoneCodeSendRepository(db *pgxpool.Pool) *PostgresPhoneCodeSendRepository {
+	return &PostgresPhoneCodeSendRepository{db: db}
+}
+
+// SaveIfAllowed records a code sent to the phone number if the limits allow it.
+// An advisory lock on the number serializes parallel requests, so they can't all pass the check.
+func (r *PostgresPhoneCodeSendRepository) SaveIfAllowed(
+	ctx context.Context,
+	phoneNumber string,
+	channel string,
+	minInterval time.Duration,
+	limit int,
+	window time.Duration,
+) (bool, error) {
+	allowed := false
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", phoneNumber); err != nil {
+			return err
+		}
+
+		sql := "SELECT COUNT(*) FROM phone_code_sends WHERE phone_number = $1 AND created_at >= $2"
+		if minInterval > 0 {
+			var count int
+			if err := tx.QueryRow(ctx, sql, phoneNumber, time.Now().Add(-minInterval)).Scan(&count); err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+		}
+		if limit > 0 && window > 0 {
+			var count int
+			if err := tx.QueryRow(ctx, sql, phoneNumber, time.Now().Add(-window)).Scan(&count); err != nil {
+				return err
+			}
+			if count >= limit {
+				return nil
+			}
+		}
+
+		sql = "INSERT INTO phone_code_sends (phone_number, channel) VALUES ($1, $2)"
+		if _, err := tx.Exec(ctx, sql, phoneNumber, channel); err != nil {
+			return err
+		}
+
+		allowed = true
+		return nil
+	})
+
+	return allowed, err
+}
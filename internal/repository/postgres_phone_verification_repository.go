@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresPhoneVerificationRepository represents the Postgres phone verification repository object
+type PostgresPhoneVerificationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresPhoneVerificationRepository creates a new Postgres phone verification repository object
+func NewPostgresPhoneVerificationRepository(db *pgxpool.Pool) *PostgresPhoneVerificationRepository {
+	return &PostgresPhoneVerificationRepository{db: db}
+}
+
+// Generate generates a random numeric code of length
+func (r *PostgresPhoneVerificationRepository) Generate(length int) (string, error) {
+	var numbers = [...]byte{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}
+	b := make([]byte, length)
+	n, err := io.ReadAtLeast(rand.Reader, b, length)
+
+	if n != length {
+		return "", err
+	}
+
+	for i := 0; i < len(b); i++ {
+		b[i] = numbers[int(b[i])%len(numbers)]
+	}
+
+	return string(b), nil
+}
+
+// Hash hashes the code
+func (r *PostgresPhoneVerificationRepository) Hash(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves the verification, replacing the pending one of the user.
+// The attempts of a live pending verification carry over, so asking for a new code doesn't reset them.
+func (r *PostgresPhoneVerificationRepository) Save(ctx context.Context, verification *domain.PhoneVerification) error {
+	sql := `INSERT INTO phone_verifications (user_id, phone_number, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET phone_number = EXCLUDED.phone_number, code_hash = EXCLUDED.code_hash,
+			attempts = CASE WHEN phone_verifications.expires_at > NOW() THEN phone_verifications.attempts ELSE 0 END,
+			expires_at = EXCLUDED.expires_at, created_at = NOW()
+		RETURNING attempts, created_at`
+	return r.db.QueryRow(ctx, sql,
+		verification.UserID,
+		verification.PhoneNumber,
+		verification.CodeHash,
+		verification.ExpiresAt,
+	).Scan(&verification.Attempts, &verification.CreatedAt)
+}
+
+// IncrementAttempts spends an attempt on the pending verification, the row lock serializes parallel guesses
+func (r *PostgresPhoneVerificationRepository) IncrementAttempts(ctx context.Context, userID int64, maxAttempts int) error {
+	sql := `UPDATE phone_verifications SET attempts = attempts + 1
+		WHERE user_id = $1 AND expires_at > NOW() AND attempts < $2 RETURNING user_id`
+	return r.db.QueryRow(ctx, sql, userID, maxAttempts).Scan(&userID)
+}
+
+// DeleteByHash deletes the pending verification of the user if the code matches, so it can only be used once
+func (r *PostgresPhoneVerificationRepository) DeleteByHash(ctx context.Context, userID int64, codeHash string) (*domain.PhoneVerification, error) {
+	sql := `DELETE FROM phone_verifications WHERE user_id = $1 AND code_hash = $2 AND expires_at > NOW()
+		RETURNING user_id, phone_number, code_hash, attempts, expires_at, created_at`
+
+	var verification domain.PhoneVerification
+	err := r.db.QueryRow(ctx, sql, userID, codeHash).Scan(
+		&verification.UserID,
+		&verification.PhoneNumber,
+		&verification.CodeHash,
+		&verification.Attempts,
+		&verification.ExpiresAt,
+		&verification.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &verification, nil
+}
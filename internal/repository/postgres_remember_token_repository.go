@@ -41,21 +41,21 @@ func (r *PostgresRememberTokenRepository) Hash(token string) string {
 }
 
 // Save saves the remember token to the database
-func (r *PostgresRememberTokenRepository) Save(ctx context.Context, userID int64, tokenHash string, duration time.Duration) error {
-	sql := "INSERT INTO remember_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)"
+func (r *PostgresRememberTokenRepository) Save(ctx context.Context, userID int64, clientID string, tokenHash string, duration time.Duration) error {
+	sql := "INSERT INTO remember_tokens (user_id, client_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)"
 	expiresAt := time.Now().Add(duration)
-	_, err := r.db.Exec(ctx, sql, userID, tokenHash, expiresAt)
+	_, err := r.db.Exec(ctx, sql, userID, clientID, tokenHash, expiresAt)
 
 	return err
 }
 
 // FindByToken finds the remember token by token hash
 func (r *PostgresRememberTokenRepository) FindByToken(ctx context.Context, hashToken string) (*domain.RememberToken, error) {
-	sql := "SELECT * FROM remember_tokens WHERE token_hash = $1 AND expires_at > NOW()"
+	sql := "SELECT id, user_id, client_id, token_hash, expires_at FROM remember_tokens WHERE token_hash = $1 AND expires_at > NOW()"
 
 	row := r.db.QueryRow(ctx, sql, hashToken)
 	var rememberToken domain.RememberToken
-	err := row.Scan(&rememberToken.ID, &rememberToken.UserID, &rememberToken.TokenHash, &rememberToken.ExpiresAt)
+	err := row.Scan(&rememberToken.ID, &rememberToken.UserID, &rememberToken.ClientID, &rememberToken.TokenHash, &rememberToken.ExpiresAt)
 
 	if err != nil {
 		return nil, err
@@ -70,3 +70,10 @@ func (r *PostgresRememberTokenRepository) Delete(ctx context.Context, tokenID in
 	_, err := r.db.Exec(ctx, sql, tokenID)
 	return err
 }
+
+// DeleteByUserID deletes all remember tokens of the user except the one with exceptTokenHash, which can be empty
+func (r *PostgresRememberTokenRepository) DeleteByUserID(ctx context.Context, userID int64, exceptTokenHash string) error {
+	sql := "DELETE FROM remember_tokens WHERE user_id = $1 AND token_hash <> $2"
+	_, err := r.db.Exec(ctx, sql, userID, exceptTokenHash)
+	return err
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// serviceAccountColumns lists the service_accounts columns in the order expected by scanServiceAccount
+const serviceAccountColumns = "id, client_id, name, organization, owner_user_id, scopes, created_at"
+
+// PostgresServiceAccountRepository represents the Postgres service account repository object
+type PostgresServiceAccountRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresServiceAccountRepository creates a new Postgres service account repository object
+func NewPostgresServiceAccountRepository(db *pgxpool.Pool) *PostgresServiceAccountRepository {
+	return &PostgresServiceAccountRepository{db: db}
+}
+
+// GenerateClientID generates a random client ID
+func (r *PostgresServiceAccountRepository) GenerateClientID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return domain.ServiceAccountClientIDPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// GenerateSecret generates a random client secret
+func (r *PostgresServiceAccountRepository) GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash hashes the given secret
+func (r *PostgresServiceAccountRepository) Hash(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves the service account and its first secret in a single transaction
+func (r *PostgresServiceAccountRepository) Save(ctx context.Context, account *domain.ServiceAccount, secretHash string) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		sql := "INSERT INTO service_accounts (client_id, name, organization, owner_user_id, scopes) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at"
+		err := tx.QueryRow(ctx, sql, account.ClientID, account.Name, account.Organization, account.OwnerUserID, account.Scopes).
+			Scan(&account.ID, &account.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, "INSERT INTO service_account_secrets (service_account_id, secret_hash) VALUES ($1, $2)", account.ID, secretHash)
+		return err
+	})
+}
+
+// FindByID finds the service account by ID
+func (r *PostgresServiceAccountRepository) FindByID(ctx context.Context, id int64) (*domain.ServiceAccount, error) {
+	sql := "SELECT " + serviceAccountColumns + " FROM service_accounts WHERE id = $1"
+	return scanServiceAccount(r.db.QueryRow(ctx, sql, id))
+}
+
+// FindByClientID finds the service account by client ID
+func (r *PostgresServiceAccountRepository) FindByClientID(ctx context.Context, clientID string) (*domain.ServiceAccount, error) {
+	sql := "SELECT " + serviceAccountColumns + " FROM service_accounts WHERE client_id = $1"
+	return scanServiceAccount(r.db.QueryRow(ctx, sql, clientID))
+}
+
+// FindByOwner finds the service accounts owned by the user
+func (r *PostgresServiceAccountRepository) FindByOwner(ctx context.Context, ownerUserID int64) ([]domain.ServiceAccount, error) {
+	sql := "SELECT " + serviceAccountColumns + " FROM service_accounts WHERE owner_user_id = $1 ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make([]domain.ServiceAccount, 0)
+	for rows.Next() {
+		account, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// RotateSecret adds a new secret and schedules the expiry of the secrets that are still valid
+func (r *PostgresServiceAccountRepository) RotateSecret(ctx context.Context, serviceAccountID int64, secretHash string, overlap time.Duration) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		expiresAt := time.Now().Add(overlap)
+		sql := "UPDATE service_account_secrets SET expires_at = $1 WHERE service_account_id = $2 AND (expires_at IS NULL OR expires_at > $1)"
+		if _, err := tx.Exec(ctx, sql, expiresAt, serviceAccountID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, "INSERT INTO service_account_secrets (service_account_id, secret_hash) VALUES ($1, $2)", serviceAccountID, secretHash)
+		return err
+	})
+}
+
+// RevokeCredentialsByOwner deletes the secrets and public keys of the service accounts owned by the user
+func (r *PostgresServiceAccountRepository) RevokeCredentialsByOwner(ctx context.Context, ownerUserID int64) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		sql := "DELETE FROM service_account_secrets WHERE service_account_id IN (SELECT id FROM service_accounts WHERE owner_user_id = $1)"
+		if _, err := tx.Exec(ctx, sql, ownerUserID); err != nil {
+			return err
+		}
+
+		sql = "DELETE FROM service_account_keys WHERE service_account_id IN (SELECT id FROM service_accounts WHERE owner_user_id = $1)"
+		_, err := tx.Exec(ctx, sql, ownerUserID)
+		return err
+	})
+}
+
+// IsSecretValid checks if the secret hash belongs to an unexpired secret of the service account
+func (r *PostgresServiceAccountRepository) IsSecretValid(ctx context.Context, serviceAccountID int64, secretHash string) (bool, error) {
+	sql := `SELECT EXISTS (
+		SELECT 1 FROM service_account_secrets
+		WHERE service_account_id = $1 AND secret_hash = $2 AND (expires_at IS NULL OR expires_at > NOW())
+	)`
+	var valid bool
+	err := r.db.QueryRow(ctx, sql, serviceAccountID, secretHash).Scan(&valid)
+	return valid, err
+}
+
+// SaveKey saves a public key of the service account
+func (r *PostgresServiceAccountRepository) SaveKey(ctx context.Context, key *domain.ServiceAccountKey) error {
+	sql := "INSERT INTO service_account_keys (service_account_id, key_id, public_key_pem) VALUES ($1, $2, $3) RETURNING id, created_at"
+	return r.db.QueryRow(ctx, sql, key.ServiceAccountID, key.KeyID, key.PublicKeyPEM).Scan(&key.ID, &key.CreatedAt)
+}
+
+// FindKeys finds the public keys of the service account
+func (r *PostgresServiceAccountRepository) FindKeys(ctx context.Context, serviceAccountID int64) ([]domain.ServiceAccountKey, error) {
+	sql := "SELECT id, service_account_id, key_id, public_key_pem, created_at FROM service_account_keys WHERE service_account_id = $1"
+	rows, err := r.db.Query(ctx, sql, serviceAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]domain.ServiceAccountKey, 0)
+	for rows.Next() {
+		var key domain.ServiceAccountKey
+		if err := rows.Scan(&key.ID, &key.ServiceAccountID, &key.KeyID, &key.PublicKeyPEM, &key.CreatedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// MarkAssertionUsed records the client assertion ID, expired entries are purged along the way
+func (r *PostgresServiceAccountRepository) MarkAssertionUsed(ctx context.Context, serviceAccountID int64, jti string, expiresAt time.Time) (bool, error) {
+	if _, err := r.db.Exec(ctx, "DELETE FROM used_client_assertions WHERE expires_at < NOW()"); err != nil {
+		return false, err
+	}
+
+	sql := "INSERT INTO used_client_assertions (service_account_id, jti, expires_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+	tag, err := r.db.Exec(ctx, sql, serviceAccountID, jti, expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// scanServiceAccount scans a single service_accounts row selected with serviceAccountColumns
+func scanServiceAccount(row pgx.Row) (*domain.ServiceAccount, error) {
+	var account domain.ServiceAccount
+	err := row.Scan(&account.ID, &account.ClientID, &account.Name, &account.Organization, &account.OwnerUserID, &account.Scopes, &account.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresSessionRepository represents the Postgres session repository object
+type PostgresSessionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresSessionRepository creates a new Postgres session repository object
+func NewPostgresSessionRepository(db *pgxpool.Pool) *PostgresSessionRepository {
+	return &PostgresSessionRepository{db: db}
+}
+
+// Generate generates a random session token
+func (r *PostgresSessionRepository) Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash hashes the given token
+func (r *PostgresSessionRepository) Hash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves the session to the database
+func (r *PostgresSessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	roles := session.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	amr := session.AMR
+	if amr == nil {
+		amr = []string{}
+	}
+
+	sql := "INSERT INTO sessions (user_id, token_hash, roles, persistent, amr, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, auth_time, created_at, last_seen_at"
+	return r.db.QueryRow(ctx, sql, session.UserID, session.TokenHash, roles, session.Persistent, amr, session.ExpiresAt).
+		Scan(&session.ID, &session.AuthTime, &session.CreatedAt, &session.LastSeenAt)
+}
+
+// FindByToken finds the unexpired session by current or previous token hash
+func (r *PostgresSessionRepository) FindByToken(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	sql := `SELECT id, user_id, token_hash, roles, persistent, auth_time, amr, created_at, last_seen_at, expires_at, rotated_at FROM sessions
+		WHERE (token_hash = $1 OR previous_token_hash = $1) AND expires_at > NOW()`
+
+	var session domain.Session
+	err := r.db.QueryRow(ctx, sql, tokenHash).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenHash,
+		&session.Roles,
+		&session.Persistent,
+		&session.AuthTime,
+		&session.AMR,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RotatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Touch updates the last seen time of the session
+func (r *PostgresSessionRepository) Touch(ctx context.Context, sessionID int64) error {
+	_, err := r.db.Exec(ctx, "UPDATE sessions SET last_seen_at = NOW() WHERE id = $1", sessionID)
+	return err
+}
+
+// Rotate replaces the token hash and roles of the session, the replaced hash becomes the previous one
+func (r *PostgresSessionRepository) Rotate(ctx context.Context, sessionID int64, tokenHash string, roles []string) error {
+	if roles == nil {
+		roles = []string{}
+	}
+
+	query := `UPDATE sessions SET previous_token_hash = token_hash, rotated_at = NOW(), token_hash = $1, roles = $2, last_seen_at = NOW()
+		WHERE id = $3`
+	tag, err := r.db.Exec(ctx, query, tokenHash, roles, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Reauthenticate replaces the token hash of the session, the replaced hash becomes the previous one.
+// It records that the user just authenticated with amr and returns the updated session.
+func (r *PostgresSessionRepository) Reauthenticate(ctx context.Context, sessionID int64, tokenHash string, amr []string) (*domain.Session, error) {
+	query := `UPDATE sessions SET previous_token_hash = token_hash, rotated_at = NOW(), token_hash = $1, auth_time = NOW(), amr = $2, last_seen_at = NOW()
+		WHERE id = $3
+		RETURNING id, user_id, token_hash, roles, persistent, auth_time, amr, created_at, last_seen_at, expires_at, rotated_at`
+
+	var session domain.Session
+	err := r.db.QueryRow(ctx, query, tokenHash, amr, sessionID).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenHash,
+		&session.Roles,
+		&session.Persistent,
+		&session.AuthTime,
+		&session.AMR,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RotatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Delete deletes the session
+func (r *PostgresSessionRepository) Delete(ctx context.Context, sessionID int64) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM sessions WHERE id = $1", sessionID)
+	return err
+}
+
+// DeleteByUserID deletes all sessions of the user except exceptSessionID, which can be zero
+func (r *PostgresSessionRepository) DeleteByUserID(ctx context.Context, userID int64, exceptSessionID int64) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM sessions WHERE user_id = $1 AND id <> $2", userID, exceptSessionID)
+	return err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// trustedDeviceColumns lists the trusted_devices columns in the order expected by scanTrustedDevice
+const trustedDeviceColumns = "id, user_id, device_id_hash, user_agent_family, ip_address, created_at, last_used_at, expires_at"
+
+// PostgresTrustedDeviceRepository represents the Postgres trusted device repository object
+type PostgresTrustedDeviceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresTrustedDeviceRepository creates a new Postgres trusted device repository object
+func NewPostgresTrustedDeviceRepository(db *pgxpool.Pool) *PostgresTrustedDeviceRepository {
+	return &PostgresTrustedDeviceRepository{db: db}
+}
+
+// Hash hashes the device ID
+func (r *PostgresTrustedDeviceRepository) Hash(deviceID string) string {
+	hash := sha256.Sum256([]byte(deviceID))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Save saves the trusted device, discarding the expired devices of the user
+func (r *PostgresTrustedDeviceRepository) Save(ctx context.Context, device *domain.TrustedDevice) error {
+	sql := "DELETE FROM trusted_devices WHERE user_id = $1 AND expires_at <= NOW()"
+	if _, err := r.db.Exec(ctx, sql, device.UserID); err != nil {
+		return err
+	}
+
+	sql = `INSERT INTO trusted_devices (user_id, device_id_hash, user_agent_family, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, last_used_at`
+	return r.db.QueryRow(ctx, sql,
+		device.UserID,
+		device.DeviceIDHash,
+		device.UserAgentFamily,
+		device.IPAddress,
+		device.ExpiresAt,
+	).Scan(&device.ID, &device.CreatedAt, &device.LastUsedAt)
+}
+
+// FindByID finds the trusted device by ID
+func (r *PostgresTrustedDeviceRepository) FindByID(ctx context.Context, id int64) (*domain.TrustedDevice, error) {
+	sql := "SELECT " + trustedDeviceColumns + " FROM trusted_devices WHERE id = $1"
+	return scanTrustedDevice(r.db.QueryRow(ctx, sql, id))
+}
+
+// FindByUserID lists the trusted devices of the user that haven't expired
+func (r *PostgresTrustedDeviceRepository) FindByUserID(ctx context.Context, userID int64) ([]domain.TrustedDevice, error) {
+	sql := "SELECT " + trustedDeviceColumns + " FROM trusted_devices WHERE user_id = $1 AND expires_at > NOW() ORDER BY last_used_at DESC"
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]domain.TrustedDevice, 0)
+	for rows.Next() {
+		device, err := scanTrustedDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, *device)
+	}
+
+	return devices, rows.Err()
+}
+
+// Touch records that the device skipped a second factor
+func (r *PostgresTrustedDeviceRepository) Touch(ctx context.Context, id int64, ipAddress string) error {
+	sql := "UPDATE trusted_devices SET last_used_at = NOW(), ip_address = $1 WHERE id = $2"
+	_, err := r.db.Exec(ctx, sql, ipAddress, id)
+	return err
+}
+
+// Delete deletes a trusted device of the user
+func (r *PostgresTrustedDeviceRepository) Delete(ctx context.Context, userID int64, id int64) error {
+	query := "DELETE FROM trusted_devices WHERE id = $1 AND user_id = $2"
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteByUserID deletes every trusted device of the user
+func (r *PostgresTrustedDeviceRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM trusted_devices WHERE user_id = $1", userID)
+	return err
+}
+
+// scanTrustedDevice scans a single trusted_devices row selected with trustedDeviceColumns
+func scanTrustedDevice(row pgx.Row) (*domain.TrustedDevice, error) {
+	var device domain.TrustedDevice
+	err := row.Scan(
+		&device.ID,
+		&device.UserID,
+		&device.DeviceIDHash,
+		&device.UserAgentFamily,
+		&device.IPAddress,
+		&device.CreatedAt,
+		&device.LastUsedAt,
+		&device.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
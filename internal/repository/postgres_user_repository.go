@@ -4,10 +4,21 @@ import (
 	"auth/internal/domain"
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// userColumns lists the users columns in the order expected by scanUser
+const userColumns = "id, name, email, password, verified, auth_source, roles, password_reset_required, password_changed_at, " +
+	"locale, timezone, avatar_url, public_metadata, private_metadata, created_at, updated_at, last_login_at, deleted_at, purge_at, " +
+	"status, status_reason, status_note, suspended_until, status_changed_at, mfa_enabled, phone_number, mfa_channel"
+
+// maxPasswordHistory is the number of previous passwords kept per user.
+// With the current password it covers usecase.MaxPasswordHistory.
+const maxPasswordHistory = 24
+
 // PostgresUserRepository represents the Postgres user repository object
 type PostgresUserRepository struct {
 	db *pgxpool.Pool
@@ -20,8 +31,16 @@ func NewPostgresUserRepository(db *pgxpool.Pool) *PostgresUserRepository {
 
 // Save saves the user to the database
 func (r *PostgresUserRepository) Save(ctx context.Context, user *domain.User) error {
-	sql := "INSERT INTO users (name, email, password) VALUES ($1, $2, $3) RETURNING id"
-	err := r.db.QueryRow(ctx, sql, user.Name, user.Email, user.Password).Scan(&user.ID)
+	if user.AuthSource == "" {
+		user.AuthSource = domain.AuthSourceLocal
+	}
+
+	if user.Roles == nil {
+		user.Roles = []string{}
+	}
+
+	sql := "INSERT INTO users (name, email, password, verified, auth_source, roles) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+	err := r.db.QueryRow(ctx, sql, user.Name, user.Email, user.Password, user.Verified, user.AuthSource, user.Roles).Scan(&user.ID)
 
 	return err
 }
@@ -29,30 +48,18 @@ func (r *PostgresUserRepository) Save(ctx context.Context, user *domain.User) er
 // FindByEmail finds the user by email
 func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	fmt.Println("FindByEmail error :")
-	sql := "SELECT * FROM users WHERE email = $1"
+	sql := "SELECT " + userColumns + " FROM users WHERE email = $1"
 	row := r.db.QueryRow(ctx, sql, email)
-	var user domain.User
-
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Verified)
-
-	if err != nil {
-		return nil, err
-	}
 
-	return &user, nil
+	return scanUser(row)
 }
 
 // FindByID finds the user by ID
 func (r *PostgresUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
-	sql := "SELECT * FROM users WHERE id = $1"
+	sql := "SELECT " + userColumns + " FROM users WHERE id = $1"
 	row := r.db.QueryRow(ctx, sql, id)
-	var user domain.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Verified)
-	if err != nil {
-		return nil, err
-	}
 
-	return &user, nil
+	return scanUser(row)
 }
 
 // IsVerifiedUserExists checks if the user exists and is verified
@@ -78,9 +85,265 @@ func (r *PostgresUserRepository) SetVerified(ctx context.Context, userID int64)
 	return err
 }
 
-// UpdatePassword updates the user's password'
+// UpdatePassword updates the user's password, moves the previous one to the password history
+// and lifts a pending forced reset
 func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, userID int64, newPassword string) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// Statements in a WITH query share a snapshot, so the history receives the password from before the update
+		sql := `WITH previous AS (
+			INSERT INTO password_histories (user_id, password)
+			SELECT id, password FROM users WHERE id = $2 AND password <> ''
+		)
+		UPDATE users SET password = $1, password_changed_at = NOW(), password_reset_required = FALSE WHERE id = $2`
+		if _, err := tx.Exec(ctx, sql, newPassword, userID); err != nil {
+			return err
+		}
+
+		sql = `DELETE FROM password_histories WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_histories WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2
+		)`
+		_, err := tx.Exec(ctx, sql, userID, maxPasswordHistory)
+		return err
+	})
+}
+
+// RehashPassword replaces the hash of the unchanged password, it isn't recorded as a password change
+func (r *PostgresUserRepository) RehashPassword(ctx context.Context, userID int64, newHash string) error {
 	sql := "UPDATE users SET password = $1 WHERE id = $2"
-	_, err := r.db.Exec(ctx, sql, newPassword, userID)
+	_, err := r.db.Exec(ctx, sql, newHash, userID)
+	return err
+}
+
+// FindPasswordHistory returns the hashes of the user's previous passwords, most recent first
+func (r *PostgresUserRepository) FindPasswordHistory(ctx context.Context, userID int64, limit int) ([]string, error) {
+	sql := "SELECT password FROM password_histories WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2"
+	rows, err := r.db.Query(ctx, sql, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	passwords := make([]string, 0)
+	for rows.Next() {
+		var password string
+		if err := rows.Scan(&password); err != nil {
+			return nil, err
+		}
+		passwords = append(passwords, password)
+	}
+
+	return passwords, rows.Err()
+}
+
+// RequirePasswordReset blocks password logins until the user resets their password
+func (r *PostgresUserRepository) RequirePasswordReset(ctx context.Context, userID int64) error {
+	sql := "UPDATE users SET password_reset_required = TRUE WHERE id = $1"
+	_, err := r.db.Exec(ctx, sql, userID)
+	return err
+}
+
+// UpdateRoles replaces the roles granted to the user
+func (r *PostgresUserRepository) UpdateRoles(ctx context.Context, userID int64, roles []string) error {
+	if roles == nil {
+		roles = []string{}
+	}
+
+	sql := "UPDATE users SET roles = $1 WHERE id = $2"
+	_, err := r.db.Exec(ctx, sql, roles, userID)
+	return err
+}
+
+// UpdateProfile saves the profile attributes of the user
+func (r *PostgresUserRepository) UpdateProfile(ctx context.Context, user *domain.User) error {
+	if user.PublicMetadata == nil {
+		user.PublicMetadata = map[string]any{}
+	}
+
+	if user.PrivateMetadata == nil {
+		user.PrivateMetadata = map[string]any{}
+	}
+
+	sql := `UPDATE users SET name = $1, locale = $2, timezone = $3, avatar_url = $4, public_metadata = $5, private_metadata = $6, updated_at = NOW()
+		WHERE id = $7 RETURNING updated_at`
+	return r.db.QueryRow(ctx, sql,
+		user.Name,
+		user.Locale,
+		user.Timezone,
+		user.AvatarURL,
+		user.PublicMetadata,
+		user.PrivateMetadata,
+		user.ID,
+	).Scan(&user.UpdatedAt)
+}
+
+// UpdateMFASettings turns the second factor of password logins on or off and sets its channel
+func (r *PostgresUserRepository) UpdateMFASettings(ctx context.Context, userID int64, enabled bool, channel string) error {
+	sql := "UPDATE users SET mfa_enabled = $1, mfa_channel = $2, updated_at = NOW() WHERE id = $3"
+	_, err := r.db.Exec(ctx, sql, enabled, channel, userID)
+	return err
+}
+
+// UpdatePhoneNumber sets the verified phone number of the user.
+// An empty number removes it and moves the MFA channel back to email.
+func (r *PostgresUserRepository) UpdatePhoneNumber(ctx context.Context, userID int64, phoneNumber string) error {
+	sql := `UPDATE users SET phone_number = $1::text,
+		mfa_channel = CASE WHEN $1::text = '' THEN 'email' ELSE mfa_channel END,
+		updated_at = NOW()
+		WHERE id = $2`
+	_, err := r.db.Exec(ctx, sql, phoneNumber, userID)
+	return err
+}
+
+// UpdateLastLogin records that the user has just logged in
+func (r *PostgresUserRepository) UpdateLastLogin(ctx context.Context, userID int64) error {
+	sql := "UPDATE users SET last_login_at = NOW() WHERE id = $1"
+	_, err := r.db.Exec(ctx, sql, userID)
+	return err
+}
+
+// SoftDelete marks the user as deleted until purgeAt and revokes their sessions, remember tokens and
+// personal access tokens, so only a password login can restore the account. Data exports are expired.
+// It returns the deletion time.
+func (r *PostgresUserRepository) SoftDelete(ctx context.Context, userID int64, purgeAt time.Time) (time.Time, error) {
+	var deletedAt time.Time
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		sql := "UPDATE users SET deleted_at = NOW(), purge_at = $1 WHERE id = $2 RETURNING deleted_at"
+		if err := tx.QueryRow(ctx, sql, purgeAt, userID).Scan(&deletedAt); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM sessions WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM remember_tokens WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM personal_access_tokens WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+
+		// Expired exports are purged with their archives, so the download links stop working right away
+		_, err := tx.Exec(ctx, "UPDATE data_exports SET expires_at = NOW() WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > NOW())", userID)
+		return err
+	})
+
+	return deletedAt, err
+}
+
+// Restore cancels the pending deletion of the user
+func (r *PostgresUserRepository) Restore(ctx context.Context, userID int64) error {
+	sql := "UPDATE users SET deleted_at = NULL, purge_at = NULL WHERE id = $1"
+	_, err := r.db.Exec(ctx, sql, userID)
 	return err
 }
+
+// PurgeDeleted permanently deletes the users whose grace period has ended and returns their email addresses.
+// Tables referencing users cascade, codes and one-time passwords are keyed by email and deleted here.
+func (r *PostgresUserRepository) PurgeDeleted(ctx context.Context) ([]string, error) {
+	var emails []string
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, "DELETE FROM users WHERE purge_at <= NOW() RETURNING email")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var email string
+			if err := rows.Scan(&email); err != nil {
+				return err
+			}
+			emails = append(emails, email)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(emails) == 0 {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM email_verification_codes WHERE email = ANY($1)", emails); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, "DELETE FROM login_otps WHERE email = ANY($1)", emails)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// UpdateStatus stores the status set by an administrator. Blocking the account revokes its sessions and
+// remember tokens, personal access tokens are kept for when the account is unblocked.
+func (r *PostgresUserRepository) UpdateStatus(ctx context.Context, user *domain.User) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := `UPDATE users SET status = $1, status_reason = $2, status_note = $3, suspended_until = $4, status_changed_at = NOW()
+			WHERE id = $5 RETURNING status_changed_at`
+		err := tx.QueryRow(ctx, query,
+			user.Status,
+			user.StatusReason,
+			user.StatusNote,
+			user.SuspendedUntil,
+			user.ID,
+		).Scan(&user.StatusChangedAt)
+		if err != nil {
+			return err
+		}
+
+		if !user.IsBlocked() {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM sessions WHERE user_id = $1", user.ID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, "DELETE FROM remember_tokens WHERE user_id = $1", user.ID)
+		return err
+	})
+}
+
+// scanUser scans a single users row selected with userColumns
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.Password,
+		&user.Verified,
+		&user.AuthSource,
+		&user.Roles,
+		&user.PasswordResetRequired,
+		&user.PasswordChangedAt,
+		&user.Locale,
+		&user.Timezone,
+		&user.AvatarURL,
+		&user.PublicMetadata,
+		&user.PrivateMetadata,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.LastLoginAt,
+		&user.DeletedAt,
+		&user.PurgeAt,
+		&user.Status,
+		&user.StatusReason,
+		&user.StatusNote,
+		&user.SuspendedUntil,
+		&user.StatusChangedAt,
+		&user.MFAEnabled,
+		&user.PhoneNumber,
+		&user.MFAChannel,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
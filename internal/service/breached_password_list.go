@@ -0,0 +1,246 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" // #nosec G505 -- the breach corpora are published as SHA-1 hashes
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// compactBreachedPasswordMagic starts a compact breached password file, followed by sorted raw SHA-1 digests
+var compactBreachedPasswordMagic = []byte("BPW1")
+
+// breachedPasswordMetrics counts lookups, they are published with the other expvar variables
+var breachedPasswordMetrics = expvar.NewMap("breached_passwords")
+
+// BreachedPasswordList looks passwords up in a local copy of a Have I Been Pwned style SHA-1 hash list.
+// The file is searched in place, so even the full corpus only costs a few reads per lookup.
+//
+// Two formats are supported: the sorted text file ("<SHA-1 hex>:<count>" per line, ordered by hash)
+// as produced by the HIBP downloader, and the compact binary file written by CompactBreachedPasswordFile.
+type BreachedPasswordList struct {
+	file    *os.File
+	size    int64
+	compact bool
+}
+
+// OpenBreachedPasswordList opens a breached password file, detecting its format
+func OpenBreachedPasswordList(path string) (*BreachedPasswordList, error) {
+	file, err := os.Open(path) // #nosec G304 -- the path comes from the server configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to stat breached password file: %w", err)
+	}
+
+	list := &BreachedPasswordList{file: file, size: info.Size()}
+
+	header := make([]byte, len(compactBreachedPasswordMagic))
+	if _, err := file.ReadAt(header, 0); err == nil && bytes.Equal(header, compactBreachedPasswordMagic) {
+		list.compact = true
+		if (list.size-int64(len(header)))%sha1.Size != 0 {
+			_ = file.Close()
+			return nil, errors.New("breached password file is truncated")
+		}
+	}
+
+	return list, nil
+}
+
+// Close closes the underlying file
+func (l *BreachedPasswordList) Close() error {
+	return l.file.Close()
+}
+
+// IsBreached reports whether the SHA-1 hash of the password is in the list
+func (l *BreachedPasswordList) IsBreached(password string) (bool, error) {
+	breachedPasswordMetrics.Add("checks", 1)
+
+	digest := sha1.Sum([]byte(password)) // #nosec G401 -- required by the list format, not used for storage
+
+	var found bool
+	var err error
+	if l.compact {
+		found, err = l.searchCompact(digest[:])
+	} else {
+		found, err = l.searchText(strings.ToUpper(hex.EncodeToString(digest[:])))
+	}
+
+	if err != nil {
+		breachedPasswordMetrics.Add("errors", 1)
+		return false, err
+	}
+	if found {
+		breachedPasswordMetrics.Add("hits", 1)
+	}
+
+	return found, nil
+}
+
+// searchCompact binary searches the fixed-size records of a compact file
+func (l *BreachedPasswordList) searchCompact(digest []byte) (bool, error) {
+	offset := int64(len(compactBreachedPasswordMagic))
+	count := int((l.size - offset) / sha1.Size)
+	record := make([]byte, sha1.Size)
+
+	var readErr error
+	i := sort.Search(count, func(i int) bool {
+		if readErr != nil {
+			return true
+		}
+		if _, err := l.file.ReadAt(record, offset+int64(i)*sha1.Size); err != nil {
+			readErr = fmt.Errorf("failed to read breached password file: %w", err)
+			return true
+		}
+
+		return bytes.Compare(record, digest) >= 0
+	})
+	if readErr != nil {
+		return false, readErr
+	}
+	if i == count {
+		return false, nil
+	}
+
+	if _, err := l.file.ReadAt(record, offset+int64(i)*sha1.Size); err != nil {
+		return false, fmt.Errorf("failed to read breached password file: %w", err)
+	}
+
+	return bytes.Equal(record, digest), nil
+}
+
+// searchText binary searches the byte offsets of a sorted text file.
+// The invariant is that the line holding the hash, if any, starts within [low, high).
+func (l *BreachedPasswordList) searchText(hash string) (bool, error) {
+	low, high := int64(0), l.size
+	for low < high {
+		mid := low + (high-low)/2
+
+		start, line, err := l.lineFrom(mid)
+		if err != nil {
+			return false, err
+		}
+		if start >= high {
+			high = mid
+			continue
+		}
+
+		lineHash, _, _ := strings.Cut(line, ":")
+		switch strings.Compare(strings.ToUpper(strings.TrimSpace(lineHash)), hash) {
+		case 0:
+			return true, nil
+		case -1:
+			low = start + int64(len(line)) + 1
+		default:
+			high = mid
+		}
+	}
+
+	return false, nil
+}
+
+// lineFrom returns the first complete line starting at or after offset, without its line break.
+// The start is the file size when there is no such line.
+func (l *BreachedPasswordList) lineFrom(offset int64) (int64, string, error) {
+	start := offset
+	if offset > 0 {
+		// Skip the rest of the line the offset falls into, unless it is the start of a line
+		start = offset - 1
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(l.file, start, l.size-start))
+	if offset > 0 {
+		skipped, err := reader.ReadSlice('\n')
+		if errors.Is(err, io.EOF) {
+			return l.size, "", nil
+		}
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to read breached password file: %w", err)
+		}
+		start += int64(len(skipped))
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", fmt.Errorf("failed to read breached password file: %w", err)
+	}
+	if line == "" {
+		return l.size, "", nil
+	}
+
+	return start, strings.TrimSuffix(line, "\n"), nil
+}
+
+// CompactBreachedPasswordFile converts a sorted text file into the compact binary format,
+// which is less than half the size and skips the line scanning on lookups.
+func CompactBreachedPasswordFile(src, dst string) error {
+	in, err := os.Open(src) // #nosec G304 -- the path comes from the server configuration
+	if err != nil {
+		return fmt.Errorf("failed to open breached password file: %w", err)
+	}
+	defer func() { _ = in.Close() }()
+
+	// Write to a temporary file first so a crash never leaves a truncated list behind
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp) // #nosec G304 -- the path comes from the server configuration
+	if err != nil {
+		return fmt.Errorf("failed to create compact breached password file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	writer := bufio.NewWriter(out)
+	if _, err := writer.Write(compactBreachedPasswordMagic); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	var previous []byte
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		lineHash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if lineHash == "" {
+			continue
+		}
+
+		digest, err := hex.DecodeString(lineHash)
+		if err != nil || len(digest) != sha1.Size {
+			_ = out.Close()
+			return fmt.Errorf("invalid hash %q in breached password file", lineHash)
+		}
+		if previous != nil && bytes.Compare(previous, digest) >= 0 {
+			_ = out.Close()
+			return errors.New("breached password file is not sorted by hash")
+		}
+		previous = digest
+
+		if _, err := writer.Write(digest); err != nil {
+			_ = out.Close()
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to read breached password file: %w", err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, dst)
+}
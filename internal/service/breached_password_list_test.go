@@ -0,0 +1,161 @@
+package service
+
+import (
+	"crypto/sha1" // #nosec G505 -- the breach corpora are published as SHA-1 hashes
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// breachedPasswords returns n passwords and the sorted lines of their HIBP style hash list.
+// Counts of varying length make the lines uneven, so the search lands in the middle of lines.
+func breachedPasswords(n int) ([]string, []string) {
+	passwords := make([]string, n)
+	lines := make([]string, n)
+	for i := range n {
+		passwords[i] = fmt.Sprintf("breached-%d", i)
+		digest := sha1.Sum([]byte(passwords[i])) // #nosec G401 -- required by the list format
+		lines[i] = fmt.Sprintf("%s:%d", strings.ToUpper(hex.EncodeToString(digest[:])), (i*7919)%100000+1)
+	}
+	slices.Sort(lines)
+
+	return passwords, lines
+}
+
+// writeBreachedPasswordFile writes the lines to a text file in a temporary directory
+func writeBreachedPasswordFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+// openBreachedPasswordList opens the list and closes it when the test ends
+func openBreachedPasswordList(t *testing.T, path string) *BreachedPasswordList {
+	t.Helper()
+
+	list, err := OpenBreachedPasswordList(path)
+	if err != nil {
+		t.Fatalf("OpenBreachedPasswordList() error = %v", err)
+	}
+	t.Cleanup(func() { _ = list.Close() })
+
+	return list
+}
+
+// assertBreached checks every breached password is found and none of the others is
+func assertBreached(t *testing.T, list *BreachedPasswordList, breached []string) {
+	t.Helper()
+
+	for _, password := range breached {
+		if found, err := list.IsBreached(password); err != nil || !found {
+			t.Errorf("IsBreached(%q) = %v, %v, want true", password, found, err)
+		}
+	}
+
+	for i := range 200 {
+		password := fmt.Sprintf("safe-%d", i)
+		if found, err := list.IsBreached(password); err != nil || found {
+			t.Errorf("IsBreached(%q) = %v, %v, want false", password, found, err)
+		}
+	}
+}
+
+func TestBreachedPasswordListText(t *testing.T) {
+	tests := []struct {
+		name   string
+		count  int
+		format func(lines []string) string
+	}{
+		{name: "empty file", count: 0, format: func([]string) string { return "" }},
+		{name: "single line", count: 1, format: func(lines []string) string { return strings.Join(lines, "\n") + "\n" }},
+		{name: "two lines", count: 2, format: func(lines []string) string { return strings.Join(lines, "\n") + "\n" }},
+		{name: "trailing newline", count: 257, format: func(lines []string) string { return strings.Join(lines, "\n") + "\n" }},
+		{name: "no trailing newline", count: 257, format: func(lines []string) string { return strings.Join(lines, "\n") }},
+		{name: "crlf line breaks", count: 257, format: func(lines []string) string { return strings.Join(lines, "\r\n") + "\r\n" }},
+		{
+			name:  "lowercase hashes",
+			count: 64,
+			format: func(lines []string) string {
+				return strings.ToLower(strings.Join(lines, "\n")) + "\n"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passwords, lines := breachedPasswords(tt.count)
+			list := openBreachedPasswordList(t, writeBreachedPasswordFile(t, tt.format(lines)))
+			if list.compact {
+				t.Fatal("OpenBreachedPasswordList() detected a text file as compact")
+			}
+
+			assertBreached(t, list, passwords)
+		})
+	}
+}
+
+func TestBreachedPasswordListCompact(t *testing.T) {
+	for _, count := range []int{0, 1, 2, 257} {
+		t.Run(fmt.Sprintf("%d hashes", count), func(t *testing.T) {
+			passwords, lines := breachedPasswords(count)
+			src := writeBreachedPasswordFile(t, strings.Join(lines, "\n")+"\n")
+			dst := filepath.Join(t.TempDir(), "pwned.bin")
+
+			if err := CompactBreachedPasswordFile(src, dst); err != nil {
+				t.Fatalf("CompactBreachedPasswordFile() error = %v", err)
+			}
+
+			list := openBreachedPasswordList(t, dst)
+			if !list.compact {
+				t.Fatal("OpenBreachedPasswordList() didn't detect the compact file")
+			}
+
+			assertBreached(t, list, passwords)
+		})
+	}
+}
+
+func TestCompactBreachedPasswordFileRejectsInvalidInput(t *testing.T) {
+	_, lines := breachedPasswords(3)
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unsorted", content: lines[1] + "\n" + lines[0] + "\n"},
+		{name: "duplicate", content: lines[0] + "\n" + lines[0] + "\n"},
+		{name: "invalid hash", content: "not-a-hash:1\n"},
+		{name: "short hash", content: lines[0][:20] + ":1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "pwned.bin")
+			if err := CompactBreachedPasswordFile(writeBreachedPasswordFile(t, tt.content), dst); err == nil {
+				t.Error("CompactBreachedPasswordFile() error = nil, want an error")
+			}
+
+			if _, err := os.Stat(dst); !os.IsNotExist(err) {
+				t.Errorf("CompactBreachedPasswordFile() left %s behind", dst)
+			}
+		})
+	}
+}
+
+func TestOpenBreachedPasswordListRejectsTruncatedCompactFile(t *testing.T) {
+	path := writeBreachedPasswordFile(t, string(compactBreachedPasswordMagic)+strings.Repeat("x", sha1.Size+1))
+
+	if list, err := OpenBreachedPasswordList(path); err == nil {
+		_ = list.Close()
+		t.Error("OpenBreachedPasswordList() error = nil, want an error")
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// FileDataExportStorage stores data export archives in a local directory.
+// The directory must be shared by the API and the task processor when they run on different hosts.
+type FileDataExportStorage struct {
+	dir string
+}
+
+// NewFileDataExportStorage creates a new FileDataExportStorage object, creating the directory if needed
+func NewFileDataExportStorage(dir string) (*FileDataExportStorage, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileDataExportStorage{dir: dir}, nil
+}
+
+// Save writes the archive under the given key, replacing an existing one
+func (s *FileDataExportStorage) Save(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a download never sees a partial archive
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Load reads the archive stored under the given key
+func (s *FileDataExportStorage) Load(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+// Delete removes the archive stored under the given key, a missing archive is not an error
+func (s *FileDataExportStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path returns the file of the key, keys can't point outside the directory
+func (s *FileDataExportStorage) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key == "." || key == ".." {
+		return "", errors.New("invalid data export storage key")
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
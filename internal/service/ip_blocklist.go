@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// IPBlocklist holds IP address ranges known to be used for attacks, e.g. Tor exit nodes or abusive hosting providers.
+//
+// The file lists one CIDR range or single IP address per line, blank lines and text after "#" are ignored.
+type IPBlocklist struct {
+	prefixes []netip.Prefix
+}
+
+// LoadIPBlocklist reads an IP blocklist file into memory
+func LoadIPBlocklist(path string) (*IPBlocklist, error) {
+	file, err := os.Open(path) // #nosec G304 -- the path comes from the server configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ip blocklist file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	list := &IPBlocklist{}
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		prefix, err := parseIPRange(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip range on line %d of ip blocklist file: %w", lineNumber, err)
+		}
+		list.prefixes = append(list.prefixes, prefix)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ip blocklist file: %w", err)
+	}
+
+	return list, nil
+}
+
+// Len returns the number of ranges in the list
+func (l *IPBlocklist) Len() int {
+	return len(l.prefixes)
+}
+
+// Contains reports whether the IP address is in one of the ranges
+func (l *IPBlocklist) Contains(ipAddress string) bool {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return false
+	}
+	// IPv4 clients of dual-stack listeners show up as IPv4-mapped IPv6 addresses
+	addr = addr.Unmap()
+
+	for _, prefix := range l.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIPRange parses a CIDR range or a single IP address
+func parseIPRange(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
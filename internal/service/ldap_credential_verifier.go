@@ -0,0 +1,310 @@
+package service
+
+import (
+	"auth/internal/domain"
+	"auth/internal/usecase"
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// LDAPConfig holds all the necessary configuration for the LDAP credential verifier.
+type LDAPConfig struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+
+	// BindDN and BindPassword are the service account used to search for users
+	BindDN       string
+	BindPassword string
+
+	// BaseDN and UserFilter locate the user entry, UserFilter receives the escaped email as its only argument
+	BaseDN     string
+	UserFilter string
+
+	EmailAttribute string
+	NameAttribute  string
+
+	// GroupAttribute is read from the user entry (e.g. memberOf) to resolve group membership.
+	// When GroupBaseDN is set, groups are searched instead with GroupFilter receiving the escaped user DN.
+	GroupAttribute string
+	GroupBaseDN    string
+	GroupFilter    string
+
+	// GroupRoles maps a group DN or CN (case-insensitive) to the local roles it grants
+	GroupRoles   map[string][]string
+	DefaultRoles []string
+}
+
+// LDAPConn is the subset of an LDAP connection used by the verifier.
+// It is satisfied by *ldap.Conn and can be replaced with an in-process stub.
+type LDAPConn interface {
+	Bind(username, password string) error
+	Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+// LDAPDialer opens a new connection to the directory
+type LDAPDialer func(ctx context.Context) (LDAPConn, error)
+
+// NewLDAPDialer returns a dialer connecting to the directory described by config
+func NewLDAPDialer(config LDAPConfig) LDAPDialer {
+	return func(_ context.Context) (LDAPConn, error) {
+		tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify} // #nosec G402 -- opt-in for lab directories
+
+		conn, err := ldap.DialURL(config.URL, ldap.DialWithTLSConfig(tlsConfig))
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial ldap server: %w", err)
+		}
+
+		if config.StartTLS {
+			if err := conn.StartTLS(tlsConfig); err != nil {
+				_ = conn.Close()
+				return nil, fmt.Errorf("failed to start tls: %w", err)
+			}
+		}
+
+		return conn, nil
+	}
+}
+
+// LDAPCredentialVerifier verifies credentials with a search-then-bind against an LDAP / Active Directory server
+// and keeps a local shadow user in sync with the directory entry.
+type LDAPCredentialVerifier struct {
+	config         LDAPConfig
+	dial           LDAPDialer
+	userRepository usecase.UserRepository
+	logger         *slog.Logger
+}
+
+// NewLDAPCredentialVerifier creates a new LDAP credential verifier
+func NewLDAPCredentialVerifier(
+	config LDAPConfig,
+	dial LDAPDialer,
+	userRepository usecase.UserRepository,
+	logger *slog.Logger,
+) *LDAPCredentialVerifier {
+	if config.UserFilter == "" {
+		config.UserFilter = "(&(objectClass=person)(mail=%s))"
+	}
+	if config.EmailAttribute == "" {
+		config.EmailAttribute = "mail"
+	}
+	if config.NameAttribute == "" {
+		config.NameAttribute = "cn"
+	}
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = "memberOf"
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = "(member=%s)"
+	}
+
+	return &LDAPCredentialVerifier{
+		config:         config,
+		dial:           dial,
+		userRepository: userRepository,
+		logger:         logger,
+	}
+}
+
+// Verify looks the user up with the service account, binds as the user to check the password,
+// then maps their groups to roles and creates or updates the local shadow user.
+func (v *LDAPCredentialVerifier) Verify(ctx context.Context, email string, password string) (*domain.User, error) {
+	// An empty password would turn the user bind into an unauthenticated bind, which always succeeds
+	if strings.TrimSpace(email) == "" || password == "" {
+		return nil, usecase.ErrInvalidCredentials
+	}
+
+	conn, err := v.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			v.logger.Warn("Failed to close ldap connection", "error", err)
+		}
+	}()
+
+	entry, err := v.findEntry(conn, email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bind as the user to verify the password
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, usecase.ErrInvalidCredentials
+		}
+
+		return nil, fmt.Errorf("failed to bind as user: %w", err)
+	}
+
+	groups, err := v.findGroups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	name := entry.GetAttributeValue(v.config.NameAttribute)
+	directoryEmail := entry.GetAttributeValue(v.config.EmailAttribute)
+	if directoryEmail == "" {
+		directoryEmail = email
+	}
+
+	return v.syncShadowUser(ctx, name, directoryEmail, v.mapRoles(groups))
+}
+
+// findEntry searches for exactly one directory entry matching the email
+func (v *LDAPCredentialVerifier) findEntry(conn LDAPConn, email string) (*ldap.Entry, error) {
+	if v.config.BindDN != "" {
+		if err := conn.Bind(v.config.BindDN, v.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind ldap service account: %w", err)
+		}
+	}
+
+	attributes := []string{v.config.EmailAttribute, v.config.NameAttribute}
+	if v.config.GroupBaseDN == "" {
+		attributes = append(attributes, v.config.GroupAttribute)
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		v.config.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		0,
+		false,
+		fmt.Sprintf(v.config.UserFilter, ldap.EscapeFilter(email)),
+		attributes,
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, usecase.ErrInvalidCredentials
+		}
+
+		return nil, fmt.Errorf("failed to search ldap user: %w", err)
+	}
+
+	// Zero or ambiguous matches are treated as bad credentials to prevent enumeration
+	if len(result.Entries) != 1 {
+		return nil, usecase.ErrInvalidCredentials
+	}
+
+	return result.Entries[0], nil
+}
+
+// findGroups returns the DNs of the groups the entry is a member of
+func (v *LDAPCredentialVerifier) findGroups(conn LDAPConn, entry *ldap.Entry) ([]string, error) {
+	if v.config.GroupBaseDN == "" {
+		return entry.GetAttributeValues(v.config.GroupAttribute), nil
+	}
+
+	// Re-bind as the service account, the user may not be allowed to read groups
+	if v.config.BindDN != "" {
+		if err := conn.Bind(v.config.BindDN, v.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind ldap service account: %w", err)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		v.config.GroupBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		fmt.Sprintf(v.config.GroupFilter, ldap.EscapeFilter(entry.DN)),
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search ldap groups: %w", err)
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, group := range result.Entries {
+		groups = append(groups, group.DN)
+	}
+
+	return groups, nil
+}
+
+// mapRoles translates group DNs into local roles
+func (v *LDAPCredentialVerifier) mapRoles(groups []string) []string {
+	roles := append([]string{}, v.config.DefaultRoles...)
+
+	for _, group := range groups {
+		for key, groupRoles := range v.config.GroupRoles {
+			if strings.EqualFold(key, group) || strings.EqualFold(key, groupCN(group)) {
+				roles = append(roles, groupRoles...)
+			}
+		}
+	}
+
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+// syncShadowUser creates the local user on first login and keeps its roles up to date afterwards.
+// Accounts the directory didn't create are refused, a directory entry with their email must not take them over.
+func (v *LDAPCredentialVerifier) syncShadowUser(ctx context.Context, name, email string, roles []string) (*domain.User, error) {
+	user, err := v.userRepository.FindByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		// The directory owns the password, so the shadow user gets no usable local password
+		user = &domain.User{
+			Name:       name,
+			Email:      email,
+			Verified:   true,
+			AuthSource: domain.AuthSourceLDAP,
+			Roles:      roles,
+		}
+		if err := v.userRepository.Save(ctx, user); err != nil {
+			return nil, err
+		}
+
+		v.logger.Info("Created shadow user for ldap account", "user_id", user.ID)
+		return user, nil
+	}
+
+	if user.AuthSource != domain.AuthSourceLDAP {
+		v.logger.Warn("Refused ldap login to an account created outside the directory", "user_id", user.ID)
+		return nil, usecase.ErrInvalidCredentials
+	}
+
+	if !slices.Equal(user.Roles, roles) {
+		if err := v.userRepository.UpdateRoles(ctx, user.ID, roles); err != nil {
+			return nil, err
+		}
+		user.Roles = roles
+	}
+
+	return user, nil
+}
+
+// groupCN extracts the first CN value of a DN, e.g. "admins" from "cn=admins,ou=groups,dc=example,dc=com"
+func groupCN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ""
+	}
+
+	for _, attribute := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(attribute.Type, "cn") {
+			return attribute.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"auth/internal/domain"
+	"auth/internal/usecase"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	stubServiceDN       = "cn=service,dc=example,dc=com"
+	stubServicePassword = "service-secret"
+)
+
+// stubDirectory is an in-process LDAP directory holding users and their passwords
+type stubDirectory struct {
+	entries   []*ldap.Entry
+	passwords map[string]string
+}
+
+func (d *stubDirectory) dial(_ context.Context) (LDAPConn, error) {
+	return &stubConn{directory: d}, nil
+}
+
+// stubConn serves the search-then-bind flow of the verifier from a stubDirectory
+type stubConn struct {
+	directory *stubDirectory
+}
+
+func (c *stubConn) Bind(username, password string) error {
+	if username == stubServiceDN && password == stubServicePassword {
+		return nil
+	}
+
+	if expected, ok := c.directory.passwords[username]; ok && password != "" && password == expected {
+		return nil
+	}
+
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (c *stubConn) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	filter, err := ldap.CompileFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user filter of the tests is (&(objectClass=person)(mail=...)), the stub only matches on mail
+	mail := filter.Children[1].Children[1].Data.String()
+
+	result := &ldap.SearchResult{}
+	for _, entry := range c.directory.entries {
+		if entry.GetAttributeValue("mail") == mail {
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *stubConn) Close() error {
+	return nil
+}
+
+// stubUserRepository keeps users in memory, only the methods used by the verifier are implemented
+type stubUserRepository struct {
+	usecase.UserRepository
+	users []*domain.User
+}
+
+func (r *stubUserRepository) Save(_ context.Context, user *domain.User) error {
+	user.ID = int64(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *stubUserRepository) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (r *stubUserRepository) UpdateRoles(_ context.Context, userID int64, roles []string) error {
+	for _, user := range r.users {
+		if user.ID == userID {
+			user.Roles = roles
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+func newStubVerifier(users *stubUserRepository) *LDAPCredentialVerifier {
+	directory := &stubDirectory{
+		entries: []*ldap.Entry{
+			ldap.NewEntry("uid=jane,ou=people,dc=example,dc=com", map[string][]string{
+				"mail":     {"jane@example.com"},
+				"cn":       {"Jane Doe"},
+				"memberOf": {"cn=admins,ou=groups,dc=example,dc=com"},
+			}),
+		},
+		passwords: map[string]string{"uid=jane,ou=people,dc=example,dc=com": "directory-password"},
+	}
+
+	config := LDAPConfig{
+		BindDN:       stubServiceDN,
+		BindPassword: stubServicePassword,
+		BaseDN:       "dc=example,dc=com",
+		GroupRoles:   map[string][]string{"admins": {"admin"}},
+		DefaultRoles: []string{"user"},
+	}
+
+	return NewLDAPCredentialVerifier(config, directory.dial, users, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestLDAPCredentialVerifierCreatesShadowUser(t *testing.T) {
+	users := &stubUserRepository{}
+	verifier := newStubVerifier(users)
+
+	user, err := verifier.Verify(context.Background(), "jane@example.com", "directory-password")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if user.AuthSource != domain.AuthSourceLDAP || user.Name != "Jane Doe" || !user.Verified {
+		t.Errorf("Verify() user = %+v, want a verified ldap shadow user named Jane Doe", user)
+	}
+	if !slices.Equal(user.Roles, []string{"admin", "user"}) {
+		t.Errorf("Verify() roles = %v, want [admin user]", user.Roles)
+	}
+	if len(users.users) != 1 {
+		t.Errorf("Verify() saved %d users, want 1", len(users.users))
+	}
+}
+
+func TestLDAPCredentialVerifierSyncsRoles(t *testing.T) {
+	users := &stubUserRepository{users: []*domain.User{
+		{ID: 1, Email: "jane@example.com", AuthSource: domain.AuthSourceLDAP, Roles: []string{"user"}},
+	}}
+	verifier := newStubVerifier(users)
+
+	user, err := verifier.Verify(context.Background(), "jane@example.com", "directory-password")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if !slices.Equal(user.Roles, []string{"admin", "user"}) || !slices.Equal(users.users[0].Roles, user.Roles) {
+		t.Errorf("Verify() roles = %v, stored %v, want [admin user]", user.Roles, users.users[0].Roles)
+	}
+}
+
+func TestLDAPCredentialVerifierRejectsInvalidCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{name: "wrong password", email: "jane@example.com", password: "wrong"},
+		{name: "empty password", email: "jane@example.com", password: ""},
+		{name: "unknown user", email: "john@example.com", password: "directory-password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &stubUserRepository{}
+			verifier := newStubVerifier(users)
+
+			_, err := verifier.Verify(context.Background(), tt.email, tt.password)
+			if !errors.Is(err, usecase.ErrInvalidCredentials) {
+				t.Errorf("Verify() error = %v, want %v", err, usecase.ErrInvalidCredentials)
+			}
+			if len(users.users) != 0 {
+				t.Errorf("Verify() saved %d users, want 0", len(users.users))
+			}
+		})
+	}
+}
+
+func TestLDAPCredentialVerifierRefusesLocalAccount(t *testing.T) {
+	users := &stubUserRepository{users: []*domain.User{
+		{ID: 1, Email: "jane@example.com", AuthSource: domain.AuthSourceLocal, Roles: []string{"user"}},
+	}}
+	verifier := newStubVerifier(users)
+
+	_, err := verifier.Verify(context.Background(), "jane@example.com", "directory-password")
+	if !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Errorf("Verify() error = %v, want %v", err, usecase.ErrInvalidCredentials)
+	}
+	if !slices.Equal(users.users[0].Roles, []string{"user"}) {
+		t.Errorf("Verify() changed the roles of the local account to %v", users.users[0].Roles)
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogSMSSender implementation of SMSSender for development, nothing leaves the server.
+// Messages are appended to a file when a path is given, and logged otherwise.
+type LogSMSSender struct {
+	logger *slog.Logger
+	path   string
+	mu     sync.Mutex
+}
+
+// NewLogSMSSender creates a new log SMS sender, path is optional
+func NewLogSMSSender(logger *slog.Logger, path string) *LogSMSSender {
+	return &LogSMSSender{logger: logger, path: path}
+}
+
+// SendSMSCode writes the text the phone number would receive
+func (sender *LogSMSSender) SendSMSCode(ctx context.Context, phoneNumber string, purpose string, code string) error {
+	return sender.write("sms", phoneNumber, phoneCodeMessage(purpose, code))
+}
+
+// SendVoiceCode writes what the call to the phone number would say
+func (sender *LogSMSSender) SendVoiceCode(ctx context.Context, phoneNumber string, purpose string, code string) error {
+	return sender.write("voice", phoneNumber, phoneCodeMessage(purpose, spokenCode(code)))
+}
+
+func (sender *LogSMSSender) write(channel string, phoneNumber string, message string) error {
+	if sender.path == "" {
+		sender.logger.Info("Phone message", "channel", channel, "phone_number", phoneNumber, "message", message)
+		return nil
+	}
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	file, err := os.OpenFile(sender.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open sms log file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\t%s\n", time.Now().Format(time.RFC3339), channel, phoneNumber, message)
+	return err
+}
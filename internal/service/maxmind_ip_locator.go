@@ -0,0 +1,70 @@
+package service
+
+import (
+	"auth/internal/domain"
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// maxMindCityRecord holds the fields read from a GeoIP2 or GeoLite2 City database record
+type maxMindCityRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// MaxMindIPLocator looks IP addresses up in a local MaxMind format database, such as GeoLite2 City.
+// Lookups never leave the server.
+type MaxMindIPLocator struct {
+	reader *maxminddb.Reader
+}
+
+// OpenMaxMindIPLocator opens a MaxMind format database file
+func OpenMaxMindIPLocator(path string) (*MaxMindIPLocator, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+
+	return &MaxMindIPLocator{reader: reader}, nil
+}
+
+// Locate returns the location of the IP address, or nil when it isn't in the database, e.g. private addresses
+func (l *MaxMindIPLocator) Locate(ipAddress string) (*domain.GeoLocation, error) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return nil, nil
+	}
+
+	var record maxMindCityRecord
+	_, found, err := l.reader.LookupNetwork(ip, &record)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return &domain.GeoLocation{
+		City:        record.City.Names["en"],
+		Country:     record.Country.Names["en"],
+		CountryCode: record.Country.ISOCode,
+		Latitude:    record.Location.Latitude,
+		Longitude:   record.Location.Longitude,
+	}, nil
+}
+
+// Close closes the database file
+func (l *MaxMindIPLocator) Close() error {
+	return l.reader.Close()
+}
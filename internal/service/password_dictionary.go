@@ -0,0 +1,82 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// commonPasswords lists frequently used passwords and words, most common first.
+// The position is used as the rank when estimating how many guesses a match costs.
+var commonPasswords = strings.Fields(`
+123456 password 12345678 qwerty 123456789 12345 1234 111111 1234567 dragon
+123123 baseball abc123 football monkey letmein 696969 shadow master 666666
+qwertyuiop 123321 mustang 1234567890 michael 654321 superman 1qaz2wsx 7777777 121212
+000000 qazwsx 123qwe killer trustno1 jordan jennifer zxcvbnm asdfgh hunter
+buster soccer harley batman andrew tigger sunshine iloveyou 2000 charlie
+robert thomas hockey ranger daniel starwars klaster 112233 george computer
+michelle jessica pepper 1111 zxcvbn 555555 11111111 131313 freedom 777777
+pass maggie 159753 aaaaaa ginger princess joshua cheese amanda summer
+love ashley nicole chelsea biteme matthew access yankees 987654321 dallas
+austin thunder taylor matrix mobilemail mom monitor monitoring montana moon moscow
+welcome admin administrator login passw0rd secret changeme default guest root
+hello whatever flower orange purple silver golden winter spring autumn
+dragonfly butterfly chocolate cookie banana apple football1 baseball1 qwerty123 password1
+letmein1 welcome1 abcdef abcd1234 asdf qwer zaq12wsx q1w2e3r4 p@ssw0rd internet
+company server office secure security system manager service summer2024 winter2024
+`)
+
+// commonPasswordRanks maps each common password to its rank, starting at 1
+var commonPasswordRanks = func() map[string]int {
+	ranks := make(map[string]int, len(commonPasswords))
+	for i, password := range commonPasswords {
+		if _, ok := ranks[password]; !ok {
+			ranks[password] = i + 1
+		}
+	}
+
+	return ranks
+}()
+
+// keyboardRows are adjacent key runs that are commonly used as passwords
+var keyboardRows = []string{
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+	"1234567890",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik9ol0p",
+}
+
+// longestCommonPassword and longestKeyboardRow bound the length of the words looked up in a password
+var (
+	longestCommonPassword = longestLength(commonPasswords)
+	longestKeyboardRow    = longestLength(keyboardRows)
+)
+
+// leetSubstitutions maps common character substitutions back to the letter they replace
+var leetSubstitutions = map[rune]rune{
+	'4': 'a',
+	'@': 'a',
+	'8': 'b',
+	'(': 'c',
+	'3': 'e',
+	'6': 'g',
+	'1': 'i',
+	'!': 'i',
+	'|': 'l',
+	'0': 'o',
+	'5': 's',
+	'$': 's',
+	'7': 't',
+	'+': 't',
+	'2': 'z',
+}
+
+// longestLength returns the length in characters of the longest word
+func longestLength(words []string) int {
+	longest := 0
+	for _, word := range words {
+		longest = max(longest, utf8.RuneCountInString(word))
+	}
+
+	return longest
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported password hashing algorithms
+const (
+	PasswordHashArgon2id = "argon2id"
+	PasswordHashBcrypt   = "bcrypt"
+)
+
+// ErrUnsupportedHash is returned when a stored hash wasn't produced by a supported algorithm
+var ErrUnsupportedHash = errors.New("unsupported password hash format")
+
+// Upper limits of the argon2id parameters, beyond them a login takes minutes or exhausts the memory of the host
+const (
+	maxArgon2Memory     = 4 * 1024 * 1024
+	maxArgon2Iterations = 100
+)
+
+// PasswordHashConfig holds all the necessary configuration for the password hasher.
+type PasswordHashConfig struct {
+	// Algorithm is used for new hashes, existing hashes of the other algorithm are still verified
+	Algorithm string
+
+	// Argon2id parameters, Memory is in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+
+	BcryptCost int
+
+	// Pepper is a server-side secret mixed into argon2id hashes with HMAC-SHA256.
+	// Bcrypt hashes can't record it, so they are never peppered.
+	Pepper string
+}
+
+// DefaultPasswordHashConfig returns the argon2id parameters recommended by OWASP
+func DefaultPasswordHashConfig() PasswordHashConfig {
+	return PasswordHashConfig{
+		Algorithm:   PasswordHashArgon2id,
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+		BcryptCost:  bcrypt.DefaultCost,
+	}
+}
+
+// Validate checks that the parameters can hash passwords, argon2 panics on zero iterations or parallelism
+// and bcrypt rejects costs outside of its range
+func (c PasswordHashConfig) Validate() error {
+	if c.Algorithm != PasswordHashArgon2id && c.Algorithm != PasswordHashBcrypt {
+		return fmt.Errorf("unsupported password hash algorithm %q", c.Algorithm)
+	}
+
+	if c.Parallelism == 0 {
+		return errors.New("argon2 parallelism must be at least 1")
+	}
+	if c.Iterations == 0 || c.Iterations > maxArgon2Iterations {
+		return fmt.Errorf("argon2 iterations must be between 1 and %d", maxArgon2Iterations)
+	}
+	// Argon2 needs 8 KiB per lane
+	if c.Memory < 8*uint32(c.Parallelism) || c.Memory > maxArgon2Memory {
+		return fmt.Errorf("argon2 memory must be between %d and %d KiB", 8*uint32(c.Parallelism), maxArgon2Memory)
+	}
+	if c.SaltLength < 8 || c.KeyLength < 16 {
+		return errors.New("argon2 salts must be at least 8 bytes and keys at least 16 bytes")
+	}
+
+	if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	return nil
+}
+
+// PasswordHasher hashes passwords with argon2id (PHC string format) or bcrypt
+type PasswordHasher struct {
+	config   PasswordHashConfig
+	pepperID string
+}
+
+// argon2Hash represents a decoded argon2id PHC string
+type argon2Hash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	keyID       string
+	salt        []byte
+	key         []byte
+}
+
+// NewPasswordHasher creates a new password hasher
+func NewPasswordHasher(config PasswordHashConfig) *PasswordHasher {
+	hasher := &PasswordHasher{config: config}
+
+	// The key ID records which pepper a hash was made with, without revealing it
+	if config.Pepper != "" {
+		sum := sha256.Sum256([]byte(config.Pepper))
+		hasher.pepperID = base64.RawStdEncoding.EncodeToString(sum[:6])
+	}
+
+	return hasher
+}
+
+// Hash hashes the password with the configured algorithm
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.config.Algorithm == PasswordHashBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.config.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+
+		return string(hash), nil
+	}
+
+	salt := make([]byte, h.config.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey(h.pepper(password, h.pepperID), salt, h.config.Iterations, h.config.Memory, h.config.Parallelism, h.config.KeyLength)
+
+	params := fmt.Sprintf("m=%d,t=%d,p=%d", h.config.Memory, h.config.Iterations, h.config.Parallelism)
+	if h.pepperID != "" {
+		params += ",keyid=" + h.pepperID
+	}
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$%s$%s$%s",
+		argon2.Version,
+		params,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify compares the password with an argon2id or bcrypt hash
+func (h *PasswordHasher) Verify(password string, encodedHash string) (bool, error) {
+	if isBcryptHash(encodedHash) {
+		err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+
+		return err == nil, err
+	}
+
+	decoded, err := decodeArgon2Hash(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	// A hash peppered with another secret can't be verified, the user has to reset the password
+	if decoded.keyID != "" && decoded.keyID != h.pepperID {
+		return false, fmt.Errorf("password hash uses unknown pepper %q", decoded.keyID)
+	}
+
+	key := argon2.IDKey(h.pepper(password, decoded.keyID), decoded.salt, decoded.iterations, decoded.memory, decoded.parallelism, uint32(len(decoded.key)))
+
+	return subtle.ConstantTimeCompare(key, decoded.key) == 1, nil
+}
+
+// NeedsRehash reports whether the hash differs from what Hash would produce today
+func (h *PasswordHasher) NeedsRehash(encodedHash string) bool {
+	if isBcryptHash(encodedHash) {
+		if h.config.Algorithm != PasswordHashBcrypt {
+			return true
+		}
+
+		cost, err := bcrypt.Cost([]byte(encodedHash))
+		return err != nil || cost != h.config.BcryptCost
+	}
+
+	if h.config.Algorithm == PasswordHashBcrypt {
+		return true
+	}
+
+	decoded, err := decodeArgon2Hash(encodedHash)
+	if err != nil {
+		return true
+	}
+
+	return decoded.memory != h.config.Memory ||
+		decoded.iterations != h.config.Iterations ||
+		decoded.parallelism != h.config.Parallelism ||
+		uint32(len(decoded.key)) != h.config.KeyLength ||
+		decoded.keyID != h.pepperID
+}
+
+// pepper mixes the pepper into the password when the hash uses one
+func (h *PasswordHasher) pepper(password string, keyID string) []byte {
+	if keyID == "" {
+		return []byte(password)
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.config.Pepper))
+	mac.Write([]byte(password))
+
+	return mac.Sum(nil)
+}
+
+// isBcryptHash reports whether the hash uses the modular crypt format of bcrypt
+func isBcryptHash(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") || strings.HasPrefix(encodedHash, "$2b$") || strings.HasPrefix(encodedHash, "$2y$")
+}
+
+// decodeArgon2Hash parses a PHC string such as $argon2id$v=19$m=65536,t=3,p=2$salt$key
+func decodeArgon2Hash(encodedHash string) (*argon2Hash, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != PasswordHashArgon2id {
+		return nil, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrUnsupportedHash
+	}
+
+	decoded := &argon2Hash{}
+	for _, param := range strings.Split(parts[3], ",") {
+		name, value, _ := strings.Cut(param, "=")
+
+		var err error
+		switch name {
+		case "m":
+			_, err = fmt.Sscanf(value, "%d", &decoded.memory)
+		case "t":
+			_, err = fmt.Sscanf(value, "%d", &decoded.iterations)
+		case "p":
+			_, err = fmt.Sscanf(value, "%d", &decoded.parallelism)
+		case "keyid":
+			decoded.keyID = value
+		}
+		if err != nil {
+			return nil, ErrUnsupportedHash
+		}
+	}
+
+	var err error
+	if decoded.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrUnsupportedHash
+	}
+	if decoded.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, ErrUnsupportedHash
+	}
+
+	if decoded.memory == 0 || decoded.iterations == 0 || decoded.parallelism == 0 || len(decoded.key) == 0 {
+		return nil, ErrUnsupportedHash
+	}
+
+	return decoded, nil
+}
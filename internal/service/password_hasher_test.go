@@ -0,0 +1,199 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testHashConfig keeps argon2id cheap so the tests run fast
+func testHashConfig() PasswordHashConfig {
+	config := DefaultPasswordHashConfig()
+	config.Memory = 64
+	config.Iterations = 1
+	config.Parallelism = 1
+	config.BcryptCost = bcrypt.MinCost
+
+	return config
+}
+
+func TestDecodeArgon2Hash(t *testing.T) {
+	tests := []struct {
+		name        string
+		encodedHash string
+		wantErr     bool
+		want        argon2Hash
+	}{
+		{
+			name:        "valid",
+			encodedHash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$a2V5a2V5a2V5",
+			want:        argon2Hash{memory: 65536, iterations: 3, parallelism: 2},
+		},
+		{
+			name:        "valid with key ID",
+			encodedHash: "$argon2id$v=19$m=64,t=1,p=1,keyid=abc$c2FsdHNhbHQ$a2V5a2V5a2V5",
+			want:        argon2Hash{memory: 64, iterations: 1, parallelism: 1, keyID: "abc"},
+		},
+		{name: "argon2i", encodedHash: "$argon2i$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5", wantErr: true},
+		{name: "old version", encodedHash: "$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5", wantErr: true},
+		{name: "missing part", encodedHash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ", wantErr: true},
+		{name: "zero iterations", encodedHash: "$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5", wantErr: true},
+		{name: "zero parallelism", encodedHash: "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHQ$a2V5a2V5a2V5", wantErr: true},
+		{name: "missing memory", encodedHash: "$argon2id$v=19$t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5", wantErr: true},
+		{name: "parallelism overflow", encodedHash: "$argon2id$v=19$m=64,t=1,p=256$c2FsdHNhbHQ$a2V5a2V5a2V5", wantErr: true},
+		{name: "non numeric", encodedHash: "$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5", wantErr: true},
+		{name: "invalid salt", encodedHash: "$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5a2V5a2V5", wantErr: true},
+		{name: "empty key", encodedHash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeArgon2Hash(tt.encodedHash)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedHash) {
+					t.Errorf("decodeArgon2Hash() error = %v, want %v", err, ErrUnsupportedHash)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("decodeArgon2Hash() error = %v", err)
+			}
+			if got.memory != tt.want.memory || got.iterations != tt.want.iterations ||
+				got.parallelism != tt.want.parallelism || got.keyID != tt.want.keyID {
+				t.Errorf("decodeArgon2Hash() = m=%d,t=%d,p=%d,keyid=%q, want m=%d,t=%d,p=%d,keyid=%q",
+					got.memory, got.iterations, got.parallelism, got.keyID,
+					tt.want.memory, tt.want.iterations, tt.want.parallelism, tt.want.keyID)
+			}
+		})
+	}
+}
+
+func TestPasswordHasherVerify(t *testing.T) {
+	peppered := testHashConfig()
+	peppered.Pepper = "pepper"
+
+	bcryptConfig := testHashConfig()
+	bcryptConfig.Algorithm = PasswordHashBcrypt
+
+	for _, config := range []PasswordHashConfig{testHashConfig(), peppered, bcryptConfig} {
+		hasher := NewPasswordHasher(config)
+
+		hash, err := hasher.Hash("correct horse")
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+
+		if ok, err := hasher.Verify("correct horse", hash); err != nil || !ok {
+			t.Errorf("Verify(%q) = %v, %v, want true", hash, ok, err)
+		}
+		if ok, err := hasher.Verify("wrong horse", hash); err != nil || ok {
+			t.Errorf("Verify(%q) with a wrong password = %v, %v, want false", hash, ok, err)
+		}
+	}
+}
+
+func TestPasswordHasherVerifyUnknownPepper(t *testing.T) {
+	config := testHashConfig()
+	config.Pepper = "old pepper"
+	hash, err := NewPasswordHasher(config).Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	config.Pepper = "new pepper"
+	if ok, err := NewPasswordHasher(config).Verify("correct horse", hash); err == nil || ok {
+		t.Errorf("Verify() with another pepper = %v, %v, want an error", ok, err)
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	base := testHashConfig()
+	argon2Hash, err := NewPasswordHasher(base).Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	bcryptConfig := base
+	bcryptConfig.Algorithm = PasswordHashBcrypt
+	bcryptHash, err := NewPasswordHasher(bcryptConfig).Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(config *PasswordHashConfig)
+		hash   string
+		want   bool
+	}{
+		{name: "same argon2id parameters", change: func(*PasswordHashConfig) {}, hash: argon2Hash, want: false},
+		{name: "more memory", change: func(c *PasswordHashConfig) { c.Memory *= 2 }, hash: argon2Hash, want: true},
+		{name: "more iterations", change: func(c *PasswordHashConfig) { c.Iterations++ }, hash: argon2Hash, want: true},
+		{name: "more parallelism", change: func(c *PasswordHashConfig) { c.Parallelism++ }, hash: argon2Hash, want: true},
+		{name: "longer key", change: func(c *PasswordHashConfig) { c.KeyLength *= 2 }, hash: argon2Hash, want: true},
+		{name: "pepper added", change: func(c *PasswordHashConfig) { c.Pepper = "pepper" }, hash: argon2Hash, want: true},
+		{name: "switched to bcrypt", change: func(c *PasswordHashConfig) { c.Algorithm = PasswordHashBcrypt }, hash: argon2Hash, want: true},
+		{name: "bcrypt to argon2id", change: func(*PasswordHashConfig) {}, hash: bcryptHash, want: true},
+		{name: "same bcrypt cost", change: func(c *PasswordHashConfig) { c.Algorithm = PasswordHashBcrypt }, hash: bcryptHash, want: false},
+		{
+			name:   "higher bcrypt cost",
+			change: func(c *PasswordHashConfig) { c.Algorithm = PasswordHashBcrypt; c.BcryptCost++ },
+			hash:   bcryptHash,
+			want:   true,
+		},
+		{name: "unparsable hash", change: func(*PasswordHashConfig) {}, hash: "$argon2id$garbage", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := base
+			tt.change(&config)
+
+			if got := NewPasswordHasher(config).NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordHashConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(config *PasswordHashConfig)
+		wantErr string
+	}{
+		{name: "defaults", change: func(*PasswordHashConfig) {}},
+		{name: "bcrypt", change: func(c *PasswordHashConfig) { c.Algorithm = PasswordHashBcrypt }},
+		{name: "unknown algorithm", change: func(c *PasswordHashConfig) { c.Algorithm = "scrypt" }, wantErr: "algorithm"},
+		{name: "zero iterations", change: func(c *PasswordHashConfig) { c.Iterations = 0 }, wantErr: "iterations"},
+		{name: "too many iterations", change: func(c *PasswordHashConfig) { c.Iterations = maxArgon2Iterations + 1 }, wantErr: "iterations"},
+		{name: "zero parallelism", change: func(c *PasswordHashConfig) { c.Parallelism = 0 }, wantErr: "parallelism"},
+		{name: "memory below lanes", change: func(c *PasswordHashConfig) { c.Memory = 8*uint32(c.Parallelism) - 1 }, wantErr: "memory"},
+		{name: "too much memory", change: func(c *PasswordHashConfig) { c.Memory = maxArgon2Memory + 1 }, wantErr: "memory"},
+		{name: "short key", change: func(c *PasswordHashConfig) { c.KeyLength = 8 }, wantErr: "keys"},
+		{name: "bcrypt cost too low", change: func(c *PasswordHashConfig) { c.BcryptCost = bcrypt.MinCost - 1 }, wantErr: "bcrypt"},
+		{name: "bcrypt cost too high", change: func(c *PasswordHashConfig) { c.BcryptCost = bcrypt.MaxCost + 1 }, wantErr: "bcrypt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultPasswordHashConfig()
+			tt.change(&config)
+
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want an error about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
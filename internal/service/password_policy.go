@@ -0,0 +1,577 @@
+package service
+
+import (
+	"auth/internal/usecase"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BcryptMaxPasswordBytes is the longest password bcrypt can hash, it rejects anything longer
+const BcryptMaxPasswordBytes = 72
+
+// Password policy violation codes
+const (
+	PasswordViolationTooShort          = "too_short"
+	PasswordViolationTooLong           = "too_long"
+	PasswordViolationMissingUppercase  = "missing_uppercase"
+	PasswordViolationMissingLowercase  = "missing_lowercase"
+	PasswordViolationMissingDigit      = "missing_digit"
+	PasswordViolationMissingSymbol     = "missing_symbol"
+	PasswordViolationContainsUserInput = "contains_user_input"
+	PasswordViolationBreached          = "breached"
+	PasswordViolationTooWeak           = "too_weak"
+)
+
+// Patterns recognized by the strength estimator
+const (
+	passwordPatternDictionary = "dictionary"
+	passwordPatternUserInput  = "user_input"
+	passwordPatternRepeat     = "repeat"
+	passwordPatternSequence   = "sequence"
+	passwordPatternKeyboard   = "keyboard"
+	passwordPatternYear       = "year"
+)
+
+// bruteforceBits is the cost of a character that isn't part of any pattern, a cardinality of 10 like zxcvbn
+var bruteforceBits = math.Log2(10)
+
+// PasswordPolicyConfig holds all the necessary configuration for the password policy.
+type PasswordPolicyConfig struct {
+	// MinLength and MaxLength are counted in characters
+	MinLength int
+	MaxLength int
+	// MaxBytes caps the UTF-8 encoded length, set it to BcryptMaxPasswordBytes when hashing with bcrypt.
+	// Zero disables the limit.
+	MaxBytes int
+
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+
+	// MinScore is the lowest accepted strength score, from 0 to 4
+	MinScore int
+	// BlockUserInputs rejects passwords containing the user's name or email
+	BlockUserInputs bool
+}
+
+// DefaultPasswordPolicyConfig returns a policy following the NIST SP 800-63B guidance:
+// a minimum length and a strength check instead of character class rules.
+func DefaultPasswordPolicyConfig() PasswordPolicyConfig {
+	return PasswordPolicyConfig{
+		MinLength:       8,
+		MaxLength:       128,
+		MinScore:        2,
+		BlockUserInputs: true,
+	}
+}
+
+// PasswordPolicy checks passwords against length and character class rules
+// and estimates their strength by looking for guessable patterns, like zxcvbn.
+type PasswordPolicy struct {
+	config            PasswordPolicyConfig
+	breachedPasswords usecase.BreachedPasswordChecker
+	logger            *slog.Logger
+}
+
+// passwordMatch represents a guessable pattern found in a password, start and end are rune indexes
+type passwordMatch struct {
+	pattern string
+	start   int
+	end     int
+	bits    float64
+	// leet and capitalized record whether the match relied on substitutions or capitalization
+	leet        bool
+	capitalized bool
+}
+
+// NewPasswordPolicy creates a new password policy, breachedPasswords is optional
+func NewPasswordPolicy(
+	config PasswordPolicyConfig,
+	breachedPasswords usecase.BreachedPasswordChecker,
+	logger *slog.Logger,
+) *PasswordPolicy {
+	return &PasswordPolicy{
+		config:            config,
+		breachedPasswords: breachedPasswords,
+		logger:            logger,
+	}
+}
+
+// Check evaluates the password against the policy
+func (p *PasswordPolicy) Check(password string, userInputs ...string) *usecase.PasswordCheckResult {
+	result := &usecase.PasswordCheckResult{
+		Violations:  []usecase.PasswordViolation{},
+		Suggestions: []string{},
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		result.Violations = append(result.Violations, usecase.PasswordViolation{
+			Code:    PasswordViolationTooShort,
+			Message: fmt.Sprintf("password must be at least %d characters long", p.config.MinLength),
+		})
+	}
+	if p.config.MaxLength > 0 && length > p.config.MaxLength {
+		result.Violations = append(result.Violations, usecase.PasswordViolation{
+			Code:    PasswordViolationTooLong,
+			Message: fmt.Sprintf("password must be at most %d characters long", p.config.MaxLength),
+		})
+	} else if p.config.MaxBytes > 0 && len(password) > p.config.MaxBytes {
+		result.Violations = append(result.Violations, usecase.PasswordViolation{
+			Code:    PasswordViolationTooLong,
+			Message: fmt.Sprintf("password must be at most %d bytes long, non-ASCII characters take up to 4 bytes", p.config.MaxBytes),
+		})
+	}
+
+	// Passwords over the limit are rejected anyway, don't spend time looking for patterns in them
+	if slices.ContainsFunc(result.Violations, func(v usecase.PasswordViolation) bool {
+		return v.Code == PasswordViolationTooLong
+	}) {
+		return result
+	}
+
+	result.Violations = append(result.Violations, p.checkCharacterClasses(password)...)
+
+	tokens := userInputTokens(userInputs)
+	matches := findPasswordMatches(password, tokens)
+
+	if p.config.BlockUserInputs && slices.ContainsFunc(matches, func(m passwordMatch) bool {
+		return m.pattern == passwordPatternUserInput
+	}) {
+		result.Violations = append(result.Violations, usecase.PasswordViolation{
+			Code:    PasswordViolationContainsUserInput,
+			Message: "password must not contain your name or email address",
+		})
+	}
+
+	if p.isBreached(password) {
+		result.Violations = append(result.Violations, usecase.PasswordViolation{
+			Code:    PasswordViolationBreached,
+			Message: "password has appeared in a data breach, choose a different one",
+		})
+	}
+
+	selected := selectPasswordMatches(matches)
+	result.Score = passwordScore(estimateBits(password, selected))
+	if result.Score < p.config.MinScore {
+		result.Violations = append(result.Violations, usecase.PasswordViolation{
+			Code:    PasswordViolationTooWeak,
+			Message: "password is too easy to guess",
+		})
+	}
+
+	result.Suggestions = passwordSuggestions(password, selected, result.Score)
+	result.Valid = len(result.Violations) == 0
+
+	return result
+}
+
+// isBreached looks the password up in the breached password list.
+// A failed lookup is logged and lets the password through, the other rules still apply.
+func (p *PasswordPolicy) isBreached(password string) bool {
+	if p.breachedPasswords == nil || password == "" {
+		return false
+	}
+
+	breached, err := p.breachedPasswords.IsBreached(password)
+	if err != nil {
+		p.logger.Warn("Failed to check breached password list", "error", err)
+		return false
+	}
+
+	return breached
+}
+
+// checkCharacterClasses returns a violation for each required character class the password lacks
+func (p *PasswordPolicy) checkCharacterClasses(password string) []usecase.PasswordViolation {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var violations []usecase.PasswordViolation
+	if p.config.RequireUppercase && !hasUpper {
+		violations = append(violations, usecase.PasswordViolation{
+			Code:    PasswordViolationMissingUppercase,
+			Message: "password must contain an uppercase letter",
+		})
+	}
+	if p.config.RequireLowercase && !hasLower {
+		violations = append(violations, usecase.PasswordViolation{
+			Code:    PasswordViolationMissingLowercase,
+			Message: "password must contain a lowercase letter",
+		})
+	}
+	if p.config.RequireDigit && !hasDigit {
+		violations = append(violations, usecase.PasswordViolation{
+			Code:    PasswordViolationMissingDigit,
+			Message: "password must contain a digit",
+		})
+	}
+	if p.config.RequireSymbol && !hasSymbol {
+		violations = append(violations, usecase.PasswordViolation{
+			Code:    PasswordViolationMissingSymbol,
+			Message: "password must contain a symbol",
+		})
+	}
+
+	return violations
+}
+
+// userInputTokens splits the user's name and email into lowercase words worth looking for,
+// e.g. "Jane Doe" and "jane.doe@example.com" give "jane", "doe" and "janedoe"
+func userInputTokens(userInputs []string) []string {
+	var tokens []string
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		// Only the local part of an email is personal, the domain is shared with others
+		if at := strings.LastIndex(input, "@"); at >= 0 {
+			input = input[:at]
+		}
+
+		words := strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) > 1 {
+			words = append(words, strings.Join(words, ""))
+		}
+
+		for _, word := range words {
+			if utf8.RuneCountInString(word) >= 3 {
+				tokens = append(tokens, word)
+			}
+		}
+	}
+
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
+
+// findPasswordMatches returns every guessable pattern in the password, matches may overlap
+func findPasswordMatches(password string, userTokens []string) []passwordMatch {
+	runes := []rune(password)
+	lower := make([]rune, len(runes))
+	unleet := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+		unleet[i] = lower[i]
+		if substitute, ok := leetSubstitutions[lower[i]]; ok {
+			unleet[i] = substitute
+		}
+	}
+
+	var matches []passwordMatch
+
+	// Words longer than the longest dictionary word or user token can't match
+	maxWordLength := longestCommonPassword
+	for _, token := range userTokens {
+		maxWordLength = max(maxWordLength, utf8.RuneCountInString(token))
+	}
+
+	// Dictionary and user input matches, with or without substitutions
+	for i := range runes {
+		for j := i + 3; j <= min(len(runes), i+maxWordLength); j++ {
+			for _, candidate := range []struct {
+				word string
+				leet bool
+			}{
+				{string(lower[i:j]), false},
+				{string(unleet[i:j]), true},
+			} {
+				if candidate.leet && candidate.word == string(lower[i:j]) {
+					continue
+				}
+
+				bits, pattern := 0.0, ""
+				if rank, ok := commonPasswordRanks[candidate.word]; ok {
+					bits, pattern = math.Log2(float64(rank)), passwordPatternDictionary
+				} else if slices.Contains(userTokens, candidate.word) {
+					bits, pattern = 1, passwordPatternUserInput
+				} else {
+					continue
+				}
+
+				match := passwordMatch{pattern: pattern, start: i, end: j, bits: bits, leet: candidate.leet}
+				if candidate.leet {
+					match.bits += substitutionBits(lower[i:j], unleet[i:j])
+				}
+				if capitalization := capitalizationBits(runes[i:j]); capitalization > 0 {
+					match.bits += capitalization
+					match.capitalized = true
+				}
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	// Repeats such as "aaaa"
+	for i := 0; i < len(lower); {
+		j := i + 1
+		for j < len(lower) && lower[j] == lower[i] {
+			j++
+		}
+		if j-i >= 3 {
+			matches = append(matches, passwordMatch{
+				pattern: passwordPatternRepeat,
+				start:   i,
+				end:     j,
+				bits:    math.Log2(characterClassSize(lower[i])) + math.Log2(float64(j-i)),
+			})
+		}
+		i = j
+	}
+
+	// Sequences such as "abcd", "4321" or "acegi"
+	for i := 0; i+2 < len(lower); {
+		delta := lower[i+1] - lower[i]
+		j := i + 2
+		for j < len(lower) && lower[j]-lower[j-1] == delta {
+			j++
+		}
+		if delta != 0 && delta >= -5 && delta <= 5 && j-i >= 3 {
+			bits := math.Log2(characterClassSize(lower[i])) + math.Log2(float64(j-i))
+			if delta < 0 {
+				bits++
+			}
+			matches = append(matches, passwordMatch{pattern: passwordPatternSequence, start: i, end: j, bits: bits})
+			i = j - 1
+			continue
+		}
+		i++
+	}
+
+	// Keyboard patterns such as "qwerty" or "lkjhg"
+	for i := range lower {
+		for j := i + 4; j <= min(len(lower), i+longestKeyboardRow); j++ {
+			word := string(lower[i:j])
+			for _, row := range keyboardRows {
+				if strings.Contains(row, word) || strings.Contains(reverse(row), word) {
+					matches = append(matches, passwordMatch{
+						pattern: passwordPatternKeyboard,
+						start:   i,
+						end:     j,
+						bits:    math.Log2(float64(len(keyboardRows)*2)) + math.Log2(float64(j-i)),
+					})
+					break
+				}
+			}
+		}
+	}
+
+	// Years between 1900 and 2099
+	for i := 0; i+4 <= len(lower); i++ {
+		year := string(lower[i : i+4])
+		if (strings.HasPrefix(year, "19") || strings.HasPrefix(year, "20")) && isDigits(year) {
+			matches = append(matches, passwordMatch{pattern: passwordPatternYear, start: i, end: i + 4, bits: math.Log2(200)})
+		}
+	}
+
+	return matches
+}
+
+// selectPasswordMatches greedily picks the non-overlapping matches that save the most guesses
+func selectPasswordMatches(matches []passwordMatch) []passwordMatch {
+	sorted := slices.Clone(matches)
+	slices.SortStableFunc(sorted, func(a, b passwordMatch) int {
+		savingA := float64(a.end-a.start)*bruteforceBits - a.bits
+		savingB := float64(b.end-b.start)*bruteforceBits - b.bits
+		switch {
+		case savingA > savingB:
+			return -1
+		case savingA < savingB:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	var selected []passwordMatch
+	for _, match := range sorted {
+		if float64(match.end-match.start)*bruteforceBits <= match.bits {
+			continue
+		}
+
+		overlaps := slices.ContainsFunc(selected, func(s passwordMatch) bool {
+			return match.start < s.end && s.start < match.end
+		})
+		if !overlaps {
+			selected = append(selected, match)
+		}
+	}
+
+	slices.SortFunc(selected, func(a, b passwordMatch) int { return a.start - b.start })
+	return selected
+}
+
+// estimateBits returns the base 2 logarithm of the guesses needed to find the password
+func estimateBits(password string, matches []passwordMatch) float64 {
+	covered := 0
+	bits := 0.0
+	for _, match := range matches {
+		covered += match.end - match.start
+		bits += match.bits
+	}
+	bits += float64(utf8.RuneCountInString(password)-covered) * bruteforceBits
+
+	// Each extra segment adds a little uncertainty about where the patterns are joined
+	if len(matches) > 1 {
+		bits += float64(len(matches) - 1)
+	}
+
+	return bits
+}
+
+// passwordScore maps the estimated guesses to zxcvbn's 0-4 scale
+func passwordScore(bits float64) int {
+	guesses := bits * math.Log10(2)
+	switch {
+	case guesses < 3:
+		return 0
+	case guesses < 6:
+		return 1
+	case guesses < 8:
+		return 2
+	case guesses < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// passwordSuggestions returns feedback the user can act on to strengthen the password
+func passwordSuggestions(password string, matches []passwordMatch, score int) []string {
+	suggestions := []string{}
+	add := func(suggestion string) {
+		if !slices.Contains(suggestions, suggestion) {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	for _, match := range matches {
+		switch match.pattern {
+		case passwordPatternDictionary:
+			if match.start == 0 && match.end == utf8.RuneCountInString(password) {
+				add("This is a very common password")
+			} else {
+				add("Avoid common passwords and words")
+			}
+		case passwordPatternUserInput:
+			add("Avoid using your name or email address")
+		case passwordPatternRepeat:
+			add(`Avoid repeated characters like "aaa"`)
+		case passwordPatternSequence:
+			add(`Avoid sequences like "abc" or "6543"`)
+		case passwordPatternKeyboard:
+			add(`Avoid keyboard patterns like "qwerty"`)
+		case passwordPatternYear:
+			add("Avoid years that are associated with you")
+		}
+
+		if match.capitalized {
+			add("Capitalization doesn't help very much")
+		}
+		if match.leet {
+			add(`Predictable substitutions like "@" instead of "a" don't help very much`)
+		}
+	}
+
+	if score < 3 {
+		add("Add another word or two, uncommon words are better")
+	}
+
+	return suggestions
+}
+
+// capitalizationBits returns the extra guesses needed to find the capitalization of a word
+func capitalizationBits(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 0
+	}
+	// Capitalizing the first letter or the whole word is the first thing attackers try
+	if lower == 0 || (upper == 1 && unicode.IsUpper(word[0])) {
+		return 1
+	}
+
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+
+	return math.Log2(variations)
+}
+
+// substitutionBits returns the extra guesses needed to find the substitutions used in a word
+func substitutionBits(original, substituted []rune) float64 {
+	count := 0
+	for i := range original {
+		if original[i] != substituted[i] {
+			count++
+		}
+	}
+
+	return float64(count)
+}
+
+// characterClassSize returns the number of characters in the class of r
+func characterClassSize(r rune) float64 {
+	switch {
+	case r >= '0' && r <= '9':
+		return 10
+	case r >= 'a' && r <= 'z':
+		return 26
+	case r < utf8.RuneSelf:
+		return 33
+	default:
+		return 100
+	}
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+
+	return result
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return value != ""
+}
+
+func reverse(value string) string {
+	runes := []rune(value)
+	slices.Reverse(runes)
+	return string(runes)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"auth/internal/usecase"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// strongPassword has no guessable pattern and every character class
+const strongPassword = "xK9#mQ2$vL7@"
+
+// stubBreachedPasswords reports the passwords it holds as breached, or fails every lookup with err
+type stubBreachedPasswords struct {
+	passwords []string
+	err       error
+}
+
+func (s stubBreachedPasswords) IsBreached(password string) (bool, error) {
+	return slices.Contains(s.passwords, password), s.err
+}
+
+func violationCodes(result *usecase.PasswordCheckResult) []string {
+	codes := []string{}
+	for _, violation := range result.Violations {
+		codes = append(codes, violation.Code)
+	}
+
+	return codes
+}
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	// Without a minimum score, only the rules under test report violations
+	lenient := DefaultPasswordPolicyConfig()
+	lenient.MinScore = 0
+
+	tests := []struct {
+		name     string
+		config   func(config *PasswordPolicyConfig)
+		breached usecase.BreachedPasswordChecker
+		password string
+		want     []string
+	}{
+		{name: "strong password", password: strongPassword, want: []string{}},
+		{name: "too short", password: "xK9#mQ2", want: []string{PasswordViolationTooShort}},
+		{
+			name:     "too long",
+			config:   func(c *PasswordPolicyConfig) { c.MaxLength = 10 },
+			password: strongPassword,
+			want:     []string{PasswordViolationTooLong},
+		},
+		{
+			name:     "too many bytes",
+			config:   func(c *PasswordPolicyConfig) { c.MaxBytes = BcryptMaxPasswordBytes },
+			password: strings.Repeat("é", 40),
+			want:     []string{PasswordViolationTooLong},
+		},
+		{
+			name:     "bytes within the limit",
+			config:   func(c *PasswordPolicyConfig) { c.MaxBytes = BcryptMaxPasswordBytes },
+			password: strings.Repeat("é", 36),
+			want:     []string{},
+		},
+		{
+			name: "missing character classes",
+			config: func(c *PasswordPolicyConfig) {
+				c.RequireUppercase, c.RequireLowercase, c.RequireDigit, c.RequireSymbol = true, true, true, true
+			},
+			password: "qmvlzrtpwx",
+			want:     []string{PasswordViolationMissingUppercase, PasswordViolationMissingDigit, PasswordViolationMissingSymbol},
+		},
+		{
+			name: "every character class",
+			config: func(c *PasswordPolicyConfig) {
+				c.RequireUppercase, c.RequireLowercase, c.RequireDigit, c.RequireSymbol = true, true, true, true
+			},
+			password: strongPassword,
+			want:     []string{},
+		},
+		{name: "contains name", password: "xK9#Jane$vL7@", want: []string{PasswordViolationContainsUserInput}},
+		{name: "contains email", password: "xK9#janedoe$vL7@", want: []string{PasswordViolationContainsUserInput}},
+		{name: "contains substituted name", password: "xK9#j4ne$vL7@", want: []string{PasswordViolationContainsUserInput}},
+		{
+			name:     "user inputs allowed",
+			config:   func(c *PasswordPolicyConfig) { c.BlockUserInputs = false },
+			password: "xK9#Jane$vL7@",
+			want:     []string{},
+		},
+		{
+			name:     "breached",
+			breached: stubBreachedPasswords{passwords: []string{strongPassword}},
+			password: strongPassword,
+			want:     []string{PasswordViolationBreached},
+		},
+		{
+			name:     "breached list unavailable",
+			breached: stubBreachedPasswords{err: errors.New("disk error")},
+			password: strongPassword,
+			want:     []string{},
+		},
+		{
+			name:     "too weak",
+			config:   func(c *PasswordPolicyConfig) { c.MinScore = 2 },
+			password: "password",
+			want:     []string{PasswordViolationTooWeak},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := lenient
+			if tt.config != nil {
+				tt.config(&config)
+			}
+
+			policy := NewPasswordPolicy(config, tt.breached, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			result := policy.Check(tt.password, "Jane Doe", "jane.doe@example.com")
+
+			if got := violationCodes(result); !slices.Equal(got, tt.want) {
+				t.Errorf("Check(%q) violations = %v, want %v", tt.password, got, tt.want)
+			}
+			if result.Valid != (len(tt.want) == 0) {
+				t.Errorf("Check(%q) valid = %v, want %v", tt.password, result.Valid, len(tt.want) == 0)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyScore(t *testing.T) {
+	tests := []struct {
+		password string
+		want     int
+	}{
+		{password: "password", want: 0},
+		{password: "P@ssw0rd", want: 0},
+		{password: "12345678", want: 0},
+		{password: "aaaaaaaaaa", want: 0},
+		{password: "abcdefgh", want: 0},
+		{password: "qwertyuiop", want: 0},
+		{password: "zxcvbnm123", want: 1},
+		{password: "correct horse battery staple", want: 4},
+		{password: strongPassword, want: 4},
+	}
+
+	policy := NewPasswordPolicy(DefaultPasswordPolicyConfig(), nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			if got := policy.Check(tt.password).Score; got != tt.want {
+				t.Errorf("Check(%q) score = %d, want %d", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindPasswordMatches(t *testing.T) {
+	tests := []struct {
+		password string
+		pattern  string
+		leet     bool
+	}{
+		{password: "xxpasswordxx", pattern: passwordPatternDictionary},
+		{password: "xxp@ssw0rdxx", pattern: passwordPatternDictionary, leet: true},
+		{password: "xxjanexx", pattern: passwordPatternUserInput},
+		{password: "xxaaaaxx", pattern: passwordPatternRepeat},
+		{password: "xxabcdxx", pattern: passwordPatternSequence},
+		{password: "xx9753xx", pattern: passwordPatternSequence},
+		{password: "xxasdfgxx", pattern: passwordPatternKeyboard},
+		{password: "xx1987xx", pattern: passwordPatternYear},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			matches := findPasswordMatches(tt.password, []string{"jane"})
+			if !slices.ContainsFunc(matches, func(m passwordMatch) bool {
+				return m.pattern == tt.pattern && m.leet == tt.leet
+			}) {
+				t.Errorf("findPasswordMatches(%q) = %+v, want a %s match (leet %v)", tt.password, matches, tt.pattern, tt.leet)
+			}
+		})
+	}
+}
+
+func TestUserInputTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []string
+		want   []string
+	}{
+		{name: "name", inputs: []string{"Jane Doe"}, want: []string{"doe", "jane", "janedoe"}},
+		{name: "email local part only", inputs: []string{"jane.doe@example.com"}, want: []string{"doe", "jane", "janedoe"}},
+		{name: "short words skipped", inputs: []string{"Al Li"}, want: []string{"alli"}},
+		{name: "duplicates removed", inputs: []string{"Jane", "jane@example.com"}, want: []string{"jane"}},
+		{name: "empty", inputs: []string{"", "  "}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userInputTokens(tt.inputs); !slices.Equal(got, tt.want) {
+				t.Errorf("userInputTokens(%q) = %q, want %q", tt.inputs, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"auth/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// phoneCodeMessage returns the text of a code sent to a phone number for the purpose
+func phoneCodeMessage(purpose string, code string) string {
+	switch purpose {
+	case domain.PhoneCodePurposeLogin:
+		return fmt.Sprintf("Your login code is %s. It expires in 5 minutes. Never share it with anyone.", code)
+	case domain.PhoneCodePurposeMFA:
+		return fmt.Sprintf("Your verification code is %s. It expires in 10 minutes. Never share it with anyone.", code)
+	case domain.PhoneCodePurposeVerification:
+		return fmt.Sprintf("Your phone number confirmation code is %s. It expires in 10 minutes.", code)
+	}
+
+	return fmt.Sprintf("Your code is %s.", code)
+}
+
+// spokenCode spaces the digits of the code out so that text to speech reads them one by one
+func spokenCode(code string) string {
+	return strings.Join(strings.Split(code, ""), ", ")
+}
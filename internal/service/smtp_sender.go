@@ -2,6 +2,7 @@ package service
 
 import (
 	"auth/internal"
+	"auth/internal/domain"
 	"bytes"
 	"context"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"mime/multipart"
 	"net/smtp"
 	"net/textproto"
+	"time"
 )
 
 // SMTPEmailSender Real implementation of EmailSender using an SMTP server
@@ -79,12 +81,96 @@ func (sender *SMTPEmailSender) SendEmailVerificationCode(ctx context.Context, em
 // SendEmailLoginOTP connects to the SMTP server and sends the email
 func (sender *SMTPEmailSender) SendEmailLoginOTP(ctx context.Context, email string, code string) error {
 	data := map[string]string{
-		"LoginCode": code,
+		"Code": code,
 	}
 
 	return sender.sendEmail(ctx, email, "login_otp_template", data)
 }
 
+// SendEmailPasswordChanged connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailPasswordChanged(ctx context.Context, email string, changedAt time.Time) error {
+	data := map[string]string{
+		"ChangedAt": changedAt.UTC().Format("January 2, 2006 at 15:04 UTC"),
+	}
+
+	return sender.sendEmail(ctx, email, "password_changed_template", data)
+}
+
+// SendEmailChangeConfirmation connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailChangeConfirmation(ctx context.Context, newEmail string, token string) error {
+	data := map[string]string{
+		"ConfirmLink": fmt.Sprintf("%s/api/v1/auth/email/confirm?token=%s", sender.BaseURL, token),
+	}
+
+	return sender.sendEmail(ctx, newEmail, "email_change_confirmation_template", data)
+}
+
+// SendEmailChangeNotice connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailChangeNotice(ctx context.Context, oldEmail string, newEmail string, revertToken string) error {
+	data := map[string]string{
+		"NewEmail":   newEmail,
+		"RevertLink": fmt.Sprintf("%s/api/v1/auth/email/revert?token=%s", sender.BaseURL, revertToken),
+	}
+
+	return sender.sendEmail(ctx, oldEmail, "email_change_notice_template", data)
+}
+
+// SendEmailAccountDeletionScheduled connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailAccountDeletionScheduled(ctx context.Context, email string, purgeAt time.Time) error {
+	data := map[string]string{
+		"PurgeAt": purgeAt.UTC().Format("January 2, 2006 at 15:04 UTC"),
+	}
+
+	return sender.sendEmail(ctx, email, "account_deletion_scheduled_template", data)
+}
+
+// SendEmailAccountRestored connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailAccountRestored(ctx context.Context, email string) error {
+	return sender.sendEmail(ctx, email, "account_restored_template", nil)
+}
+
+// SendEmailAccountDeleted connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailAccountDeleted(ctx context.Context, email string) error {
+	return sender.sendEmail(ctx, email, "account_deleted_template", nil)
+}
+
+// SendEmailDataExportReady connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailDataExportReady(ctx context.Context, email string, token string, expiresAt time.Time) error {
+	data := map[string]string{
+		"DownloadLink": fmt.Sprintf("%s/api/v1/users/exports/download?token=%s", sender.BaseURL, token),
+		"ExpiresAt":    expiresAt.UTC().Format("January 2, 2006 at 15:04 UTC"),
+	}
+
+	return sender.sendEmail(ctx, email, "data_export_ready_template", data)
+}
+
+// SendEmailNewDeviceLogin connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailNewDeviceLogin(ctx context.Context, email string, login domain.DeviceLogin, secureToken string) error {
+	location := login.Location
+	if location == "" {
+		location = "Unknown"
+	}
+
+	data := map[string]string{
+		"Device":     login.Device,
+		"IPAddress":  login.IPAddress,
+		"Location":   location,
+		"LoginAt":    login.LoginAt.UTC().Format("January 2, 2006 at 15:04 UTC"),
+		"SecureLink": fmt.Sprintf("%s/api/v1/auth/secure-account?token=%s", sender.BaseURL, secureToken),
+	}
+
+	return sender.sendEmail(ctx, email, "new_device_login_template", data)
+}
+
+// SendEmailMFACode connects to the SMTP server and sends the email
+func (sender *SMTPEmailSender) SendEmailMFACode(ctx context.Context, email string, code string) error {
+	data := map[string]string{
+		"Code": code,
+	}
+
+	return sender.sendEmail(ctx, email, "mfa_code_template", data)
+}
+
 // sendEmail is a helper function to construct and send email
 func (sender *SMTPEmailSender) sendEmail(ctx context.Context, email string, templateName string, data any) error {
 	//body.WriteString(fromHeader)
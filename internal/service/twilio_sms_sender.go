@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTwilioBaseURL is the API used when TwilioConfig has no base URL
+const DefaultTwilioBaseURL = "https://api.twilio.com"
+
+// TwilioConfig holds the configuration of the Twilio sender.
+// BaseURL can point to any provider compatible with the Twilio Messages and Calls APIs.
+type TwilioConfig struct {
+	BaseURL    string
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+// TwilioSMSSender implementation of SMSSender using the Twilio REST API
+type TwilioSMSSender struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// NewTwilioSMSSender creates a new Twilio SMS sender
+func NewTwilioSMSSender(config TwilioConfig) *TwilioSMSSender {
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultTwilioBaseURL
+	}
+
+	return &TwilioSMSSender{
+		baseURL:    baseURL,
+		accountSID: config.AccountSID,
+		authToken:  config.AuthToken,
+		from:       config.From,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendSMSCode texts the code to the phone number
+func (sender *TwilioSMSSender) SendSMSCode(ctx context.Context, phoneNumber string, purpose string, code string) error {
+	form := url.Values{
+		"To":   {phoneNumber},
+		"From": {sender.from},
+		"Body": {phoneCodeMessage(purpose, code)},
+	}
+
+	return sender.post(ctx, "Messages.json", form)
+}
+
+// SendVoiceCode calls the phone number and reads the code out twice
+func (sender *TwilioSMSSender) SendVoiceCode(ctx context.Context, phoneNumber string, purpose string, code string) error {
+	var message bytes.Buffer
+	spoken := phoneCodeMessage(purpose, spokenCode(code))
+	if err := xml.EscapeText(&message, []byte(spoken)); err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"To":    {phoneNumber},
+		"From":  {sender.from},
+		"Twiml": {fmt.Sprintf("<Response><Say>%s</Say><Pause length=\"1\"/><Say>%s</Say></Response>", message.String(), message.String())},
+	}
+
+	return sender.post(ctx, "Calls.json", form)
+}
+
+// post sends the form to a resource of the account
+func (sender *TwilioSMSSender) post(ctx context.Context, resource string, form url.Values) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/%s", sender.baseURL, url.PathEscape(sender.accountSID), resource)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(sender.accountSID, sender.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := sender.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach sms provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	// Errors are reported as {"code": 21211, "message": "..."}
+	var apiError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := json.Unmarshal(body, &apiError); err == nil && apiError.Message != "" {
+		return fmt.Errorf("sms provider returned %d: %s (code %d)", resp.StatusCode, apiError.Message, apiError.Code)
+	}
+
+	return fmt.Errorf("sms provider returned %d", resp.StatusCode)
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// AddServiceAccountKeyUseCase represents the add service account key use case object
+type AddServiceAccountKeyUseCase struct {
+	serviceAccountRepository ServiceAccountRepository
+}
+
+// NewAddServiceAccountKeyUseCase creates a new AddServiceAccountKeyUseCase object
+func NewAddServiceAccountKeyUseCase(serviceAccountRepository ServiceAccountRepository) *AddServiceAccountKeyUseCase {
+	return &AddServiceAccountKeyUseCase{serviceAccountRepository: serviceAccountRepository}
+}
+
+// Execute registers a public key the service account can sign client assertions with.
+// When no key ID is given, one is derived from the key itself.
+func (uc *AddServiceAccountKeyUseCase) Execute(
+	ctx context.Context,
+	ownerUserID int64,
+	serviceAccountID int64,
+	keyID string,
+	publicKeyPEM string,
+) (*domain.ServiceAccountKey, error) {
+	publicKeyPEM = strings.TrimSpace(publicKeyPEM)
+	if _, err := parsePublicKeyPEM(publicKeyPEM); err != nil {
+		return nil, err
+	}
+
+	if _, err := findOwnedServiceAccount(ctx, uc.serviceAccountRepository, ownerUserID, serviceAccountID); err != nil {
+		return nil, err
+	}
+
+	keyID = strings.TrimSpace(keyID)
+	if keyID == "" {
+		sum := sha256.Sum256([]byte(publicKeyPEM))
+		keyID = hex.EncodeToString(sum[:8])
+	}
+
+	key := &domain.ServiceAccountKey{
+		ServiceAccountID: serviceAccountID,
+		KeyID:            keyID,
+		PublicKeyPEM:     publicKeyPEM,
+	}
+
+	if err := uc.serviceAccountRepository.SaveKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
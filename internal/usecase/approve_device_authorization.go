@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ApproveDeviceAuthorizationUseCase represents the use case for approving or denying a device authorization
+type ApproveDeviceAuthorizationUseCase struct {
+	deviceAuthorizationRepository DeviceAuthorizationRepository
+	getDeviceAuthorizationUseCase *GetDeviceAuthorizationUseCase
+}
+
+// NewApproveDeviceAuthorizationUseCase creates a new ApproveDeviceAuthorizationUseCase object
+func NewApproveDeviceAuthorizationUseCase(
+	deviceAuthorizationRepository DeviceAuthorizationRepository,
+	getDeviceAuthorizationUseCase *GetDeviceAuthorizationUseCase,
+) *ApproveDeviceAuthorizationUseCase {
+	return &ApproveDeviceAuthorizationUseCase{
+		deviceAuthorizationRepository: deviceAuthorizationRepository,
+		getDeviceAuthorizationUseCase: getDeviceAuthorizationUseCase,
+	}
+}
+
+// Execute records the decision of the user. Once approved, the next poll of the device receives tokens for the user.
+func (uc *ApproveDeviceAuthorizationUseCase) Execute(ctx context.Context, userID int64, userCode string, approve bool) (*domain.DeviceAuthorization, error) {
+	authorization, err := uc.getDeviceAuthorizationUseCase.Execute(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	status := domain.DeviceAuthorizationDenied
+	if approve {
+		status = domain.DeviceAuthorizationApproved
+	}
+
+	if err := uc.deviceAuthorizationRepository.Decide(ctx, authorization.ID, userID, status); err != nil {
+		// Another request decided or the code expired in the meantime
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidUserCode
+		}
+
+		return nil, err
+	}
+
+	authorization.Status = status
+	authorization.UserID = &userID
+
+	return authorization, nil
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Points added to the risk score of a login for each reason
+const (
+	knownBadIPRiskScore       = 100
+	impossibleTravelRiskScore = 60
+	newCountryRiskScore       = 30
+	velocityRiskScore         = 40
+)
+
+// Logins closer than this to the previous one are never impossible travel, IP geolocation is approximate
+const minImpossibleTravelDistanceKm = 100
+
+// minTravelTime keeps back-to-back logins from reaching absurd speeds
+const minTravelTime = time.Minute
+
+// LoginRiskPolicy maps the risk score of a login to a decision, zero thresholds disable the band
+type LoginRiskPolicy struct {
+	// MFAScore is the score from which the user must complete a second factor
+	MFAScore int
+	// BlockScore is the score from which the login is refused
+	BlockScore int
+	// MaxTravelSpeedKmh is the speed between two logins above which the travel is impossible
+	MaxTravelSpeedKmh float64
+	// VelocityLimit is the number of logins within VelocityWindow above which the account is under attack
+	VelocityLimit  int
+	VelocityWindow time.Duration
+}
+
+// DefaultLoginRiskPolicy returns the policy used when nothing is configured
+func DefaultLoginRiskPolicy() LoginRiskPolicy {
+	return LoginRiskPolicy{
+		MFAScore:          30,
+		BlockScore:        90,
+		MaxTravelSpeedKmh: 1000,
+		VelocityLimit:     10,
+		VelocityWindow:    time.Hour,
+	}
+}
+
+// Decide returns the decision for the risk score
+func (p LoginRiskPolicy) Decide(score int) string {
+	if p.BlockScore > 0 && score >= p.BlockScore {
+		return domain.RiskDecisionBlock
+	}
+	if p.MFAScore > 0 && score >= p.MFAScore {
+		return domain.RiskDecisionRequireMFA
+	}
+
+	return domain.RiskDecisionAllow
+}
+
+// AssessLoginRiskUseCase represents the use case for scoring logins with correct credentials.
+// Lookups only use local data: the GeoIP database, the IP blocklist and the login history.
+type AssessLoginRiskUseCase struct {
+	loginEventRepository LoginEventRepository
+	ipLocator            IPLocator
+	ipBlocklist          IPBlocklist
+	startMFAUseCase      *StartMFAChallengeUseCase
+	trustDeviceUseCase   *TrustDeviceUseCase
+	policy               LoginRiskPolicy
+}
+
+// NewAssessLoginRiskUseCase creates a new AssessLoginRiskUseCase object. The IP locator and blocklist are optional.
+func NewAssessLoginRiskUseCase(
+	loginEventRepository LoginEventRepository,
+	ipLocator IPLocator,
+	ipBlocklist IPBlocklist,
+	startMFAUseCase *StartMFAChallengeUseCase,
+	trustDeviceUseCase *TrustDeviceUseCase,
+	policy LoginRiskPolicy,
+) *AssessLoginRiskUseCase {
+	return &AssessLoginRiskUseCase{
+		loginEventRepository: loginEventRepository,
+		ipLocator:            ipLocator,
+		ipBlocklist:          ipBlocklist,
+		startMFAUseCase:      startMFAUseCase,
+		trustDeviceUseCase:   trustDeviceUseCase,
+		policy:               policy,
+	}
+}
+
+// Execute scores the login of the client in the context and applies the decision of the policy.
+// Users with MFA enabled always need a second factor, trusted devices skip it unless the login is blocked.
+// It returns nil when the login can go on, ErrLoginBlocked when it is refused,
+// or an MFARequiredError once a second factor has been sent to the user.
+// rememberMe and useSession are applied when the second factor is completed.
+func (uc *AssessLoginRiskUseCase) Execute(ctx context.Context, user *domain.User, rememberMe bool, useSession bool) error {
+	// Logins outside an HTTP request have no client to score, MFA is still enforced
+	info, ok := ClientInfoFromContext(ctx)
+	if !ok || info.IPAddress == "" {
+		if !user.MFAEnabled {
+			return nil
+		}
+
+		trusted, err := uc.trustDeviceUseCase.IsTrusted(ctx, user.ID)
+		if err != nil || trusted {
+			return err
+		}
+
+		return uc.startMFAUseCase.Execute(ctx, user, nil, rememberMe, useSession)
+	}
+
+	event, err := uc.assess(ctx, user.ID, info.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	if user.MFAEnabled && event.Decision == domain.RiskDecisionAllow {
+		event.Decision = domain.RiskDecisionRequireMFA
+	}
+
+	if event.Decision == domain.RiskDecisionRequireMFA {
+		trusted, err := uc.trustDeviceUseCase.IsTrusted(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if trusted {
+			event.Decision = domain.RiskDecisionAllow
+		}
+	}
+
+	event.Succeeded = event.Decision == domain.RiskDecisionAllow
+	if err := uc.loginEventRepository.Save(ctx, event); err != nil {
+		return err
+	}
+
+	switch event.Decision {
+	case domain.RiskDecisionBlock:
+		return ErrLoginBlocked
+	case domain.RiskDecisionRequireMFA:
+		return uc.startMFAUseCase.Execute(ctx, user, &event.ID, rememberMe, useSession)
+	}
+
+	return nil
+}
+
+// assess scores the login against the history of the user
+func (uc *AssessLoginRiskUseCase) assess(ctx context.Context, userID int64, ipAddress string) (*domain.LoginEvent, error) {
+	event := &domain.LoginEvent{
+		UserID:      userID,
+		IPAddress:   ipAddress,
+		RiskReasons: []string{},
+	}
+
+	if uc.ipBlocklist != nil && uc.ipBlocklist.Contains(ipAddress) {
+		event.RiskScore += knownBadIPRiskScore
+		event.RiskReasons = append(event.RiskReasons, domain.RiskReasonKnownBadIP)
+	}
+
+	if uc.ipLocator != nil {
+		// A lookup failure only leaves the location based checks out
+		if location, err := uc.ipLocator.Locate(ipAddress); err == nil && location != nil {
+			event.CountryCode = location.CountryCode
+			event.Latitude = &location.Latitude
+			event.Longitude = &location.Longitude
+		}
+	}
+
+	if event.IsLocated() {
+		impossible, err := uc.isImpossibleTravel(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		if impossible {
+			event.RiskScore += impossibleTravelRiskScore
+			event.RiskReasons = append(event.RiskReasons, domain.RiskReasonImpossibleTravel)
+		}
+	}
+
+	if event.CountryCode != "" {
+		countries, err := uc.loginEventRepository.FindSucceededCountries(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		// The first located login sets the home country
+		if len(countries) > 0 && !slices.Contains(countries, event.CountryCode) {
+			event.RiskScore += newCountryRiskScore
+			event.RiskReasons = append(event.RiskReasons, domain.RiskReasonNewCountry)
+		}
+	}
+
+	if uc.policy.VelocityLimit > 0 && uc.policy.VelocityWindow > 0 {
+		count, err := uc.loginEventRepository.CountSince(ctx, userID, time.Now().Add(-uc.policy.VelocityWindow))
+		if err != nil {
+			return nil, err
+		}
+		if count >= uc.policy.VelocityLimit {
+			event.RiskScore += velocityRiskScore
+			event.RiskReasons = append(event.RiskReasons, domain.RiskReasonVelocity)
+		}
+	}
+
+	event.Decision = uc.policy.Decide(event.RiskScore)
+	return event, nil
+}
+
+// isImpossibleTravel reports whether the user couldn't have travelled from their previous login to this one in time
+func (uc *AssessLoginRiskUseCase) isImpossibleTravel(ctx context.Context, event *domain.LoginEvent) (bool, error) {
+	if uc.policy.MaxTravelSpeedKmh <= 0 {
+		return false, nil
+	}
+
+	previous, err := uc.loginEventRepository.FindLastSucceeded(ctx, event.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+	if !previous.IsLocated() {
+		return false, nil
+	}
+
+	distance := domain.DistanceKm(*previous.Latitude, *previous.Longitude, *event.Latitude, *event.Longitude)
+	if distance < minImpossibleTravelDistanceKm {
+		return false, nil
+	}
+
+	elapsed := max(time.Since(previous.CreatedAt), minTravelTime)
+	return distance/elapsed.Hours() > uc.policy.MaxTravelSpeedKmh, nil
+}
@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// AuditEventRepository represents the audit event repository interface
+type AuditEventRepository interface {
+	Save(ctx context.Context, event *domain.AuditEvent) error
+}
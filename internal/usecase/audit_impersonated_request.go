@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ImpersonatedRequest describes a request made with an impersonation token
+type ImpersonatedRequest struct {
+	Method    string
+	Path      string
+	IPAddress string
+}
+
+// AuditImpersonatedRequestUseCase represents the use case for checking and recording each request made while impersonating
+type AuditImpersonatedRequestUseCase struct {
+	userRepository          UserRepository
+	impersonationRepository ImpersonationRepository
+	auditEventRepository    AuditEventRepository
+}
+
+// NewAuditImpersonatedRequestUseCase creates a new AuditImpersonatedRequestUseCase object
+func NewAuditImpersonatedRequestUseCase(
+	userRepository UserRepository,
+	impersonationRepository ImpersonationRepository,
+	auditEventRepository AuditEventRepository,
+) *AuditImpersonatedRequestUseCase {
+	return &AuditImpersonatedRequestUseCase{
+		userRepository:          userRepository,
+		impersonationRepository: impersonationRepository,
+		auditEventRepository:    auditEventRepository,
+	}
+}
+
+// Execute rejects the request with ErrInvalidToken when the impersonation has ended or the acting
+// administrator lost their role, then records the request in the audit log of the impersonated user
+func (uc *AuditImpersonatedRequestUseCase) Execute(ctx context.Context, principal *domain.Principal, req ImpersonatedRequest) error {
+	impersonation, err := uc.impersonationRepository.FindByID(ctx, principal.ImpersonationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	if !impersonation.IsActive() || impersonation.UserID != principal.ID || impersonation.AdminID != principal.ActorID {
+		return ErrInvalidToken
+	}
+
+	admin, err := uc.userRepository.FindByID(ctx, principal.ActorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	if !admin.HasRole(domain.RoleAdmin) || admin.IsBlocked() || admin.IsDeleted() {
+		return ErrInvalidToken
+	}
+
+	return uc.auditEventRepository.Save(ctx, &domain.AuditEvent{
+		UserID:  principal.ID,
+		ActorID: &principal.ActorID,
+		Action:  domain.AuditActionImpersonationRequest,
+		Details: map[string]any{
+			"impersonation_id": impersonation.ID,
+			"method":           req.Method,
+			"path":             req.Path,
+		},
+		IPAddress: req.IPAddress,
+	})
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"strings"
+	"time"
+)
+
+// AuthenticateAccessTokenUseCase represents the use case for resolving the principal of a JWT access token
+type AuthenticateAccessTokenUseCase struct {
+	tokenParser TokenParser
+}
+
+// NewAuthenticateAccessTokenUseCase creates a new AuthenticateAccessTokenUseCase object
+func NewAuthenticateAccessTokenUseCase(tokenParser TokenParser) *AuthenticateAccessTokenUseCase {
+	return &AuthenticateAccessTokenUseCase{tokenParser: tokenParser}
+}
+
+// Execute validates the access token and returns the user or service principal it was issued to
+func (uc *AuthenticateAccessTokenUseCase) Execute(token string) (*domain.Principal, error) {
+	claims, err := uc.tokenParser.ParseToken(token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// Only tokens issued by a login or a refresh grant access to the API
+	purpose, _ := claims["purpose"].(string)
+	if purpose != "access_token" && purpose != "refresh_token" {
+		return nil, ErrInvalidToken
+	}
+
+	// Tokens exchanged for an audience are meant for those services only
+	if _, ok := claims["aud"]; ok {
+		return nil, ErrInvalidToken
+	}
+
+	principal := &domain.Principal{Type: domain.PrincipalTypeUser}
+	principal.ClientID, _ = claims["client_id"].(string)
+
+	if exp, ok := claims["exp"].(float64); ok {
+		principal.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		principal.Scopes = strings.Fields(scope)
+	}
+
+	if claims["principal_type"] == domain.PrincipalTypeService {
+		// JWT stores numbers as float64
+		serviceAccountID, ok := claims["service_account_id"].(float64)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		principal.Type = domain.PrincipalTypeService
+		principal.ID = int64(serviceAccountID)
+
+		return principal, nil
+	}
+
+	// The 'sub' in claim hold our user ID. JWT stores numbers as float64
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	principal.ID = int64(userID)
+
+	// Only tokens issued by a login record when and how the user authenticated
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		principal.AuthTime = time.Unix(int64(authTime), 0)
+		principal.AMR = claimStrings(claims["amr"])
+	}
+
+	// Impersonation tokens name the acting administrator in the act claim (RFC 8693)
+	if impersonationID, ok := claims["impersonation_id"].(float64); ok {
+		act, _ := claims["act"].(map[string]any)
+		actorID, ok := act["sub"].(float64)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		principal.ActorID = int64(actorID)
+		principal.ImpersonationID = int64(impersonationID)
+	}
+
+	// Interactive sessions are not restricted to specific scopes
+	if principal.Scopes == nil {
+		principal.Scopes = []string{domain.ScopeAll}
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ClientAssertionTypeJWTBearer is the client assertion type for private key JWT client authentication (RFC 7523)
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// maxClientAssertionLifetime limits how far in the future a client assertion may expire
+const maxClientAssertionLifetime = 10 * time.Minute
+
+// ClientCredentials holds the credentials a client presents to the OAuth endpoints
+type ClientCredentials struct {
+	ClientID            string
+	ClientSecret        string
+	ClientAssertionType string
+	ClientAssertion     string
+}
+
+// AuthenticateClientUseCase represents the use case for authenticating a service account as an OAuth client
+type AuthenticateClientUseCase struct {
+	serviceAccountRepository         ServiceAccountRepository
+	checkServiceAccountStatusUseCase *CheckServiceAccountStatusUseCase
+	audiences                        []string
+}
+
+// NewAuthenticateClientUseCase creates a new AuthenticateClientUseCase object.
+// Client assertions must be addressed to one of the audiences, usually the issuer and the token endpoint URL.
+func NewAuthenticateClientUseCase(
+	serviceAccountRepository ServiceAccountRepository,
+	checkServiceAccountStatusUseCase *CheckServiceAccountStatusUseCase,
+	audiences []string,
+) *AuthenticateClientUseCase {
+	return &AuthenticateClientUseCase{
+		serviceAccountRepository:         serviceAccountRepository,
+		checkServiceAccountStatusUseCase: checkServiceAccountStatusUseCase,
+		audiences:                        audiences,
+	}
+}
+
+// Execute authenticates the client with either a client secret or a private key JWT assertion
+func (uc *AuthenticateClientUseCase) Execute(ctx context.Context, credentials ClientCredentials) (*domain.ServiceAccount, error) {
+	if credentials.ClientAssertion != "" {
+		return uc.authenticateAssertion(ctx, credentials)
+	}
+
+	if credentials.ClientID == "" || credentials.ClientSecret == "" {
+		return nil, ErrInvalidClient
+	}
+
+	account, err := uc.findAccount(ctx, credentials.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := uc.serviceAccountRepository.IsSecretValid(ctx, account.ID, uc.serviceAccountRepository.Hash(credentials.ClientSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		return nil, ErrInvalidClient
+	}
+
+	return account, nil
+}
+
+// authenticateAssertion verifies a JWT signed with one of the registered keys of the service account
+func (uc *AuthenticateClientUseCase) authenticateAssertion(ctx context.Context, credentials ClientCredentials) (*domain.ServiceAccount, error) {
+	if credentials.ClientAssertionType != ClientAssertionTypeJWTBearer {
+		return nil, ErrInvalidClient
+	}
+
+	// The issuer tells us which keys to verify the signature with
+	unverified, _, err := jwt.NewParser().ParseUnverified(credentials.ClientAssertion, jwt.MapClaims{})
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	clientID, err := unverified.Claims.GetIssuer()
+	if err != nil || clientID == "" || (credentials.ClientID != "" && credentials.ClientID != clientID) {
+		return nil, ErrInvalidClient
+	}
+
+	account, err := uc.findAccount(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := uc.serviceAccountRepository.FindKeys(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, _ := unverified.Header["kid"].(string)
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(clientID),
+		jwt.WithSubject(clientID),
+	)
+
+	for _, key := range keys {
+		if keyID != "" && key.KeyID != keyID {
+			continue
+		}
+
+		publicKey, err := parsePublicKeyPEM(key.PublicKeyPEM)
+		if err != nil {
+			continue
+		}
+
+		claims := jwt.MapClaims{}
+		_, err = parser.ParseWithClaims(credentials.ClientAssertion, claims, func(_ *jwt.Token) (interface{}, error) {
+			return publicKey, nil
+		})
+		if err != nil {
+			continue
+		}
+
+		if err := uc.checkAssertionClaims(ctx, account.ID, claims); err != nil {
+			return nil, err
+		}
+
+		return account, nil
+	}
+
+	return nil, ErrInvalidClient
+}
+
+// checkAssertionClaims validates the audience and lifetime of a verified assertion and prevents its replay
+func (uc *AuthenticateClientUseCase) checkAssertionClaims(ctx context.Context, serviceAccountID int64, claims jwt.MapClaims) error {
+	audiences, err := claims.GetAudience()
+	if err != nil || !slices.ContainsFunc(audiences, func(audience string) bool { return slices.Contains(uc.audiences, audience) }) {
+		return ErrInvalidClient
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt.After(time.Now().Add(maxClientAssertionLifetime)) {
+		return ErrInvalidClient
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return ErrInvalidClient
+	}
+
+	fresh, err := uc.serviceAccountRepository.MarkAssertionUsed(ctx, serviceAccountID, jti, expiresAt.Time)
+	if err != nil {
+		return err
+	}
+
+	if !fresh {
+		return ErrInvalidClient
+	}
+
+	return nil
+}
+
+// findAccount finds the service account by client ID, rejecting it when its owner is no longer active
+func (uc *AuthenticateClientUseCase) findAccount(ctx context.Context, clientID string) (*domain.ServiceAccount, error) {
+	account, err := uc.serviceAccountRepository.FindByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidClient
+		}
+
+		return nil, err
+	}
+
+	if err := uc.checkServiceAccountStatusUseCase.CheckOwner(ctx, account); err != nil {
+		if errors.Is(err, ErrAccountUnavailable) {
+			return nil, ErrInvalidClient
+		}
+
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// parsePublicKeyPEM parses a PEM encoded RSA, ECDSA or Ed25519 public key
+func parsePublicKeyPEM(publicKeyPEM string) (crypto.PublicKey, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(publicKeyPEM)); err == nil {
+		return key, nil
+	}
+
+	if key, err := jwt.ParseECPublicKeyFromPEM([]byte(publicKeyPEM)); err == nil {
+		return key, nil
+	}
+
+	if key, err := jwt.ParseEdPublicKeyFromPEM([]byte(publicKeyPEM)); err == nil {
+		return key, nil
+	}
+
+	return nil, ErrInvalidPublicKey
+}
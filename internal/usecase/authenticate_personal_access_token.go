@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// AuthenticatePersonalAccessTokenUseCase represents the use case for authenticating a request with a personal access token
+type AuthenticatePersonalAccessTokenUseCase struct {
+	tokenRepository PersonalAccessTokenRepository
+}
+
+// NewAuthenticatePersonalAccessTokenUseCase creates a new AuthenticatePersonalAccessTokenUseCase object
+func NewAuthenticatePersonalAccessTokenUseCase(tokenRepository PersonalAccessTokenRepository) *AuthenticatePersonalAccessTokenUseCase {
+	return &AuthenticatePersonalAccessTokenUseCase{tokenRepository: tokenRepository}
+}
+
+// Execute finds the unexpired token matching the raw token and records its usage
+func (uc *AuthenticatePersonalAccessTokenUseCase) Execute(ctx context.Context, rawToken string, ip string) (*domain.PersonalAccessToken, error) {
+	if !strings.HasPrefix(rawToken, domain.PersonalAccessTokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := uc.tokenRepository.FindByToken(ctx, uc.tokenRepository.Hash(rawToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	if err := uc.tokenRepository.UpdateLastUsed(ctx, token.ID, ip); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
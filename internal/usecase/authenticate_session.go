@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// sessionTouchInterval limits how often the last seen time of a session is written
+const sessionTouchInterval = time.Minute
+
+// sessionRotationGracePeriod is how long the token replaced by a rotation keeps working,
+// so requests sent concurrently with the one that rotated it don't fail
+const sessionRotationGracePeriod = 30 * time.Second
+
+// SessionAuthentication represents the session of a request.
+// RotatedToken is set when the session token was replaced and the cookie must be updated.
+type SessionAuthentication struct {
+	Session      *domain.Session
+	RotatedToken string
+}
+
+// AuthenticateSessionUseCase represents the use case for authenticating a request with a session cookie
+type AuthenticateSessionUseCase struct {
+	sessionRepository SessionRepository
+	userRepository    UserRepository
+	policy            SessionPolicy
+	rotationPolicy    PasswordRotationPolicy
+}
+
+// NewAuthenticateSessionUseCase creates a new AuthenticateSessionUseCase object
+func NewAuthenticateSessionUseCase(
+	sessionRepository SessionRepository,
+	userRepository UserRepository,
+	policy SessionPolicy,
+	rotationPolicy PasswordRotationPolicy,
+) *AuthenticateSessionUseCase {
+	return &AuthenticateSessionUseCase{
+		sessionRepository: sessionRepository,
+		userRepository:    userRepository,
+		policy:            policy,
+		rotationPolicy:    rotationPolicy,
+	}
+}
+
+// Execute validates the session token against the idle and absolute timeouts and the password expiry.
+// When the roles of the user changed since the token was issued, the token is rotated
+// so a token captured before a privilege change doesn't carry the new privileges.
+func (uc *AuthenticateSessionUseCase) Execute(ctx context.Context, rawToken string) (*SessionAuthentication, error) {
+	if rawToken == "" {
+		return nil, ErrInvalidToken
+	}
+
+	tokenHash := uc.sessionRepository.Hash(rawToken)
+	session, err := uc.sessionRepository.FindByToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	previousToken := session.TokenHash != tokenHash
+	if previousToken && (session.RotatedAt == nil || time.Since(*session.RotatedAt) > sessionRotationGracePeriod) {
+		return nil, ErrSessionRotated
+	}
+
+	if session.IsIdle(uc.policy.IdleTimeout) {
+		if err := uc.sessionRepository.Delete(ctx, session.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrInvalidToken
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	// The next login asks for a new password
+	if uc.rotationPolicy.IsExpired(user) {
+		if err := uc.sessionRepository.Delete(ctx, session.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrInvalidToken
+	}
+
+	result := &SessionAuthentication{Session: session}
+
+	// Only the current token is rotated, the client already received the one replacing the previous token
+	if !previousToken && !session.HasRoles(user.Roles) {
+		newToken, err := uc.sessionRepository.Generate()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := uc.sessionRepository.Rotate(ctx, session.ID, uc.sessionRepository.Hash(newToken), user.Roles); err != nil {
+			return nil, err
+		}
+
+		session.Roles = user.Roles
+		result.RotatedToken = newToken
+
+		return result, nil
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		if err := uc.sessionRepository.Touch(ctx, session.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,7 @@
+package usecase
+
+// BreachedPasswordChecker represents a list of passwords known from data breaches
+type BreachedPasswordChecker interface {
+	// IsBreached reports whether the password appears in the list
+	IsBreached(password string) (bool, error)
+}
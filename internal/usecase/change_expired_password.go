@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ChangeExpiredPasswordUseCase represents the use case for replacing an expired password with the restricted token issued at login
+type ChangeExpiredPasswordUseCase struct {
+	userRepository        UserRepository
+	tokenParser           TokenParser
+	changePasswordUseCase *ChangePasswordUseCase
+	loginUseCase          *LoginUserUseCase
+}
+
+// NewChangeExpiredPasswordUseCase creates a new ChangeExpiredPasswordUseCase object
+func NewChangeExpiredPasswordUseCase(
+	userRepository UserRepository,
+	tokenParser TokenParser,
+	changePasswordUseCase *ChangePasswordUseCase,
+	loginUseCase *LoginUserUseCase,
+) *ChangeExpiredPasswordUseCase {
+	return &ChangeExpiredPasswordUseCase{
+		userRepository:        userRepository,
+		tokenParser:           tokenParser,
+		changePasswordUseCase: changePasswordUseCase,
+		loginUseCase:          loginUseCase,
+	}
+}
+
+// Execute validates the restricted token, changes the password and logs the user in
+func (uc *ChangeExpiredPasswordUseCase) Execute(ctx context.Context, passwordChangeToken string, newPassword string) (*LoginToken, error) {
+	claims, err := uc.tokenParser.ParseToken(passwordChangeToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != PasswordChangeTokenPurpose {
+		return nil, ErrInvalidToken
+	}
+
+	// JWT stores numbers as float64
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	changedAt, ok := claims["password_changed_at"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	// The token is spent once the password has changed
+	if user.PasswordChangedAt.Unix() != int64(changedAt) {
+		return nil, ErrInvalidToken
+	}
+
+	if err := uc.changePasswordUseCase.Execute(ctx, user.ID, newPassword); err != nil {
+		return nil, err
+	}
+
+	return uc.loginUseCase.GenerateToken(ctx, user.ID, false, []string{domain.AMRPassword})
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// PasswordChangeTokenPurpose is the purpose of the restricted token issued when a password has expired.
+// It is only accepted by the change expired password use case.
+const PasswordChangeTokenPurpose = "password_change"
+
+// passwordChangeTokenDuration is how long the user has to pick a new password after logging in
+const passwordChangeTokenDuration = 10 * time.Minute
+
+// MaxPasswordHistory is the largest History the stored password history supports
+const MaxPasswordHistory = 25
+
+// PasswordRotationPolicy controls password reuse and expiry, zero values disable them
+type PasswordRotationPolicy struct {
+	// History is the number of most recent passwords, the current one included, that can't be reused, at most MaxPasswordHistory
+	History int
+	// MaxAge is how long a password can be used before it must be changed
+	MaxAge time.Duration
+}
+
+// IsExpired reports whether the user's password is older than MaxAge.
+// Passwords owned by an external directory never expire here.
+func (p PasswordRotationPolicy) IsExpired(user *domain.User) bool {
+	if p.MaxAge <= 0 || user.AuthSource != domain.AuthSourceLocal {
+		return false
+	}
+
+	return time.Since(user.PasswordChangedAt) > p.MaxAge
+}
+
+// PasswordChangeRequiredError is returned by logins with a correct but expired password.
+// It matches ErrPasswordChangeRequired with errors.Is and carries the restricted token to change it with.
+type PasswordChangeRequiredError struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (e *PasswordChangeRequiredError) Error() string {
+	return ErrPasswordChangeRequired.Error()
+}
+
+// Is reports whether the target is ErrPasswordChangeRequired
+func (e *PasswordChangeRequiredError) Is(target error) bool {
+	return target == ErrPasswordChangeRequired
+}
+
+// requirePasswordChange issues a restricted token when the user's password has expired, nil otherwise.
+// The token is bound to the current password change time, so it can only be used once.
+func requirePasswordChange(tokenGenerator TokenGenerator, policy PasswordRotationPolicy, user *domain.User) error {
+	if !policy.IsExpired(user) {
+		return nil
+	}
+
+	token, err := tokenGenerator.GenerateTokenWithClaims(user.ID, PasswordChangeTokenPurpose, passwordChangeTokenDuration, map[string]any{
+		"password_changed_at": user.PasswordChangedAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return &PasswordChangeRequiredError{Token: token, ExpiresAt: time.Now().Add(passwordChangeTokenDuration)}
+}
+
+// ChangePasswordUseCase represents the change password use case object.
+// It is the single place new passwords are validated and stored for an existing user.
+type ChangePasswordUseCase struct {
+	userRepository UserRepository
+	passwordHasher PasswordHasher
+	passwordPolicy PasswordPolicy
+	rotationPolicy PasswordRotationPolicy
+}
+
+// NewChangePasswordUseCase creates a new change password use case object
+func NewChangePasswordUseCase(
+	userRepository UserRepository,
+	passwordHasher PasswordHasher,
+	passwordPolicy PasswordPolicy,
+	rotationPolicy PasswordRotationPolicy,
+) *ChangePasswordUseCase {
+	return &ChangePasswordUseCase{
+		userRepository: userRepository,
+		passwordHasher: passwordHasher,
+		passwordPolicy: passwordPolicy,
+		rotationPolicy: rotationPolicy,
+	}
+}
+
+// Execute checks the new password against the password policy and the user's recent passwords, then stores it
+func (uc *ChangePasswordUseCase) Execute(ctx context.Context, userID int64, newPassword string) error {
+	if strings.TrimSpace(newPassword) == "" {
+		return ErrEmptyPassword
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		return err
+	}
+
+	if err := validatePassword(uc.passwordPolicy, newPassword, user.Name, user.Email); err != nil {
+		return err
+	}
+
+	reused, err := uc.isReused(ctx, user, newPassword)
+	if err != nil {
+		return err
+	}
+	if reused {
+		return ErrPasswordReused
+	}
+
+	hashedPassword, err := uc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	return uc.userRepository.UpdatePassword(ctx, user.ID, hashedPassword)
+}
+
+// isReused reports whether the password matches the current one or one of the previous History - 1
+func (uc *ChangePasswordUseCase) isReused(ctx context.Context, user *domain.User, password string) (bool, error) {
+	if uc.rotationPolicy.History <= 0 {
+		return false, nil
+	}
+
+	hashes := []string{user.Password}
+	if uc.rotationPolicy.History > 1 {
+		previous, err := uc.userRepository.FindPasswordHistory(ctx, user.ID, uc.rotationPolicy.History-1)
+		if err != nil {
+			return false, err
+		}
+		hashes = append(hashes, previous...)
+	}
+
+	for _, hash := range hashes {
+		if hash == "" {
+			continue
+		}
+
+		// Hashes in an unsupported format can't match, they are skipped rather than failing the change
+		if matches, err := uc.passwordHasher.Verify(password, hash); err == nil && matches {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
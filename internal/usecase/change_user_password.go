@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// ChangeUserPasswordInput represents the input of the change user password use case
+type ChangeUserPasswordInput struct {
+	UserID          int64
+	CurrentPassword string
+	NewPassword     string
+	// SessionID and RememberToken identify the credentials of the current device, which stay valid
+	SessionID     int64
+	RememberToken string
+}
+
+// ChangeUserPasswordUseCase represents the use case for a logged-in user changing their own password
+type ChangeUserPasswordUseCase struct {
+	logger                  *slog.Logger
+	userRepository          UserRepository
+	rememberRepository      RememberTokenRepository
+	sessionRepository       SessionRepository
+	trustedDeviceRepository TrustedDeviceRepository
+	passwordHasher          PasswordHasher
+	changePasswordUseCase   *ChangePasswordUseCase
+	taskDistributor         TaskDistributor
+}
+
+// NewChangeUserPasswordUseCase creates a new ChangeUserPasswordUseCase object
+func NewChangeUserPasswordUseCase(
+	logger *slog.Logger,
+	userRepository UserRepository,
+	rememberRepository RememberTokenRepository,
+	sessionRepository SessionRepository,
+	trustedDeviceRepository TrustedDeviceRepository,
+	passwordHasher PasswordHasher,
+	changePasswordUseCase *ChangePasswordUseCase,
+	taskDistributor TaskDistributor,
+) *ChangeUserPasswordUseCase {
+	return &ChangeUserPasswordUseCase{
+		logger:                  logger,
+		userRepository:          userRepository,
+		rememberRepository:      rememberRepository,
+		sessionRepository:       sessionRepository,
+		trustedDeviceRepository: trustedDeviceRepository,
+		passwordHasher:          passwordHasher,
+		changePasswordUseCase:   changePasswordUseCase,
+		taskDistributor:         taskDistributor,
+	}
+}
+
+// Execute verifies the current password, stores the new one, signs the user out of their other devices,
+// stops trusting any device to skip the second factor and notifies them by email
+func (uc *ChangeUserPasswordUseCase) Execute(ctx context.Context, input ChangeUserPasswordInput) error {
+	user, err := uc.userRepository.FindByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		return err
+	}
+
+	// The directory owns the password of users provisioned from it
+	if user.AuthSource != domain.AuthSourceLocal {
+		return ErrPasswordManagedExternally
+	}
+
+	if input.CurrentPassword == "" {
+		return ErrInvalidCurrentPassword
+	}
+
+	valid, err := uc.passwordHasher.Verify(input.CurrentPassword, user.Password)
+	if err != nil || !valid {
+		return ErrInvalidCurrentPassword
+	}
+
+	if err := uc.changePasswordUseCase.Execute(ctx, user.ID, input.NewPassword); err != nil {
+		return err
+	}
+
+	// Anyone who knew the old password may still be signed in elsewhere
+	exceptTokenHash := ""
+	if input.RememberToken != "" {
+		exceptTokenHash = uc.rememberRepository.Hash(input.RememberToken)
+	}
+	if err := uc.rememberRepository.DeleteByUserID(ctx, user.ID, exceptTokenHash); err != nil {
+		return err
+	}
+	if err := uc.sessionRepository.DeleteByUserID(ctx, user.ID, input.SessionID); err != nil {
+		return err
+	}
+	if err := uc.trustedDeviceRepository.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// The password has changed at this point, a failed notification must not report the change as failed
+	if err := uc.taskDistributor.DistributeTaskSendEmailPasswordChanged(ctx, user.Email, time.Now()); err != nil {
+		uc.logger.Error("Failed to distribute password changed email", "user_id", user.ID, "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ForwardAuthRequest holds the credentials and original destination of a request checked on behalf of a reverse proxy
+type ForwardAuthRequest struct {
+	BearerToken   string
+	SessionToken  string
+	RememberToken string
+	Host          string
+	URI           string
+	ClientIP      string
+}
+
+// ForwardAuthIdentity represents the user a reverse proxy lets through.
+// Applications trust it, so it only carries attributes users can't edit themselves.
+type ForwardAuthIdentity struct {
+	UserID int64
+	Email  string
+	Roles  []string
+	// Scopes are those of the credential, ScopeAll for interactive logins
+	Scopes []string
+	// RotatedSession is set when the session token was rotated and the browser must receive the new cookie
+	RotatedSession *SessionAuthentication
+}
+
+// forwardAuthCredential represents the user and limits of validated forward auth credentials
+type forwardAuthCredential struct {
+	userID         int64
+	scopes         []string
+	expiresAt      time.Time
+	rotatedSession *SessionAuthentication
+}
+
+// CheckForwardAuthUseCase represents the use case for validating requests on behalf of reverse proxies
+type CheckForwardAuthUseCase struct {
+	authenticateAccessTokenUseCase         *AuthenticateAccessTokenUseCase
+	authenticatePersonalAccessTokenUseCase *AuthenticatePersonalAccessTokenUseCase
+	authenticateSessionUseCase             *AuthenticateSessionUseCase
+	rememberRepository                     RememberTokenRepository
+	userRepository                         UserRepository
+	rotationPolicy                         PasswordRotationPolicy
+	identityCache                          IdentityCache
+	rules                                  []domain.AccessRule
+}
+
+// NewCheckForwardAuthUseCase creates a new CheckForwardAuthUseCase object.
+// The first rule matching the destination decides which roles are allowed, destinations without a rule accept any user.
+func NewCheckForwardAuthUseCase(
+	authenticateAccessTokenUseCase *AuthenticateAccessTokenUseCase,
+	authenticatePersonalAccessTokenUseCase *AuthenticatePersonalAccessTokenUseCase,
+	authenticateSessionUseCase *AuthenticateSessionUseCase,
+	rememberRepository RememberTokenRepository,
+	userRepository UserRepository,
+	rotationPolicy PasswordRotationPolicy,
+	identityCache IdentityCache,
+	rules []domain.AccessRule,
+) *CheckForwardAuthUseCase {
+	return &CheckForwardAuthUseCase{
+		authenticateAccessTokenUseCase:         authenticateAccessTokenUseCase,
+		authenticatePersonalAccessTokenUseCase: authenticatePersonalAccessTokenUseCase,
+		authenticateSessionUseCase:             authenticateSessionUseCase,
+		rememberRepository:                     rememberRepository,
+		userRepository:                         userRepository,
+		rotationPolicy:                         rotationPolicy,
+		identityCache:                          identityCache,
+		rules:                                  rules,
+	}
+}
+
+// Execute resolves the user behind the bearer token, session or remember token and applies the access rules of the destination.
+// It returns ErrUserUnauthorized without valid credentials and ErrForbidden when a rule rejects the user
+// or the path of the destination can't be decoded.
+func (uc *CheckForwardAuthUseCase) Execute(ctx context.Context, req ForwardAuthRequest) (*ForwardAuthIdentity, error) {
+	identity, err := uc.identify(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, _ := strings.Cut(req.URI, "?")
+	if _, ok := domain.CleanPath(path); !ok {
+		return nil, ErrForbidden
+	}
+
+	for _, rule := range uc.rules {
+		if rule.Matches(req.Host, path) {
+			if !rule.Allows(identity.Roles) {
+				return nil, ErrForbidden
+			}
+
+			break
+		}
+	}
+
+	return identity, nil
+}
+
+// identify returns the identity of the credentials, from the cache when they were validated recently.
+// Revoked credentials may therefore keep working for up to the TTL of the cache, expired ones never do.
+func (uc *CheckForwardAuthUseCase) identify(ctx context.Context, req ForwardAuthRequest) (*ForwardAuthIdentity, error) {
+	var cacheKey string
+	switch {
+	case req.BearerToken != "":
+		cacheKey = fmt.Sprintf("bearer:%x", sha256.Sum256([]byte(req.BearerToken)))
+	case req.SessionToken != "":
+		cacheKey = fmt.Sprintf("session:%x", sha256.Sum256([]byte(req.SessionToken)))
+	case req.RememberToken != "":
+		cacheKey = fmt.Sprintf("remember:%x", sha256.Sum256([]byte(req.RememberToken)))
+	default:
+		return nil, ErrUserUnauthorized
+	}
+
+	if identity, ok := uc.identityCache.Get(cacheKey); ok {
+		return identity, nil
+	}
+
+	credential, err := uc.resolveCredential(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, credential.userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserUnauthorized
+		}
+
+		return nil, err
+	}
+
+	// Users with an expired password are sent to the login page, where they must change it
+	if user.IsBlocked() || user.IsDeleted() || uc.rotationPolicy.IsExpired(user) {
+		return nil, ErrUserUnauthorized
+	}
+
+	identity := &ForwardAuthIdentity{UserID: user.ID, Email: user.Email, Roles: user.Roles, Scopes: credential.scopes}
+	uc.identityCache.Set(cacheKey, identity, credential.expiresAt)
+
+	// The new session token is only handed out once, cached identities never carry it
+	if credential.rotatedSession != nil {
+		rotated := *identity
+		rotated.RotatedSession = credential.rotatedSession
+		return &rotated, nil
+	}
+
+	return identity, nil
+}
+
+// resolveCredential validates the credentials and returns the user they belong to, their scopes and expiry.
+// Service principals are rejected, the proxied applications are meant for people.
+func (uc *CheckForwardAuthUseCase) resolveCredential(ctx context.Context, req ForwardAuthRequest) (*forwardAuthCredential, error) {
+	if req.BearerToken != "" {
+		if strings.HasPrefix(req.BearerToken, domain.PersonalAccessTokenPrefix) {
+			token, err := uc.authenticatePersonalAccessTokenUseCase.Execute(ctx, req.BearerToken, req.ClientIP)
+			if err != nil {
+				if errors.Is(err, ErrInvalidToken) {
+					return nil, ErrUserUnauthorized
+				}
+
+				return nil, err
+			}
+
+			return &forwardAuthCredential{userID: token.UserID, scopes: token.Scopes, expiresAt: token.ExpiresAt}, nil
+		}
+
+		// Impersonation is only possible through this API, where each request is audited
+		principal, err := uc.authenticateAccessTokenUseCase.Execute(req.BearerToken)
+		if err != nil || !principal.IsUser() || principal.IsImpersonated() {
+			return nil, ErrUserUnauthorized
+		}
+
+		return &forwardAuthCredential{userID: principal.ID, scopes: principal.Scopes, expiresAt: principal.ExpiresAt}, nil
+	}
+
+	if req.SessionToken != "" {
+		result, err := uc.authenticateSessionUseCase.Execute(ctx, req.SessionToken)
+		if err != nil {
+			if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrSessionRotated) {
+				return nil, ErrUserUnauthorized
+			}
+
+			return nil, err
+		}
+
+		credential := &forwardAuthCredential{userID: result.Session.UserID, scopes: []string{domain.ScopeAll}, expiresAt: result.Session.ExpiresAt}
+		if result.RotatedToken != "" {
+			credential.rotatedSession = result
+		}
+
+		return credential, nil
+	}
+
+	rememberToken, err := uc.rememberRepository.FindByToken(ctx, uc.rememberRepository.Hash(req.RememberToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserUnauthorized
+		}
+
+		return nil, err
+	}
+
+	// Remember tokens are issued by interactive logins
+	return &forwardAuthCredential{userID: rememberToken.UserID, scopes: []string{domain.ScopeAll}, expiresAt: rememberToken.ExpiresAt}, nil
+}
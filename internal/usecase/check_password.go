@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"strings"
+)
+
+// PasswordViolation represents a password policy rule the password breaks
+type PasswordViolation struct {
+	Code    string `json:"code" example:"too_short"`
+	Message string `json:"message" example:"password must be at least 8 characters long"`
+}
+
+// PasswordCheckResult represents the evaluation of a password against the password policy
+type PasswordCheckResult struct {
+	Valid bool `json:"valid" example:"false"`
+	// Score estimates how hard the password is to guess, from 0 (too guessable) to 4 (very unguessable)
+	Score       int                 `json:"score" example:"1"`
+	Violations  []PasswordViolation `json:"violations"`
+	Suggestions []string            `json:"suggestions" example:"Add another word or two, uncommon words are better"`
+}
+
+// PasswordPolicyError is returned when a password doesn't satisfy the password policy.
+// It matches ErrWeakPassword with errors.Is.
+type PasswordPolicyError struct {
+	Result *PasswordCheckResult
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Result.Violations))
+	for _, violation := range e.Result.Violations {
+		messages = append(messages, violation.Message)
+	}
+
+	return ErrWeakPassword.Error() + ": " + strings.Join(messages, ", ")
+}
+
+// Is reports whether the target is ErrWeakPassword
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// validatePassword checks the password against the policy, returning a *PasswordPolicyError when it is rejected
+func validatePassword(policy PasswordPolicy, password string, userInputs ...string) error {
+	result := policy.Check(password, userInputs...)
+	if !result.Valid {
+		return &PasswordPolicyError{Result: result}
+	}
+
+	return nil
+}
+
+// CheckPasswordUseCase represents the check password use case object
+type CheckPasswordUseCase struct {
+	passwordPolicy PasswordPolicy
+}
+
+// NewCheckPasswordUseCase creates a new check password use case object
+func NewCheckPasswordUseCase(passwordPolicy PasswordPolicy) *CheckPasswordUseCase {
+	return &CheckPasswordUseCase{passwordPolicy: passwordPolicy}
+}
+
+// Execute evaluates the password without saving anything, so the frontend can give feedback while the user types.
+// Name and email are optional and improve the estimation when known.
+func (uc *CheckPasswordUseCase) Execute(password, name, email string) *PasswordCheckResult {
+	return uc.passwordPolicy.Check(password, name, email)
+}
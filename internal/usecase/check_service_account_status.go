@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// CheckServiceAccountStatusUseCase represents the use case for checking that a service account may still authenticate
+type CheckServiceAccountStatusUseCase struct {
+	serviceAccountRepository ServiceAccountRepository
+	checkUserStatusUseCase   *CheckUserStatusUseCase
+}
+
+// NewCheckServiceAccountStatusUseCase creates a new CheckServiceAccountStatusUseCase object
+func NewCheckServiceAccountStatusUseCase(
+	serviceAccountRepository ServiceAccountRepository,
+	checkUserStatusUseCase *CheckUserStatusUseCase,
+) *CheckServiceAccountStatusUseCase {
+	return &CheckServiceAccountStatusUseCase{
+		serviceAccountRepository: serviceAccountRepository,
+		checkUserStatusUseCase:   checkUserStatusUseCase,
+	}
+}
+
+// Execute returns the service account when it exists and its owner is active
+func (uc *CheckServiceAccountStatusUseCase) Execute(ctx context.Context, serviceAccountID int64) (*domain.ServiceAccount, error) {
+	account, err := uc.serviceAccountRepository.FindByID(ctx, serviceAccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserUnauthorized
+		}
+
+		return nil, err
+	}
+
+	if err := uc.CheckOwner(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// CheckOwner returns ErrAccountUnavailable when the owner of the service account has been blocked, deleted or purged.
+// A service account acts on behalf of its owner, so it can't outlive their access.
+func (uc *CheckServiceAccountStatusUseCase) CheckOwner(ctx context.Context, account *domain.ServiceAccount) error {
+	if _, err := uc.checkUserStatusUseCase.Execute(ctx, account.OwnerUserID); err != nil {
+		if errors.Is(err, ErrUserUnauthorized) {
+			return ErrAccountUnavailable
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// CheckUserStatusUseCase represents the use case for checking that the user behind a credential may still use it
+type CheckUserStatusUseCase struct {
+	userRepository UserRepository
+}
+
+// NewCheckUserStatusUseCase creates a new CheckUserStatusUseCase object
+func NewCheckUserStatusUseCase(userRepository UserRepository) *CheckUserStatusUseCase {
+	return &CheckUserStatusUseCase{userRepository: userRepository}
+}
+
+// Execute returns the user when their account is active. Access tokens are stateless and outlive
+// the sessions revoked when an account is blocked or deleted, so each request is checked.
+func (uc *CheckUserStatusUseCase) Execute(ctx context.Context, userID int64) (*domain.User, error) {
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserUnauthorized
+		}
+
+		return nil, err
+	}
+
+	if user.IsBlocked() || user.IsDeleted() {
+		return nil, ErrAccountUnavailable
+	}
+
+	return user, nil
+}
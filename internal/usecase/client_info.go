@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// clientInfoContextKey is the context key of the client info, unexported to avoid collisions
+type clientInfoContextKey struct{}
+
+// ContextWithClientInfo returns a copy of ctx carrying the client the request comes from
+func ContextWithClientInfo(ctx context.Context, info domain.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// ClientInfoFromContext returns the client the request comes from, if the context carries one
+func ClientInfoFromContext(ctx context.Context) (domain.ClientInfo, bool) {
+	info, ok := ctx.Value(clientInfoContextKey{}).(domain.ClientInfo)
+	return info, ok
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ConfirmEmailChangeUseCase represents the use case for confirming a new email address
+type ConfirmEmailChangeUseCase struct {
+	emailChangeRepository EmailChangeRepository
+}
+
+// NewConfirmEmailChangeUseCase creates a new ConfirmEmailChangeUseCase object
+func NewConfirmEmailChangeUseCase(emailChangeRepository EmailChangeRepository) *ConfirmEmailChangeUseCase {
+	return &ConfirmEmailChangeUseCase{emailChangeRepository: emailChangeRepository}
+}
+
+// Execute applies the email change the token was sent for
+func (uc *ConfirmEmailChangeUseCase) Execute(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidToken
+	}
+
+	change, err := uc.emailChangeRepository.FindByConfirmToken(ctx, uc.emailChangeRepository.Hash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	applied, err := uc.emailChangeRepository.Confirm(ctx, change)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	// Another account verified the address between the request and the confirmation
+	if !applied {
+		return ErrEmailExists
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"strings"
+	"time"
+)
+
+// maxPersonalAccessTokenDays caps how long a personal access token can live
+const maxPersonalAccessTokenDays = 365
+
+// CreatePersonalAccessTokenUseCase represents the create personal access token use case object
+type CreatePersonalAccessTokenUseCase struct {
+	tokenRepository PersonalAccessTokenRepository
+}
+
+// NewCreatePersonalAccessTokenUseCase creates a new CreatePersonalAccessTokenUseCase object
+func NewCreatePersonalAccessTokenUseCase(tokenRepository PersonalAccessTokenRepository) *CreatePersonalAccessTokenUseCase {
+	return &CreatePersonalAccessTokenUseCase{tokenRepository: tokenRepository}
+}
+
+// Execute creates a new personal access token for the user.
+// The raw token is returned only once, only its hash is stored.
+func (uc *CreatePersonalAccessTokenUseCase) Execute(
+	ctx context.Context,
+	userID int64,
+	name string,
+	scopes []string,
+	expiresInDays int,
+) (*domain.PersonalAccessToken, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", ErrEmptyTokenName
+	}
+
+	if expiresInDays < 1 || expiresInDays > maxPersonalAccessTokenDays {
+		return nil, "", ErrInvalidTokenExpiry
+	}
+
+	token := &domain.PersonalAccessToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(time.Duration(expiresInDays) * 24 * time.Hour),
+	}
+
+	if err := token.Validate(); err != nil {
+		return nil, "", ErrInvalidScope
+	}
+
+	// Generate and hash the token
+	rawToken, err := uc.tokenRepository.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token.TokenHash = uc.tokenRepository.Hash(rawToken)
+
+	if err := uc.tokenRepository.Save(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, rawToken, nil
+}
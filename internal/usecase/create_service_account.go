@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"slices"
+	"strings"
+)
+
+// CreateServiceAccountUseCase represents the create service account use case object
+type CreateServiceAccountUseCase struct {
+	serviceAccountRepository ServiceAccountRepository
+	userRepository           UserRepository
+}
+
+// NewCreateServiceAccountUseCase creates a new CreateServiceAccountUseCase object
+func NewCreateServiceAccountUseCase(serviceAccountRepository ServiceAccountRepository, userRepository UserRepository) *CreateServiceAccountUseCase {
+	return &CreateServiceAccountUseCase{
+		serviceAccountRepository: serviceAccountRepository,
+		userRepository:           userRepository,
+	}
+}
+
+// Execute creates a new service account owned by the user.
+// The raw client secret is returned only once, only its hash is stored.
+func (uc *CreateServiceAccountUseCase) Execute(
+	ctx context.Context,
+	ownerUserID int64,
+	name string,
+	organization string,
+	scopes []string,
+) (*domain.ServiceAccount, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", ErrEmptyName
+	}
+
+	if strings.TrimSpace(organization) == "" {
+		return nil, "", ErrEmptyOrganization
+	}
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	account := &domain.ServiceAccount{
+		Name:         strings.TrimSpace(name),
+		Organization: strings.TrimSpace(organization),
+		OwnerUserID:  ownerUserID,
+		Scopes:       scopes,
+	}
+
+	if err := account.Validate(); err != nil {
+		return nil, "", ErrInvalidScope
+	}
+
+	if slices.ContainsFunc(scopes, domain.IsPrivilegedServiceScope) {
+		owner, err := uc.userRepository.FindByID(ctx, ownerUserID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !owner.HasRole(domain.RoleAdmin) {
+			return nil, "", ErrInvalidScope
+		}
+	}
+
+	clientID, err := uc.serviceAccountRepository.GenerateClientID()
+	if err != nil {
+		return nil, "", err
+	}
+	account.ClientID = clientID
+
+	secret, err := uc.serviceAccountRepository.GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.serviceAccountRepository.Save(ctx, account, uc.serviceAccountRepository.Hash(secret)); err != nil {
+		return nil, "", err
+	}
+
+	return account, secret, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+)
+
+// SessionPolicy holds the timeouts of server-side sessions
+type SessionPolicy struct {
+	// IdleTimeout ends sessions that haven't been used for this long
+	IdleTimeout time.Duration
+	// AbsoluteTimeout ends sessions this long after login, regardless of activity
+	AbsoluteTimeout time.Duration
+	// RememberMeTimeout replaces the absolute timeout of persistent sessions
+	RememberMeTimeout time.Duration
+}
+
+// SessionToken represents a newly issued session and its raw token
+type SessionToken struct {
+	Session *domain.Session
+	Token   string
+}
+
+// CreateSessionUseCase represents the create session use case object
+type CreateSessionUseCase struct {
+	sessionRepository  SessionRepository
+	userRepository     UserRepository
+	trackDeviceUseCase *TrackLoginDeviceUseCase
+	policy             SessionPolicy
+}
+
+// NewCreateSessionUseCase creates a new CreateSessionUseCase object
+func NewCreateSessionUseCase(
+	sessionRepository SessionRepository,
+	userRepository UserRepository,
+	trackDeviceUseCase *TrackLoginDeviceUseCase,
+	policy SessionPolicy,
+) *CreateSessionUseCase {
+	return &CreateSessionUseCase{
+		sessionRepository:  sessionRepository,
+		userRepository:     userRepository,
+		trackDeviceUseCase: trackDeviceUseCase,
+		policy:             policy,
+	}
+}
+
+// Execute starts a new session for the user, who just authenticated with amr.
+// The raw token is returned only once, only its hash is stored. Logins must pass AssessLoginRiskUseCase first.
+func (uc *CreateSessionUseCase) Execute(ctx context.Context, userID int64, persistent bool, amr []string) (*SessionToken, error) {
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBlocked() {
+		return nil, ErrAccountUnavailable
+	}
+
+	rawToken, err := uc.sessionRepository.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	lifetime := uc.policy.AbsoluteTimeout
+	if persistent {
+		lifetime = uc.policy.RememberMeTimeout
+	}
+
+	session := &domain.Session{
+		UserID:     user.ID,
+		TokenHash:  uc.sessionRepository.Hash(rawToken),
+		Roles:      user.Roles,
+		Persistent: persistent,
+		AMR:        amr,
+		ExpiresAt:  time.Now().Add(lifetime),
+	}
+
+	if err := uc.sessionRepository.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepository.UpdateLastLogin(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	uc.trackDeviceUseCase.Execute(ctx, user)
+
+	return &SessionToken{Session: session, Token: rawToken}, nil
+}
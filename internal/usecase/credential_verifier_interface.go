@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// CredentialVerifier verifies a user's email and password against an authentication backend
+type CredentialVerifier interface {
+	// Verify returns the authenticated user, or ErrInvalidCredentials when the credentials don't match.
+	Verify(ctx context.Context, email string, password string) (*domain.User, error)
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// DataExportRepository represents the data export repository interface
+type DataExportRepository interface {
+	// Generate creates a new download token.
+	Generate() (string, error)
+	// Hash hashes a download token using SHA-256.
+	Hash(token string) string
+	Save(ctx context.Context, export *domain.DataExport) error
+	FindByID(ctx context.Context, id int64) (*domain.DataExport, error)
+	FindByUserID(ctx context.Context, userID int64) ([]domain.DataExport, error)
+	// FindByToken finds the ready, unexpired export matching the download token hash.
+	FindByToken(ctx context.Context, tokenHash string) (*domain.DataExport, error)
+	UpdateStatus(ctx context.Context, id int64, status string) error
+	// Complete stores the download token, archive and expiry of the export and marks it ready.
+	Complete(ctx context.Context, export *domain.DataExport) error
+	// DeleteExpired deletes expired exports and returns the storage keys of their archives.
+	DeleteExpired(ctx context.Context) ([]string, error)
+}
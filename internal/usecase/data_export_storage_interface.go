@@ -0,0 +1,10 @@
+package usecase
+
+import "context"
+
+// DataExportStorage represents the temporary storage of data export archives
+type DataExportStorage interface {
+	Save(ctx context.Context, key string, data []byte) error
+	Load(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
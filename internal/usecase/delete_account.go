@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// AccountRestoreTokenPurpose is the purpose of the restricted token issued when a deleted account logs in.
+// It is only accepted by the restore account use case.
+const AccountRestoreTokenPurpose = "account_restore"
+
+// accountRestoreTokenDuration is how long the user has to confirm the restoration after logging in
+const accountRestoreTokenDuration = 10 * time.Minute
+
+// AccountPendingDeletionError is returned by logins with correct credentials to an account waiting for deletion.
+// It matches ErrAccountPendingDeletion with errors.Is and carries the restricted token to restore the account with.
+type AccountPendingDeletionError struct {
+	Token     string
+	ExpiresAt time.Time
+	PurgeAt   time.Time
+}
+
+func (e *AccountPendingDeletionError) Error() string {
+	return ErrAccountPendingDeletion.Error()
+}
+
+// Is reports whether the target is ErrAccountPendingDeletion
+func (e *AccountPendingDeletionError) Is(target error) bool {
+	return target == ErrAccountPendingDeletion
+}
+
+// requireAccountRestore issues a restricted token when the user's account is waiting for deletion, nil otherwise.
+// The token is bound to the deletion time, so it can't restore a later deletion.
+func requireAccountRestore(tokenGenerator TokenGenerator, user *domain.User) error {
+	if !user.IsDeleted() {
+		return nil
+	}
+
+	token, err := tokenGenerator.GenerateTokenWithClaims(user.ID, AccountRestoreTokenPurpose, accountRestoreTokenDuration, map[string]any{
+		"deleted_at": user.DeletedAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	pendingDeletion := &AccountPendingDeletionError{Token: token, ExpiresAt: time.Now().Add(accountRestoreTokenDuration)}
+	if user.PurgeAt != nil {
+		pendingDeletion.PurgeAt = *user.PurgeAt
+	}
+
+	return pendingDeletion
+}
+
+// DeleteAccountUseCase represents the use case for a user deleting their own account.
+// The account is only soft deleted, it is purged once the grace period has ended.
+type DeleteAccountUseCase struct {
+	logger             *slog.Logger
+	userRepository     UserRepository
+	credentialVerifier CredentialVerifier
+	taskDistributor    TaskDistributor
+	gracePeriod        time.Duration
+}
+
+// NewDeleteAccountUseCase creates a new DeleteAccountUseCase object
+func NewDeleteAccountUseCase(
+	logger *slog.Logger,
+	userRepository UserRepository,
+	credentialVerifier CredentialVerifier,
+	taskDistributor TaskDistributor,
+	gracePeriod time.Duration,
+) *DeleteAccountUseCase {
+	return &DeleteAccountUseCase{
+		logger:             logger,
+		userRepository:     userRepository,
+		credentialVerifier: credentialVerifier,
+		taskDistributor:    taskDistributor,
+		gracePeriod:        gracePeriod,
+	}
+}
+
+// Execute re-authenticates the user with their password, schedules the account for deletion
+// and signs the user out everywhere. It returns the time the account will be purged.
+func (uc *DeleteAccountUseCase) Execute(ctx context.Context, userID int64, password string) (time.Time, error) {
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrUserNotFound
+		}
+
+		return time.Time{}, err
+	}
+
+	if user.IsDeleted() {
+		return time.Time{}, ErrAccountPendingDeletion
+	}
+
+	if password == "" {
+		return time.Time{}, ErrInvalidCurrentPassword
+	}
+
+	// The configured backend verifies the password, so directory users can delete their account too
+	verified, err := uc.credentialVerifier.Verify(ctx, user.Email, password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrPasswordResetRequired) {
+			return time.Time{}, ErrInvalidCurrentPassword
+		}
+
+		return time.Time{}, err
+	}
+	if verified.ID != user.ID {
+		return time.Time{}, ErrInvalidCurrentPassword
+	}
+
+	purgeAt := time.Now().Add(uc.gracePeriod)
+	if _, err := uc.userRepository.SoftDelete(ctx, user.ID, purgeAt); err != nil {
+		return time.Time{}, err
+	}
+
+	// The account is scheduled for deletion at this point, a failed notification must not report it as failed
+	if err := uc.taskDistributor.DistributeTaskSendEmailAccountDeletionScheduled(ctx, user.Email, purgeAt); err != nil {
+		uc.logger.Error("Failed to distribute account deletion scheduled email", "user_id", user.ID, "error", err)
+	}
+
+	return purgeAt, nil
+}
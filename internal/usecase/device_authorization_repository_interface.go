@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// DeviceAuthorizationRepository represents the device authorization repository interface
+type DeviceAuthorizationRepository interface {
+	// GenerateDeviceCode creates the secret code the device polls the token endpoint with.
+	GenerateDeviceCode() (string, error)
+	// GenerateUserCode creates the short code the user types on the verification page.
+	GenerateUserCode() (string, error)
+	// Hash hashes a device or user code using SHA-256.
+	Hash(code string) string
+	Save(ctx context.Context, authorization *domain.DeviceAuthorization) error
+	// FindByDeviceCode finds the authorization by device code hash, including expired ones.
+	FindByDeviceCode(ctx context.Context, deviceCodeHash string) (*domain.DeviceAuthorization, error)
+	// FindPendingByUserCode finds the unexpired pending authorization by user code hash.
+	FindPendingByUserCode(ctx context.Context, userCodeHash string) (*domain.DeviceAuthorization, error)
+	// Decide approves or denies a pending authorization on behalf of the user.
+	Decide(ctx context.Context, id int64, userID int64, status string) error
+	// RecordPoll stores when the device last polled and the interval it must respect.
+	RecordPoll(ctx context.Context, id int64, interval int) error
+	// Delete consumes the authorization, returning sql.ErrNoRows when it was already consumed.
+	Delete(ctx context.Context, id int64) error
+}
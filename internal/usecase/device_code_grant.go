@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// slowDownIncrement is added to the poll interval each time the device polls too fast (RFC 8628 section 3.5)
+const slowDownIncrement = 5
+
+// deviceAccessTokenDuration is the lifetime of access tokens issued to devices
+const deviceAccessTokenDuration = 24 * time.Hour
+
+// DeviceCodeGrantUseCase represents the OAuth device code grant use case object
+type DeviceCodeGrantUseCase struct {
+	deviceAuthorizationRepository DeviceAuthorizationRepository
+	rememberRepository            RememberTokenRepository
+	tokenGenerator                TokenGenerator
+	checkUserStatusUseCase        *CheckUserStatusUseCase
+	rememberMeHours               time.Duration
+}
+
+// NewDeviceCodeGrantUseCase creates a new DeviceCodeGrantUseCase object
+func NewDeviceCodeGrantUseCase(
+	deviceAuthorizationRepository DeviceAuthorizationRepository,
+	rememberRepository RememberTokenRepository,
+	tokenGenerator TokenGenerator,
+	checkUserStatusUseCase *CheckUserStatusUseCase,
+) *DeviceCodeGrantUseCase {
+	return &DeviceCodeGrantUseCase{
+		deviceAuthorizationRepository: deviceAuthorizationRepository,
+		rememberRepository:            rememberRepository,
+		tokenGenerator:                tokenGenerator,
+		checkUserStatusUseCase:        checkUserStatusUseCase,
+		rememberMeHours:               time.Hour * 24 * 30,
+	}
+}
+
+// Execute exchanges an approved device code for tokens.
+// Until the user decides, it returns ErrAuthorizationPending, or ErrSlowDown when the device polls faster than its interval.
+func (uc *DeviceCodeGrantUseCase) Execute(ctx context.Context, clientID string, deviceCode string) (*OAuthToken, error) {
+	if deviceCode == "" {
+		return nil, ErrInvalidGrant
+	}
+
+	authorization, err := uc.deviceAuthorizationRepository.FindByDeviceCode(ctx, uc.deviceAuthorizationRepository.Hash(deviceCode))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidGrant
+		}
+
+		return nil, err
+	}
+
+	// The device code is bound to the client it was issued to
+	if authorization.ClientID != clientID {
+		return nil, ErrInvalidGrant
+	}
+
+	if authorization.IsExpired() {
+		return nil, ErrExpiredToken
+	}
+
+	switch authorization.Status {
+	case domain.DeviceAuthorizationPending:
+		return nil, uc.recordPoll(ctx, authorization)
+	case domain.DeviceAuthorizationDenied:
+		if err := uc.deviceAuthorizationRepository.Delete(ctx, authorization.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, ErrAccessDenied
+	}
+
+	// Consuming the authorization first guarantees a device code is exchanged only once
+	if err := uc.deviceAuthorizationRepository.Delete(ctx, authorization.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidGrant
+		}
+
+		return nil, err
+	}
+
+	return uc.issueToken(ctx, authorization)
+}
+
+// recordPoll enforces the poll interval and returns the error the device should receive
+func (uc *DeviceCodeGrantUseCase) recordPoll(ctx context.Context, authorization *domain.DeviceAuthorization) error {
+	interval := authorization.Interval
+	pollErr := ErrAuthorizationPending
+
+	if authorization.LastPolledAt != nil && time.Since(*authorization.LastPolledAt) < time.Duration(interval)*time.Second {
+		interval += slowDownIncrement
+		pollErr = ErrSlowDown
+	}
+
+	if err := uc.deviceAuthorizationRepository.RecordPoll(ctx, authorization.ID, interval); err != nil {
+		return err
+	}
+
+	return pollErr
+}
+
+// issueToken issues the access token of the approving user.
+// Only unrestricted grants receive a refresh token, since refreshing issues an unrestricted access token.
+func (uc *DeviceCodeGrantUseCase) issueToken(ctx context.Context, authorization *domain.DeviceAuthorization) (*OAuthToken, error) {
+	if authorization.UserID == nil {
+		return nil, ErrInvalidGrant
+	}
+	userID := *authorization.UserID
+
+	// The account may have been blocked or deleted since the user approved the device
+	if _, err := uc.checkUserStatusUseCase.Execute(ctx, userID); err != nil {
+		if errors.Is(err, ErrAccountUnavailable) || errors.Is(err, ErrUserUnauthorized) {
+			return nil, ErrInvalidGrant
+		}
+
+		return nil, err
+	}
+
+	claims := map[string]any{"client_id": authorization.ClientID}
+	if authorization.Scope != "" {
+		claims["scope"] = authorization.Scope
+	}
+
+	accessToken, err := uc.tokenGenerator.GenerateTokenWithClaims(userID, "access_token", deviceAccessTokenDuration, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &OAuthToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(deviceAccessTokenDuration.Seconds()),
+		Scope:       authorization.Scope,
+	}
+
+	if authorization.Scope == "" {
+		rawToken, err := uc.rememberRepository.Generate()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := uc.rememberRepository.Save(ctx, userID, authorization.ClientID, uc.rememberRepository.Hash(rawToken), uc.rememberMeHours); err != nil {
+			return nil, err
+		}
+
+		token.RefreshToken = rawToken
+	}
+
+	return token, nil
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Data export download formats
+const (
+	DataExportFormatZip  = "zip"
+	DataExportFormatJSON = "json"
+)
+
+// DataExportFile represents a downloadable data export
+type DataExportFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// DownloadDataExportUseCase represents the use case for downloading a data export with the emailed link
+type DownloadDataExportUseCase struct {
+	dataExportRepository DataExportRepository
+	dataExportStorage    DataExportStorage
+}
+
+// NewDownloadDataExportUseCase creates a new DownloadDataExportUseCase object
+func NewDownloadDataExportUseCase(dataExportRepository DataExportRepository, dataExportStorage DataExportStorage) *DownloadDataExportUseCase {
+	return &DownloadDataExportUseCase{
+		dataExportRepository: dataExportRepository,
+		dataExportStorage:    dataExportStorage,
+	}
+}
+
+// Execute returns the archive of the export the token was sent for, or the JSON document inside it
+func (uc *DownloadDataExportUseCase) Execute(ctx context.Context, token string, format string) (*DataExportFile, error) {
+	if format == "" {
+		format = DataExportFormatZip
+	}
+	if format != DataExportFormatZip && format != DataExportFormatJSON {
+		return nil, ErrInvalidDataExportFormat
+	}
+
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	export, err := uc.dataExportRepository.FindByToken(ctx, uc.dataExportRepository.Hash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	archive, err := uc.dataExportStorage.Load(ctx, export.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("personal-data-%s", export.CreatedAt.UTC().Format("20060102"))
+	if format == DataExportFormatZip {
+		return &DataExportFile{Name: name + ".zip", ContentType: "application/zip", Data: archive}, nil
+	}
+
+	document, err := readDataExportDocument(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataExportFile{Name: name + ".json", ContentType: "application/json", Data: document}, nil
+}
+
+// readDataExportDocument extracts the JSON document from a data export archive
+func readDataExportDocument(archive []byte) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := reader.Open(DataExportFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
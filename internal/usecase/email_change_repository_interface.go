@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// EmailChangeRepository represents the email change repository interface
+type EmailChangeRepository interface {
+	// Generate creates a new confirmation or revert token.
+	Generate() (string, error)
+	// Hash hashes a token using SHA-256.
+	Hash(token string) string
+	// Save stores the email change and discards the user's other unconfirmed changes.
+	Save(ctx context.Context, change *domain.EmailChange) error
+	FindByConfirmToken(ctx context.Context, tokenHash string) (*domain.EmailChange, error)
+	FindByRevertToken(ctx context.Context, tokenHash string) (*domain.EmailChange, error)
+	// Confirm atomically moves the user to the new address, it returns false when a verified user owns it.
+	Confirm(ctx context.Context, change *domain.EmailChange) (bool, error)
+	// Revert restores the old address and deletes the changes of the user, it returns false when the old address was taken.
+	Revert(ctx context.Context, change *domain.EmailChange) (bool, error)
+}
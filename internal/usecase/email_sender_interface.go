@@ -1,6 +1,10 @@
 package usecase
 
-import "context"
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+)
 
 // EmailSender interface
 type EmailSender interface {
@@ -8,4 +12,13 @@ type EmailSender interface {
 	SendEmailPasswordResetLink(ctx context.Context, email string, token string) error
 	SendEmailVerificationCode(ctx context.Context, email string, code string) error
 	SendEmailLoginOTP(ctx context.Context, email string, token string) error
+	SendEmailPasswordChanged(ctx context.Context, email string, changedAt time.Time) error
+	SendEmailChangeConfirmation(ctx context.Context, newEmail string, token string) error
+	SendEmailChangeNotice(ctx context.Context, oldEmail string, newEmail string, revertToken string) error
+	SendEmailAccountDeletionScheduled(ctx context.Context, email string, purgeAt time.Time) error
+	SendEmailAccountRestored(ctx context.Context, email string) error
+	SendEmailAccountDeleted(ctx context.Context, email string) error
+	SendEmailDataExportReady(ctx context.Context, email string, token string, expiresAt time.Time) error
+	SendEmailNewDeviceLogin(ctx context.Context, email string, login domain.DeviceLogin, secureToken string) error
+	SendEmailMFACode(ctx context.Context, email string, code string) error
 }
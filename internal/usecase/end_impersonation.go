@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+)
+
+// EndImpersonationUseCase represents the use case for an administrator ending an impersonation they started
+type EndImpersonationUseCase struct {
+	logger                  *slog.Logger
+	impersonationRepository ImpersonationRepository
+	auditEventRepository    AuditEventRepository
+}
+
+// NewEndImpersonationUseCase creates a new EndImpersonationUseCase object
+func NewEndImpersonationUseCase(
+	logger *slog.Logger,
+	impersonationRepository ImpersonationRepository,
+	auditEventRepository AuditEventRepository,
+) *EndImpersonationUseCase {
+	return &EndImpersonationUseCase{
+		logger:                  logger,
+		impersonationRepository: impersonationRepository,
+		auditEventRepository:    auditEventRepository,
+	}
+}
+
+// Execute ends the impersonation, its token stops working right away. Ending it again is a no-op.
+func (uc *EndImpersonationUseCase) Execute(ctx context.Context, adminID int64, impersonationID int64, ipAddress string) error {
+	impersonation, err := uc.impersonationRepository.FindByID(ctx, impersonationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrImpersonationNotFound
+		}
+
+		return err
+	}
+
+	if impersonation.AdminID != adminID {
+		return ErrImpersonationNotFound
+	}
+
+	ended, err := uc.impersonationRepository.End(ctx, impersonation.ID)
+	if err != nil {
+		return err
+	}
+	if !ended {
+		return nil
+	}
+
+	uc.logger.Info("Impersonation ended", "impersonation_id", impersonation.ID, "admin_id", adminID, "user_id", impersonation.UserID)
+
+	return uc.auditEventRepository.Save(ctx, &domain.AuditEvent{
+		UserID:    impersonation.UserID,
+		ActorID:   &adminID,
+		Action:    domain.AuditActionImpersonationEnded,
+		Details:   map[string]any{"impersonation_id": impersonation.ID},
+		IPAddress: ipAddress,
+	})
+}
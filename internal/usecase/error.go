@@ -4,17 +4,75 @@ import "errors"
 
 // Pre-defined errors for specific business rule violations.
 var (
-	ErrInvalidCredentials      = errors.New("invalid credentials")
-	ErrInvalidToken            = errors.New("invalid token")
-	ErrEmailExists             = errors.New("user with this email already exists")
-	ErrInvalidInput            = errors.New("invalid input")
-	ErrInvalidEmail            = errors.New("invalid email format")
-	ErrInvalidVerificationCode = errors.New("invalid or expired verification code")
-	ErrEmptyName               = errors.New("name field is required")
-	ErrEmptyEmail              = errors.New("email field is required")
-	ErrEmptyPassword           = errors.New("password field is required")
-	ErrPasswordTooShort        = errors.New("password must be at least 8 characters long")
-	ErrInternalServer          = errors.New("internal server error")
-	ErrUserNotFound            = errors.New("user not found")
-	ErrUserUnauthorized        = errors.New("user is unauthorized")
+	ErrInvalidCredentials         = errors.New("invalid credentials")
+	ErrInvalidToken               = errors.New("invalid token")
+	ErrSessionRotated             = errors.New("session token was replaced, use the new session cookie")
+	ErrEmailExists                = errors.New("user with this email already exists")
+	ErrInvalidInput               = errors.New("invalid input")
+	ErrInvalidEmail               = errors.New("invalid email format")
+	ErrInvalidVerificationCode    = errors.New("invalid or expired verification code")
+	ErrEmptyName                  = errors.New("name field is required")
+	ErrEmptyEmail                 = errors.New("email field is required")
+	ErrEmptyPassword              = errors.New("password field is required")
+	ErrWeakPassword               = errors.New("password does not meet the password policy")
+	ErrInternalServer             = errors.New("internal server error")
+	ErrUserNotFound               = errors.New("user not found")
+	ErrUserUnauthorized           = errors.New("user is unauthorized")
+	ErrEmptyTokenName             = errors.New("token name is required")
+	ErrInvalidScope               = errors.New("invalid or unsupported scope")
+	ErrInvalidTokenExpiry         = errors.New("token expiry must be between 1 and 365 days")
+	ErrTokenNotFound              = errors.New("token not found")
+	ErrEmptyOrganization          = errors.New("organization field is required")
+	ErrServiceAccountNotFound     = errors.New("service account not found")
+	ErrInvalidPublicKey           = errors.New("invalid public key")
+	ErrInvalidClient              = errors.New("invalid client credentials")
+	ErrInvalidGrant               = errors.New("invalid or expired grant")
+	ErrInvalidRotationOverlap     = errors.New("overlap must be between 0 and 168 hours")
+	ErrInvalidUserCode            = errors.New("invalid or expired user code")
+	ErrAuthorizationPending       = errors.New("the authorization request is still pending")
+	ErrSlowDown                   = errors.New("polling too frequently, slow down")
+	ErrExpiredToken               = errors.New("the device code has expired")
+	ErrAccessDenied               = errors.New("the authorization request was denied")
+	ErrUnsupportedTokenType       = errors.New("revocation of this token type is not supported")
+	ErrForbidden                  = errors.New("access to this resource is forbidden")
+	ErrPasswordResetRequired      = errors.New("password must be reset before logging in")
+	ErrPasswordReused             = errors.New("password was used recently, choose a different one")
+	ErrPasswordChangeRequired     = errors.New("password has expired and must be changed")
+	ErrInvalidCurrentPassword     = errors.New("current password is incorrect")
+	ErrPasswordManagedExternally  = errors.New("password is managed by an external directory")
+	ErrEmailManagedExternally     = errors.New("email is managed by an external directory")
+	ErrSameEmail                  = errors.New("new email is the same as the current one")
+	ErrNameTooLong                = errors.New("name must be at most 100 characters long")
+	ErrInvalidLocale              = errors.New("locale must be a valid BCP 47 language tag")
+	ErrInvalidTimezone            = errors.New("timezone must be a valid IANA time zone")
+	ErrInvalidAvatarURL           = errors.New("avatar url must be an absolute http or https url")
+	ErrMetadataTooLarge           = errors.New("metadata must be at most 4096 bytes when encoded as JSON")
+	ErrAccountPendingDeletion     = errors.New("account is scheduled for deletion")
+	ErrDataExportInProgress       = errors.New("a data export is already in progress")
+	ErrDataExportNotFound         = errors.New("data export not found")
+	ErrInvalidDataExportFormat    = errors.New("data export format must be zip or json")
+	ErrAccountUnavailable         = errors.New("account is unavailable, contact support for help")
+	ErrInvalidUserStatus          = errors.New("status must be active, disabled or suspended")
+	ErrInvalidStatusReason        = errors.New("reason must be one of abuse, fraud, compromised, terms_violation or other")
+	ErrStatusNoteTooLong          = errors.New("note must be at most 500 characters long")
+	ErrInvalidSuspensionEnd       = errors.New("suspended until must be in the future")
+	ErrOwnStatusChange            = errors.New("administrators can't change the status of their own account")
+	ErrSelfImpersonation          = errors.New("administrators can't impersonate themselves")
+	ErrAdminImpersonation         = errors.New("administrators can't be impersonated")
+	ErrEmptyImpersonationReason   = errors.New("a reason is required to impersonate a user")
+	ErrImpersonationReasonTooLong = errors.New("reason must be at most 500 characters long")
+	ErrReauthenticationNotAllowed = errors.New("only a login session or login token can be reauthenticated")
+	ErrUnauthorizedClient         = errors.New("the client is not authorized to use this grant type")
+	ErrInvalidTarget              = errors.New("the requested audience is invalid or not allowed")
+	ErrLoginBlocked               = errors.New("sign-in blocked for your security, contact support for help")
+	ErrMFARequired                = errors.New("a second factor is required to finish signing in")
+	ErrInvalidMFACode             = errors.New("invalid or expired verification code")
+	ErrTooManyMFAChallenges       = errors.New("too many sign-in attempts waiting for a second factor, try again later")
+	ErrTrustedDeviceNotFound      = errors.New("trusted device not found")
+	ErrEmptyPhoneNumber           = errors.New("phone number field is required")
+	ErrInvalidPhoneNumber         = errors.New("phone number must be in E.164 format, e.g. +6281234567890")
+	ErrInvalidOTPChannel          = errors.New("channel must be email, sms or voice")
+	ErrPhoneNumberRequired        = errors.New("a verified phone number is required for this channel")
+	ErrTooManyPhoneCodes          = errors.New("too many codes sent to this phone number, try again later")
+	ErrImpersonationNotFound      = errors.New("impersonation not found")
 )
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"archive/zip"
+	"auth/internal/domain"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DataExportFileName is the name of the JSON document inside a data export archive
+const DataExportFileName = "personal-data.json"
+
+// GenerateDataExportUseCase represents the use case for generating a requested data export in the background
+type GenerateDataExportUseCase struct {
+	logger                 *slog.Logger
+	dataExportRepository   DataExportRepository
+	personalDataRepository PersonalDataRepository
+	dataExportStorage      DataExportStorage
+	taskDistributor        TaskDistributor
+	ttl                    time.Duration
+}
+
+// NewGenerateDataExportUseCase creates a new GenerateDataExportUseCase object.
+// Generated archives can be downloaded for ttl.
+func NewGenerateDataExportUseCase(
+	logger *slog.Logger,
+	dataExportRepository DataExportRepository,
+	personalDataRepository PersonalDataRepository,
+	dataExportStorage DataExportStorage,
+	taskDistributor TaskDistributor,
+	ttl time.Duration,
+) *GenerateDataExportUseCase {
+	return &GenerateDataExportUseCase{
+		logger:                 logger,
+		dataExportRepository:   dataExportRepository,
+		personalDataRepository: personalDataRepository,
+		dataExportStorage:      dataExportStorage,
+		taskDistributor:        taskDistributor,
+		ttl:                    ttl,
+	}
+}
+
+// Execute collects the personal data of the export's user into a zipped JSON archive, stores it
+// and emails the user a time-limited download link
+func (uc *GenerateDataExportUseCase) Execute(ctx context.Context, exportID int64) error {
+	export, err := uc.dataExportRepository.FindByID(ctx, exportID)
+	if err != nil {
+		// The export is gone with its user, there is nothing left to generate
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	}
+
+	// The task may be retried after the export was completed
+	if export.Status == domain.DataExportStatusReady {
+		return nil
+	}
+
+	if err := uc.dataExportRepository.UpdateStatus(ctx, export.ID, domain.DataExportStatusProcessing); err != nil {
+		return err
+	}
+
+	email, token, err := uc.generate(ctx, export)
+	if err != nil {
+		if statusErr := uc.dataExportRepository.UpdateStatus(ctx, export.ID, domain.DataExportStatusFailed); statusErr != nil {
+			uc.logger.Error("Failed to mark data export as failed", "export_id", export.ID, "error", statusErr)
+		}
+
+		// Retrying won't help while the account is waiting for deletion
+		if errors.Is(err, ErrAccountPendingDeletion) {
+			return nil
+		}
+
+		return err
+	}
+
+	// The export is ready at this point, a failed notification must not fail it
+	if err := uc.taskDistributor.DistributeTaskSendEmailDataExportReady(ctx, email, token, *export.ExpiresAt); err != nil {
+		uc.logger.Error("Failed to distribute data export ready email", "export_id", export.ID, "error", err)
+	}
+
+	return nil
+}
+
+// generate builds and stores the archive and completes the export.
+// It returns the email address of the user and the download token.
+func (uc *GenerateDataExportUseCase) generate(ctx context.Context, export *domain.DataExport) (string, string, error) {
+	data, err := uc.personalDataRepository.Collect(ctx, export.UserID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if data.User.IsDeleted() {
+		return "", "", ErrAccountPendingDeletion
+	}
+
+	archive, err := buildDataExportArchive(data)
+	if err != nil {
+		return "", "", err
+	}
+
+	export.StorageKey = fmt.Sprintf("data-export-%d.zip", export.ID)
+	if err := uc.dataExportStorage.Save(ctx, export.StorageKey, archive); err != nil {
+		return "", "", err
+	}
+
+	token, err := uc.dataExportRepository.Generate()
+	if err != nil {
+		return "", "", err
+	}
+
+	expiresAt := time.Now().Add(uc.ttl)
+	export.TokenHash = uc.dataExportRepository.Hash(token)
+	export.SizeBytes = int64(len(archive))
+	export.ExpiresAt = &expiresAt
+	if err := uc.dataExportRepository.Complete(ctx, export); err != nil {
+		return "", "", err
+	}
+
+	return data.User.Email, token, nil
+}
+
+// buildDataExportArchive zips the personal data as an indented JSON document
+func buildDataExportArchive(data *domain.PersonalData) ([]byte, error) {
+	document, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	file, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     DataExportFileName,
+		Method:   zip.Deflate,
+		Modified: data.ExportedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Write(document); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// GetDataExportUseCase represents the use case for checking the status of a data export
+type GetDataExportUseCase struct {
+	dataExportRepository DataExportRepository
+}
+
+// NewGetDataExportUseCase creates a new GetDataExportUseCase object
+func NewGetDataExportUseCase(dataExportRepository DataExportRepository) *GetDataExportUseCase {
+	return &GetDataExportUseCase{dataExportRepository: dataExportRepository}
+}
+
+// Execute returns the data export, if it belongs to the user
+func (uc *GetDataExportUseCase) Execute(ctx context.Context, userID int64, exportID int64) (*domain.DataExport, error) {
+	export, err := uc.dataExportRepository.FindByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDataExportNotFound
+		}
+
+		return nil, err
+	}
+
+	// Don't reveal that other users' exports exist
+	if export.UserID != userID {
+		return nil, ErrDataExportNotFound
+	}
+
+	return export, nil
+}
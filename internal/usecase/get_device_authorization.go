@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// GetDeviceAuthorizationUseCase represents the use case for looking up a device authorization by its user code
+type GetDeviceAuthorizationUseCase struct {
+	deviceAuthorizationRepository DeviceAuthorizationRepository
+}
+
+// NewGetDeviceAuthorizationUseCase creates a new GetDeviceAuthorizationUseCase object
+func NewGetDeviceAuthorizationUseCase(deviceAuthorizationRepository DeviceAuthorizationRepository) *GetDeviceAuthorizationUseCase {
+	return &GetDeviceAuthorizationUseCase{deviceAuthorizationRepository: deviceAuthorizationRepository}
+}
+
+// Execute returns the pending device authorization so the user can review the client and scope before approving it
+func (uc *GetDeviceAuthorizationUseCase) Execute(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+	normalized := domain.NormalizeUserCode(userCode)
+	if normalized == "" {
+		return nil, ErrInvalidUserCode
+	}
+
+	authorization, err := uc.deviceAuthorizationRepository.FindPendingByUserCode(ctx, uc.deviceAuthorizationRepository.Hash(normalized))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidUserCode
+		}
+
+		return nil, err
+	}
+
+	return authorization, nil
+}
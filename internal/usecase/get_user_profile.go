@@ -29,5 +29,8 @@ func (uc *GetUserProfileUseCase) Execute(ctx context.Context, userID int64) (*do
 		return nil, err
 	}
 
+	// The password hash never leaves the service
+	user.Password = ""
+
 	return user, nil
 }
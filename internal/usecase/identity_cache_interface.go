@@ -0,0 +1,10 @@
+package usecase
+
+import "time"
+
+// IdentityCache represents a short-lived cache of validated credentials, keyed by credential hash
+type IdentityCache interface {
+	Get(key string) (*ForwardAuthIdentity, bool)
+	// Set caches the identity for the TTL of the cache, but never past expiresAt unless it is zero.
+	Set(key string, identity *ForwardAuthIdentity, expiresAt time.Time)
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// ImpersonationRepository represents the impersonation repository interface
+type ImpersonationRepository interface {
+	Save(ctx context.Context, impersonation *domain.Impersonation) error
+	FindByID(ctx context.Context, id int64) (*domain.Impersonation, error)
+	// End marks the impersonation as ended, it returns false when it had already ended.
+	End(ctx context.Context, id int64) (bool, error)
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Token type hints defined by RFC 7009 and RFC 7662
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+// TokenIntrospection represents the state of a token as described by RFC 7662
+type TokenIntrospection struct {
+	Active    bool
+	Scope     string
+	ClientID  string
+	Subject   string
+	TokenType string
+	ExpiresAt int64
+	IssuedAt  int64
+	// Audience and Actor are set on tokens issued by the token exchange grant
+	Audience []string
+	Actor    map[string]any
+}
+
+// IntrospectTokenUseCase represents the use case for resource servers introspecting a token
+type IntrospectTokenUseCase struct {
+	authenticateClientUseCase        *AuthenticateClientUseCase
+	tokenParser                      TokenParser
+	rememberRepository               RememberTokenRepository
+	personalAccessTokenRepository    PersonalAccessTokenRepository
+	impersonationRepository          ImpersonationRepository
+	checkUserStatusUseCase           *CheckUserStatusUseCase
+	checkServiceAccountStatusUseCase *CheckServiceAccountStatusUseCase
+}
+
+// NewIntrospectTokenUseCase creates a new IntrospectTokenUseCase object
+func NewIntrospectTokenUseCase(
+	authenticateClientUseCase *AuthenticateClientUseCase,
+	tokenParser TokenParser,
+	rememberRepository RememberTokenRepository,
+	personalAccessTokenRepository PersonalAccessTokenRepository,
+	impersonationRepository ImpersonationRepository,
+	checkUserStatusUseCase *CheckUserStatusUseCase,
+	checkServiceAccountStatusUseCase *CheckServiceAccountStatusUseCase,
+) *IntrospectTokenUseCase {
+	return &IntrospectTokenUseCase{
+		authenticateClientUseCase:        authenticateClientUseCase,
+		tokenParser:                      tokenParser,
+		rememberRepository:               rememberRepository,
+		personalAccessTokenRepository:    personalAccessTokenRepository,
+		impersonationRepository:          impersonationRepository,
+		checkUserStatusUseCase:           checkUserStatusUseCase,
+		checkServiceAccountStatusUseCase: checkServiceAccountStatusUseCase,
+	}
+}
+
+// Execute authenticates the calling client, which must hold the introspection scope, and describes the token,
+// which can be a JWT access token, a remember token or a personal access token. Unknown, expired or revoked tokens
+// are reported as inactive, as are tokens of blocked or deleted users and of ended impersonations.
+func (uc *IntrospectTokenUseCase) Execute(ctx context.Context, credentials ClientCredentials, token string, tokenTypeHint string) (*TokenIntrospection, error) {
+	account, err := uc.authenticateClientUseCase.Execute(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(account.Scopes, domain.ScopeTokenIntrospect) {
+		return nil, ErrUnauthorizedClient
+	}
+
+	if token == "" {
+		return &TokenIntrospection{}, nil
+	}
+
+	if strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+		return uc.introspectPersonalAccessToken(ctx, token)
+	}
+
+	// The hint only changes the lookup order
+	if tokenTypeHint == TokenTypeHintRefreshToken {
+		introspection, err := uc.introspectRememberToken(ctx, token)
+		if err != nil || introspection.Active {
+			return introspection, err
+		}
+
+		return uc.introspectAccessToken(ctx, token)
+	}
+
+	introspection, err := uc.introspectAccessToken(ctx, token)
+	if err != nil || introspection.Active {
+		return introspection, err
+	}
+
+	return uc.introspectRememberToken(ctx, token)
+}
+
+// introspectAccessToken describes a JWT issued by the token generator
+func (uc *IntrospectTokenUseCase) introspectAccessToken(ctx context.Context, token string) (*TokenIntrospection, error) {
+	claims, err := uc.tokenParser.ParseToken(token)
+	if err != nil {
+		return &TokenIntrospection{}, nil
+	}
+
+	// Tokens issued for other purposes, like email verification, aren't access tokens
+	purpose, _ := claims["purpose"].(string)
+	if purpose != "access_token" && purpose != "refresh_token" {
+		return &TokenIntrospection{}, nil
+	}
+
+	active, err := uc.isAccessTokenSubjectActive(ctx, claims)
+	if err != nil || !active {
+		return &TokenIntrospection{}, err
+	}
+
+	introspection := &TokenIntrospection{
+		Active:    true,
+		Subject:   claimString(claims["sub"]),
+		TokenType: "Bearer",
+	}
+	introspection.Scope, _ = claims["scope"].(string)
+	introspection.ClientID, _ = claims["client_id"].(string)
+	introspection.Audience = claimStrings(claims["aud"])
+	introspection.Actor, _ = claims["act"].(map[string]any)
+
+	// JWT stores numbers as float64
+	if exp, ok := claims["exp"].(float64); ok {
+		introspection.ExpiresAt = int64(exp)
+	}
+	if iat, ok := claims["iat"].(float64); ok {
+		introspection.IssuedAt = int64(iat)
+	}
+
+	return introspection, nil
+}
+
+// isAccessTokenSubjectActive checks that the service account or user the access token was issued to is still active,
+// and for impersonation tokens that the impersonation hasn't ended
+func (uc *IntrospectTokenUseCase) isAccessTokenSubjectActive(ctx context.Context, claims map[string]any) (bool, error) {
+	// JWT stores numbers as float64
+	if claims["principal_type"] == domain.PrincipalTypeService {
+		serviceAccountID, ok := claims["service_account_id"].(float64)
+		if !ok {
+			return false, nil
+		}
+
+		_, err := uc.checkServiceAccountStatusUseCase.Execute(ctx, int64(serviceAccountID))
+		return isActiveStatus(err)
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return false, nil
+	}
+
+	if active, err := uc.isUserActive(ctx, int64(userID)); err != nil || !active {
+		return false, err
+	}
+
+	impersonationID, ok := claims["impersonation_id"].(float64)
+	if !ok {
+		return true, nil
+	}
+
+	impersonation, err := uc.impersonationRepository.FindByID(ctx, int64(impersonationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return impersonation.IsActive() && impersonation.UserID == int64(userID), nil
+}
+
+// isUserActive reports whether the user is neither blocked nor deleted
+func (uc *IntrospectTokenUseCase) isUserActive(ctx context.Context, userID int64) (bool, error) {
+	_, err := uc.checkUserStatusUseCase.Execute(ctx, userID)
+	return isActiveStatus(err)
+}
+
+// isActiveStatus turns the error of a status check into whether the principal is active
+func isActiveStatus(err error) (bool, error) {
+	if errors.Is(err, ErrAccountUnavailable) || errors.Is(err, ErrUserUnauthorized) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// introspectRememberToken describes a remember token used to refresh access tokens
+func (uc *IntrospectTokenUseCase) introspectRememberToken(ctx context.Context, token string) (*TokenIntrospection, error) {
+	rememberToken, err := uc.rememberRepository.FindByToken(ctx, uc.rememberRepository.Hash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &TokenIntrospection{}, nil
+		}
+
+		return nil, err
+	}
+
+	if active, err := uc.isUserActive(ctx, rememberToken.UserID); err != nil || !active {
+		return &TokenIntrospection{}, err
+	}
+
+	return &TokenIntrospection{
+		Active:    true,
+		Subject:   strconv.FormatInt(rememberToken.UserID, 10),
+		TokenType: TokenTypeHintRefreshToken,
+		ExpiresAt: rememberToken.ExpiresAt.Unix(),
+	}, nil
+}
+
+// introspectPersonalAccessToken describes a personal access token
+func (uc *IntrospectTokenUseCase) introspectPersonalAccessToken(ctx context.Context, token string) (*TokenIntrospection, error) {
+	personalAccessToken, err := uc.personalAccessTokenRepository.FindByToken(ctx, uc.personalAccessTokenRepository.Hash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &TokenIntrospection{}, nil
+		}
+
+		return nil, err
+	}
+
+	if active, err := uc.isUserActive(ctx, personalAccessToken.UserID); err != nil || !active {
+		return &TokenIntrospection{}, err
+	}
+
+	return &TokenIntrospection{
+		Active:    true,
+		Scope:     strings.Join(personalAccessToken.Scopes, " "),
+		Subject:   strconv.FormatInt(personalAccessToken.UserID, 10),
+		TokenType: "Bearer",
+		ExpiresAt: personalAccessToken.ExpiresAt.Unix(),
+		IssuedAt:  personalAccessToken.CreatedAt.Unix(),
+	}, nil
+}
+
+// claimString formats a string or numeric claim
+func claimString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,6 @@
+package usecase
+
+// IPBlocklist interface for the IP address ranges known to be used for attacks
+type IPBlocklist interface {
+	Contains(ipAddress string) bool
+}
@@ -0,0 +1,9 @@
+package usecase
+
+import "auth/internal/domain"
+
+// IPLocator interface for looking up the approximate location of an IP address
+type IPLocator interface {
+	// Locate returns the location of the IP address, or nil when it isn't in the database.
+	Locate(ipAddress string) (*domain.GeoLocation, error)
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+)
+
+// KnownDeviceRepository represents the repository of the devices users signed in from
+type KnownDeviceRepository interface {
+	// Hash hashes a device ID using SHA-256.
+	Hash(deviceID string) string
+	// HasAny reports whether any device has been recorded for the user.
+	HasAny(ctx context.Context, userID int64) (bool, error)
+	// HasSimilar reports whether the user signed in from the IP address with the same user agent family before.
+	HasSimilar(ctx context.Context, userID int64, userAgentFamily string, ipAddress string) (bool, error)
+	// Upsert records a sign-in from the device and reports whether the device was seen for the first time.
+	Upsert(ctx context.Context, device *domain.KnownDevice) (bool, error)
+	Delete(ctx context.Context, userID int64, deviceID int64) error
+	// MarkSecureAccountTokenUsed records the hash of a "secure my account" token and reports whether it was unused.
+	MarkSecureAccountTokenUsed(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) (bool, error)
+}
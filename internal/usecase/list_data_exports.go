@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// ListDataExportsUseCase represents the use case for listing the data exports of a user
+type ListDataExportsUseCase struct {
+	dataExportRepository DataExportRepository
+}
+
+// NewListDataExportsUseCase creates a new ListDataExportsUseCase object
+func NewListDataExportsUseCase(dataExportRepository DataExportRepository) *ListDataExportsUseCase {
+	return &ListDataExportsUseCase{dataExportRepository: dataExportRepository}
+}
+
+// Execute returns the data exports of the user, most recent first
+func (uc *ListDataExportsUseCase) Execute(ctx context.Context, userID int64) ([]domain.DataExport, error) {
+	return uc.dataExportRepository.FindByUserID(ctx, userID)
+}
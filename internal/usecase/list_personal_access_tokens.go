@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// ListPersonalAccessTokensUseCase represents the list personal access tokens use case object
+type ListPersonalAccessTokensUseCase struct {
+	tokenRepository PersonalAccessTokenRepository
+}
+
+// NewListPersonalAccessTokensUseCase creates a new ListPersonalAccessTokensUseCase object
+func NewListPersonalAccessTokensUseCase(tokenRepository PersonalAccessTokenRepository) *ListPersonalAccessTokensUseCase {
+	return &ListPersonalAccessTokensUseCase{tokenRepository: tokenRepository}
+}
+
+// Execute lists the personal access tokens owned by the user
+func (uc *ListPersonalAccessTokensUseCase) Execute(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error) {
+	return uc.tokenRepository.FindByUserID(ctx, userID)
+}
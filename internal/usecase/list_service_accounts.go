@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// ListServiceAccountsUseCase represents the list service accounts use case object
+type ListServiceAccountsUseCase struct {
+	serviceAccountRepository ServiceAccountRepository
+}
+
+// NewListServiceAccountsUseCase creates a new ListServiceAccountsUseCase object
+func NewListServiceAccountsUseCase(serviceAccountRepository ServiceAccountRepository) *ListServiceAccountsUseCase {
+	return &ListServiceAccountsUseCase{serviceAccountRepository: serviceAccountRepository}
+}
+
+// Execute returns the service accounts owned by the user
+func (uc *ListServiceAccountsUseCase) Execute(ctx context.Context, ownerUserID int64) ([]domain.ServiceAccount, error) {
+	return uc.serviceAccountRepository.FindByOwner(ctx, ownerUserID)
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// ListTrustedDevicesUseCase represents the list trusted devices use case object
+type ListTrustedDevicesUseCase struct {
+	trustedDeviceRepository TrustedDeviceRepository
+}
+
+// NewListTrustedDevicesUseCase creates a new ListTrustedDevicesUseCase object
+func NewListTrustedDevicesUseCase(trustedDeviceRepository TrustedDeviceRepository) *ListTrustedDevicesUseCase {
+	return &ListTrustedDevicesUseCase{trustedDeviceRepository: trustedDeviceRepository}
+}
+
+// Execute lists the devices of the user that skip the second factor
+func (uc *ListTrustedDevicesUseCase) Execute(ctx context.Context, userID int64) ([]domain.TrustedDevice, error) {
+	return uc.trustedDeviceRepository.FindByUserID(ctx, userID)
+}
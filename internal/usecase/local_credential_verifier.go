@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+)
+
+// LocalCredentialVerifier verifies credentials against the password hashes stored in the user repository
+type LocalCredentialVerifier struct {
+	logger            *slog.Logger
+	userRepository    UserRepository
+	passwordHasher    PasswordHasher
+	breachedPasswords BreachedPasswordChecker
+}
+
+// NewLocalCredentialVerifier creates a new LocalCredentialVerifier object.
+// When breachedPasswords is set, users logging in with a breached password are forced to reset it.
+func NewLocalCredentialVerifier(
+	logger *slog.Logger,
+	userRepository UserRepository,
+	passwordHasher PasswordHasher,
+	breachedPasswords BreachedPasswordChecker,
+) *LocalCredentialVerifier {
+	return &LocalCredentialVerifier{
+		logger:            logger,
+		userRepository:    userRepository,
+		passwordHasher:    passwordHasher,
+		breachedPasswords: breachedPasswords,
+	}
+}
+
+// Verify finds the user by email and compares the provided password with the stored hash.
+// Hashes made with outdated parameters are upgraded while the plain password is at hand.
+func (v *LocalCredentialVerifier) Verify(ctx context.Context, email string, password string) (*domain.User, error) {
+	// find user by email
+	user, err := v.userRepository.FindByEmail(ctx, email)
+	if err != nil {
+		// Don't return user not found error to prevent email enumeration attack
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidCredentials
+		}
+
+		return nil, err
+	}
+
+	// Users provisioned by an external directory have no usable local password
+	if user.AuthSource != domain.AuthSourceLocal {
+		return nil, ErrInvalidCredentials
+	}
+
+	// compare the provided password with the stored hash
+	valid, err := v.passwordHasher.Verify(password, user.Password)
+	if err != nil {
+		v.logger.Warn("Failed to verify password hash", "user_id", user.ID, "error", err)
+		return nil, ErrInvalidCredentials
+	}
+
+	if !valid {
+		return nil, ErrInvalidCredentials
+	}
+
+	if !user.PasswordResetRequired && v.isBreached(user, password) {
+		if err := v.userRepository.RequirePasswordReset(ctx, user.ID); err != nil {
+			return nil, err
+		}
+
+		v.logger.Info("Required password reset after breached password login", "user_id", user.ID)
+		user.PasswordResetRequired = true
+	}
+
+	// The password is correct, but the user has to go through the password reset flow first
+	if user.PasswordResetRequired {
+		return nil, ErrPasswordResetRequired
+	}
+
+	if v.passwordHasher.NeedsRehash(user.Password) {
+		v.rehash(ctx, user, password)
+	}
+
+	return user, nil
+}
+
+// isBreached reports whether the password is in the breached password list, a failed lookup lets the login through
+func (v *LocalCredentialVerifier) isBreached(user *domain.User, password string) bool {
+	if v.breachedPasswords == nil {
+		return false
+	}
+
+	breached, err := v.breachedPasswords.IsBreached(password)
+	if err != nil {
+		v.logger.Warn("Failed to check breached password list", "user_id", user.ID, "error", err)
+		return false
+	}
+
+	return breached
+}
+
+// rehash stores a hash made with the current parameters. A failure doesn't prevent the login.
+func (v *LocalCredentialVerifier) rehash(ctx context.Context, user *domain.User, password string) {
+	hashedPassword, err := v.passwordHasher.Hash(password)
+	if err != nil {
+		v.logger.Warn("Failed to rehash password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	if err := v.userRepository.RehashPassword(ctx, user.ID, hashedPassword); err != nil {
+		v.logger.Warn("Failed to store rehashed password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	user.Password = hashedPassword
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+)
+
+// LoginEventRepository represents the login history repository interface
+type LoginEventRepository interface {
+	Save(ctx context.Context, event *domain.LoginEvent) error
+	// FindLastSucceeded finds the latest login that signed the user in, it returns sql.ErrNoRows when there is none.
+	FindLastSucceeded(ctx context.Context, userID int64) (*domain.LoginEvent, error)
+	// FindSucceededCountries returns the countries the user signed in from.
+	FindSucceededCountries(ctx context.Context, userID int64) ([]string, error)
+	// CountSince counts the logins of the user since the given time, whatever their outcome.
+	CountSince(ctx context.Context, userID int64, since time.Time) (int, error)
+	// MarkSucceeded records that the login signed the user in after a second factor.
+	MarkSucceeded(ctx context.Context, eventID int64) error
+}
@@ -9,7 +9,12 @@ import (
 type LoginOTPRepository interface {
 	Generate(length int) (string, error)
 	Hash(code string) string
+	// Save replaces the code of the email, the wrong attempts of the replaced code are kept.
 	Save(ctx context.Context, email string, codeHash string, duration time.Duration) error
-	IsCodeExist(ctx context.Context, codeHash string) error
+	// IncrementAttempts spends an attempt on the live code of the email.
+	// It returns sql.ErrNoRows when the email has no live code or it has run out of attempts.
+	IncrementAttempts(ctx context.Context, email string, maxAttempts int) error
+	// DeleteByHash consumes the code of the email, it returns sql.ErrNoRows when the code doesn't match.
+	DeleteByHash(ctx context.Context, email string, codeHash string) error
 	Delete(ctx context.Context, email string) error
 }
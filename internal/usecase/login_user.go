@@ -1,19 +1,23 @@
 package usecase
 
 import (
+	"auth/internal/domain"
 	"context"
-	"database/sql"
-	"errors"
 	"time"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
+// accessTokenDuration is the lifetime of the access tokens issued by a login
+const accessTokenDuration = time.Hour * 24
+
 // LoginUserUseCase represents the login user use case object
 type LoginUserUseCase struct {
 	userRepository     UserRepository
+	credentialVerifier CredentialVerifier
 	tokenGenerator     TokenGenerator
 	rememberRepository RememberTokenRepository
+	rotationPolicy     PasswordRotationPolicy
+	trackDeviceUseCase *TrackLoginDeviceUseCase
+	assessRiskUseCase  *AssessLoginRiskUseCase
 	rememberMeHours    time.Duration
 }
 
@@ -24,47 +28,69 @@ type LoginToken struct {
 }
 
 // NewLoginUserUseCase creates a new login user use case object
-func NewLoginUserUseCase(userRepository UserRepository, tokenGenerator TokenGenerator, rememberRepository RememberTokenRepository) *LoginUserUseCase {
+func NewLoginUserUseCase(
+	userRepository UserRepository,
+	credentialVerifier CredentialVerifier,
+	tokenGenerator TokenGenerator,
+	rememberRepository RememberTokenRepository,
+	rotationPolicy PasswordRotationPolicy,
+	trackDeviceUseCase *TrackLoginDeviceUseCase,
+	assessRiskUseCase *AssessLoginRiskUseCase,
+) *LoginUserUseCase {
 	return &LoginUserUseCase{
 		userRepository:     userRepository,
+		credentialVerifier: credentialVerifier,
 		tokenGenerator:     tokenGenerator,
 		rememberRepository: rememberRepository,
+		rotationPolicy:     rotationPolicy,
+		trackDeviceUseCase: trackDeviceUseCase,
+		assessRiskUseCase:  assessRiskUseCase,
 		rememberMeHours:    time.Hour * 24 * 30,
 	}
 }
 
 // Execute authenticates a user by checking their credentials and then generates tokens for them
 func (uc *LoginUserUseCase) Execute(ctx context.Context, email string, password string, rememberMe bool) (*LoginToken, error) {
-	// find user by email
-	user, err := uc.userRepository.FindByEmail(ctx, email)
+	// verify the credentials against the configured authentication backend
+	user, err := uc.credentialVerifier.Verify(ctx, email, password)
 	if err != nil {
-		// Don't return user not found error to prevent email enumeration attack
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrInvalidCredentials
-		}
-
 		return nil, err
 	}
 
-	// compare the provided password with the stored hash
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-	if err != nil {
-		return nil, ErrInvalidCredentials
+	if err := authorizeLogin(ctx, uc.assessRiskUseCase, uc.tokenGenerator, uc.rotationPolicy, user, rememberMe, false); err != nil {
+		return nil, err
 	}
 
-	return uc.GenerateToken(ctx, user.ID, rememberMe)
+	return uc.GenerateToken(ctx, user.ID, rememberMe, []string{domain.AMRPassword})
 }
 
 // GenerateToken Creates a new JWT and optionally a remember me token for a given user ID
 // This method is separate from Execute so it can be called directly after other authentication flows, like email verification.
-func (uc *LoginUserUseCase) GenerateToken(ctx context.Context, userID int64, rememberMe bool) (*LoginToken, error) {
+// amr lists the authentication methods the user just completed, they are recorded in the token with the time of login.
+// Logins must pass AssessLoginRiskUseCase first, it enforces MFA and the risk policy.
+func (uc *LoginUserUseCase) GenerateToken(ctx context.Context, userID int64, rememberMe bool, amr []string) (*LoginToken, error) {
+	// Every flow ending in a login passes through here, blocked accounts get no tokens
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBlocked() {
+		return nil, ErrAccountUnavailable
+	}
+
 	// generate access token for authenticated user
-	token, err := uc.tokenGenerator.GenerateToken(userID, "access_token")
+	token, err := generateAccessToken(uc.tokenGenerator, userID, amr)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := uc.userRepository.UpdateLastLogin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	uc.trackDeviceUseCase.Execute(ctx, user)
+
 	result := &LoginToken{AccessToken: token}
 
 	if rememberMe {
@@ -74,7 +100,7 @@ func (uc *LoginUserUseCase) GenerateToken(ctx context.Context, userID int64, rem
 			return nil, err
 		}
 		tokenHash := uc.rememberRepository.Hash(rawToken)
-		err = uc.rememberRepository.Save(ctx, userID, tokenHash, uc.rememberMeHours)
+		err = uc.rememberRepository.Save(ctx, userID, "", tokenHash, uc.rememberMeHours)
 		if err != nil {
 			return nil, err
 		}
@@ -82,3 +108,42 @@ func (uc *LoginUserUseCase) GenerateToken(ctx context.Context, userID int64, rem
 	}
 	return result, nil
 }
+
+// authorizeLogin applies the checks every login goes through once the user proved who they are.
+// Blocked accounts are refused. Risky logins are refused or need a second factor before any token,
+// restricted ones included, is issued. A deleted account or an expired password only gets a restricted token.
+// assessRisk is nil when the second factor has just been completed.
+func authorizeLogin(
+	ctx context.Context,
+	assessRisk *AssessLoginRiskUseCase,
+	tokenGenerator TokenGenerator,
+	rotationPolicy PasswordRotationPolicy,
+	user *domain.User,
+	rememberMe bool,
+	useSession bool,
+) error {
+	// Administrators decide why an account is blocked, the user only learns that it is unavailable
+	if user.IsBlocked() {
+		return ErrAccountUnavailable
+	}
+
+	if assessRisk != nil {
+		if err := assessRisk.Execute(ctx, user, rememberMe, useSession); err != nil {
+			return err
+		}
+	}
+
+	if err := requireAccountRestore(tokenGenerator, user); err != nil {
+		return err
+	}
+
+	return requirePasswordChange(tokenGenerator, rotationPolicy, user)
+}
+
+// generateAccessToken issues an access token recording that the user just authenticated with amr
+func generateAccessToken(tokenGenerator TokenGenerator, userID int64, amr []string) (string, error) {
+	return tokenGenerator.GenerateTokenWithClaims(userID, "access_token", accessTokenDuration, map[string]any{
+		"auth_time": time.Now().Unix(),
+		"amr":       amr,
+	})
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// LoginUserSessionUseCase represents the use case for logging in with a server-side session instead of a JWT
+type LoginUserSessionUseCase struct {
+	credentialVerifier   CredentialVerifier
+	tokenGenerator       TokenGenerator
+	rotationPolicy       PasswordRotationPolicy
+	createSessionUseCase *CreateSessionUseCase
+	assessRiskUseCase    *AssessLoginRiskUseCase
+}
+
+// NewLoginUserSessionUseCase creates a new LoginUserSessionUseCase object
+func NewLoginUserSessionUseCase(
+	credentialVerifier CredentialVerifier,
+	tokenGenerator TokenGenerator,
+	rotationPolicy PasswordRotationPolicy,
+	createSessionUseCase *CreateSessionUseCase,
+	assessRiskUseCase *AssessLoginRiskUseCase,
+) *LoginUserSessionUseCase {
+	return &LoginUserSessionUseCase{
+		credentialVerifier:   credentialVerifier,
+		tokenGenerator:       tokenGenerator,
+		rotationPolicy:       rotationPolicy,
+		createSessionUseCase: createSessionUseCase,
+		assessRiskUseCase:    assessRiskUseCase,
+	}
+}
+
+// Execute verifies the credentials and starts a session, which is persistent when the user asked to be remembered
+func (uc *LoginUserSessionUseCase) Execute(ctx context.Context, email string, password string, rememberMe bool) (*SessionToken, error) {
+	user, err := uc.credentialVerifier.Verify(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeLogin(ctx, uc.assessRiskUseCase, uc.tokenGenerator, uc.rotationPolicy, user, rememberMe, true); err != nil {
+		return nil, err
+	}
+
+	return uc.createSessionUseCase.Execute(ctx, user.ID, rememberMe, []string{domain.AMRPassword})
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// LogoutUseCase represents the logout use case object
+type LogoutUseCase struct {
+	sessionRepository  SessionRepository
+	rememberRepository RememberTokenRepository
+}
+
+// NewLogoutUseCase creates a new LogoutUseCase object
+func NewLogoutUseCase(sessionRepository SessionRepository, rememberRepository RememberTokenRepository) *LogoutUseCase {
+	return &LogoutUseCase{
+		sessionRepository:  sessionRepository,
+		rememberRepository: rememberRepository,
+	}
+}
+
+// Execute ends the session and deletes the remember token presented by the client, either can be empty
+func (uc *LogoutUseCase) Execute(ctx context.Context, sessionToken string, rememberToken string) error {
+	if sessionToken != "" {
+		session, err := uc.sessionRepository.FindByToken(ctx, uc.sessionRepository.Hash(sessionToken))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if session != nil {
+			if err := uc.sessionRepository.Delete(ctx, session.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if rememberToken != "" {
+		token, err := uc.rememberRepository.FindByToken(ctx, uc.rememberRepository.Hash(rememberToken))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if token != nil {
+			if err := uc.rememberRepository.Delete(ctx, token.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
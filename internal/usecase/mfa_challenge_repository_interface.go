@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// MFAChallengeRepository represents the MFA challenge repository interface
+type MFAChallengeRepository interface {
+	// Generate creates a random numeric code of the given length.
+	Generate(length int) (string, error)
+	// Hash hashes a code using SHA-256.
+	Hash(code string) string
+	// SaveIfAllowed saves the challenge unless the user already has maxOpen live challenges,
+	// it reports whether the challenge was saved.
+	SaveIfAllowed(ctx context.Context, challenge *domain.MFAChallenge, maxOpen int) (bool, error)
+	FindByID(ctx context.Context, id int64) (*domain.MFAChallenge, error)
+	// IncrementAttempts spends an attempt on the challenge.
+	// It returns sql.ErrNoRows when the challenge has expired or run out of attempts.
+	IncrementAttempts(ctx context.Context, id int64, maxAttempts int) error
+	// DeleteByHash consumes the challenge if the code matches, it returns sql.ErrNoRows otherwise.
+	DeleteByHash(ctx context.Context, id int64, codeHash string) error
+}
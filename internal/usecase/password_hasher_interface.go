@@ -0,0 +1,11 @@
+package usecase
+
+// PasswordHasher represents the password hashing interface
+type PasswordHasher interface {
+	// Hash hashes the password with the configured algorithm and parameters.
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches the encoded hash, whichever supported algorithm produced it.
+	Verify(password string, encodedHash string) (bool, error)
+	// NeedsRehash reports whether the hash was produced with an outdated algorithm, parameters or pepper.
+	NeedsRehash(encodedHash string) bool
+}
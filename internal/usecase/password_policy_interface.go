@@ -0,0 +1,8 @@
+package usecase
+
+// PasswordPolicy represents the password policy engine interface
+type PasswordPolicy interface {
+	// Check evaluates the password against the policy. User inputs such as the name and email
+	// are treated as guessable and may be rejected outright when found in the password.
+	Check(password string, userInputs ...string) *PasswordCheckResult
+}
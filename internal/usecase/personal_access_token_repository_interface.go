@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// PersonalAccessTokenRepository represents the personal access token repository interface
+type PersonalAccessTokenRepository interface {
+	// Generate creates a new secure token string carrying the personal access token prefix.
+	Generate() (string, error)
+	// Hash hashes a raw token string using SHA-256.
+	Hash(token string) string
+	// Save stores a new personal access token and fills in its ID and creation time.
+	Save(ctx context.Context, token *domain.PersonalAccessToken) error
+	// FindByToken finds the unexpired token matching the hash.
+	FindByToken(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	// FindByUserID lists the tokens owned by a user.
+	FindByUserID(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error)
+	// UpdateLastUsed records when and from where the token was last used.
+	UpdateLastUsed(ctx context.Context, tokenID int64, ip string) error
+	// Delete removes a token owned by the user.
+	Delete(ctx context.Context, userID int64, tokenID int64) error
+	// DeleteByUserID removes every token owned by the user.
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
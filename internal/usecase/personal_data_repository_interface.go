@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// PersonalDataRepository represents the repository collecting everything stored about a user
+type PersonalDataRepository interface {
+	Collect(ctx context.Context, userID int64) (*domain.PersonalData, error)
+}
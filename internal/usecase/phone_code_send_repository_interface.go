@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+// PhoneCodeSendRepository represents the log of the codes sent to phone numbers, used to throttle them
+type PhoneCodeSendRepository interface {
+	// SaveIfAllowed records a code sent to the phone number unless the number received one within minInterval,
+	// or limit codes within window, whatever the channel. Zero values disable the checks.
+	// The check and the record are atomic per phone number, it reports whether the code was recorded.
+	SaveIfAllowed(ctx context.Context, phoneNumber string, channel string, minInterval time.Duration, limit int, window time.Duration) (bool, error)
+}
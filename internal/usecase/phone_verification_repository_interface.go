@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// PhoneVerificationRepository represents the repository of the phone numbers waiting for verification, one per user
+type PhoneVerificationRepository interface {
+	// Generate creates a random numeric code of the given length.
+	Generate(length int) (string, error)
+	// Hash hashes a code using SHA-256.
+	Hash(code string) string
+	// Save replaces the pending verification of the user, the wrong attempts of a live one are kept.
+	Save(ctx context.Context, verification *domain.PhoneVerification) error
+	// IncrementAttempts spends an attempt on the pending verification of the user.
+	// It returns sql.ErrNoRows when there is none, it has expired or it has run out of attempts.
+	IncrementAttempts(ctx context.Context, userID int64, maxAttempts int) error
+	// DeleteByHash consumes the pending verification of the user if the code matches and returns it.
+	// It returns sql.ErrNoRows when the code doesn't match.
+	DeleteByHash(ctx context.Context, userID int64, codeHash string) (*domain.PhoneVerification, error)
+}
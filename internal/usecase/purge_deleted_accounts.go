@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+)
+
+// PurgeDeletedAccountsUseCase represents the use case for permanently deleting the accounts whose grace period has ended
+type PurgeDeletedAccountsUseCase struct {
+	logger          *slog.Logger
+	userRepository  UserRepository
+	taskDistributor TaskDistributor
+}
+
+// NewPurgeDeletedAccountsUseCase creates a new PurgeDeletedAccountsUseCase object
+func NewPurgeDeletedAccountsUseCase(logger *slog.Logger, userRepository UserRepository, taskDistributor TaskDistributor) *PurgeDeletedAccountsUseCase {
+	return &PurgeDeletedAccountsUseCase{
+		logger:          logger,
+		userRepository:  userRepository,
+		taskDistributor: taskDistributor,
+	}
+}
+
+// Execute purges the accounts and lets their owners know. It returns the number of purged accounts.
+func (uc *PurgeDeletedAccountsUseCase) Execute(ctx context.Context) (int, error) {
+	emails, err := uc.userRepository.PurgeDeleted(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, email := range emails {
+		if err := uc.taskDistributor.DistributeTaskSendEmailAccountDeleted(ctx, email); err != nil {
+			uc.logger.Error("Failed to distribute account deleted email", "error", err)
+		}
+	}
+
+	return len(emails), nil
+}
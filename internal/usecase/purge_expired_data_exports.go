@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+)
+
+// PurgeExpiredDataExportsUseCase represents the use case for deleting data exports once their download link has expired
+type PurgeExpiredDataExportsUseCase struct {
+	logger               *slog.Logger
+	dataExportRepository DataExportRepository
+	dataExportStorage    DataExportStorage
+}
+
+// NewPurgeExpiredDataExportsUseCase creates a new PurgeExpiredDataExportsUseCase object
+func NewPurgeExpiredDataExportsUseCase(
+	logger *slog.Logger,
+	dataExportRepository DataExportRepository,
+	dataExportStorage DataExportStorage,
+) *PurgeExpiredDataExportsUseCase {
+	return &PurgeExpiredDataExportsUseCase{
+		logger:               logger,
+		dataExportRepository: dataExportRepository,
+		dataExportStorage:    dataExportStorage,
+	}
+}
+
+// Execute deletes the expired exports and their archives. It returns the number of deleted archives.
+func (uc *PurgeExpiredDataExportsUseCase) Execute(ctx context.Context) (int, error) {
+	keys, err := uc.dataExportRepository.DeleteExpired(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		// The export is already gone, a leftover archive can't be downloaded anymore
+		if err := uc.dataExportStorage.Delete(ctx, key); err != nil {
+			uc.logger.Error("Failed to delete data export archive", "key", key, "error", err)
+		}
+	}
+
+	return len(keys), nil
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+)
+
+// ReauthenticateResult represents the credential upgraded by a reauthentication.
+// Token clients get a new access token, session users get their session token rotated.
+type ReauthenticateResult struct {
+	AccessToken  string
+	Session      *domain.Session
+	SessionToken string
+	AuthTime     time.Time
+}
+
+// ReauthenticateUseCase represents the use case for proving the identity of a logged-in user again before a sensitive action
+type ReauthenticateUseCase struct {
+	userRepository     UserRepository
+	credentialVerifier CredentialVerifier
+	tokenGenerator     TokenGenerator
+	sessionRepository  SessionRepository
+}
+
+// NewReauthenticateUseCase creates a new ReauthenticateUseCase object
+func NewReauthenticateUseCase(
+	userRepository UserRepository,
+	credentialVerifier CredentialVerifier,
+	tokenGenerator TokenGenerator,
+	sessionRepository SessionRepository,
+) *ReauthenticateUseCase {
+	return &ReauthenticateUseCase{
+		userRepository:     userRepository,
+		credentialVerifier: credentialVerifier,
+		tokenGenerator:     tokenGenerator,
+		sessionRepository:  sessionRepository,
+	}
+}
+
+// Execute verifies the password of the user and records a fresh authentication in their credential, without a new login.
+// Scoped credentials can't be upgraded, they would gain full access.
+func (uc *ReauthenticateUseCase) Execute(ctx context.Context, principal *domain.Principal, password string) (*ReauthenticateResult, error) {
+	if !principal.IsInteractive() {
+		return nil, ErrReauthenticationNotAllowed
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, principal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := uc.credentialVerifier.Verify(ctx, user.Email, password)
+	if err != nil {
+		return nil, err
+	}
+	if verified.ID != user.ID {
+		return nil, ErrInvalidCredentials
+	}
+
+	amr := []string{domain.AMRPassword}
+
+	if principal.SessionID == 0 {
+		token, err := generateAccessToken(uc.tokenGenerator, user.ID, amr)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ReauthenticateResult{AccessToken: token, AuthTime: time.Now()}, nil
+	}
+
+	// Rotating the token keeps a session token captured before the reauthentication from being upgraded with it
+	rawToken, err := uc.sessionRepository.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessionRepository.Reauthenticate(ctx, principal.SessionID, uc.sessionRepository.Hash(rawToken), amr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReauthenticateResult{Session: session, SessionToken: rawToken, AuthTime: session.AuthTime}, nil
+}
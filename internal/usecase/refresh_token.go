@@ -12,6 +12,7 @@ type RefreshTokenUseCase struct {
 	userRepository          UserRepository
 	rememberTokenRepository RememberTokenRepository
 	tokenGenerator          TokenGenerator
+	rotationPolicy          PasswordRotationPolicy
 }
 
 // RefreshResult Hold the output of a successful token refresh
@@ -25,11 +26,13 @@ func NewRefreshTokenUseCase(
 	userRepository UserRepository,
 	rememberTokenRepository RememberTokenRepository,
 	tokenGenerator TokenGenerator,
+	rotationPolicy PasswordRotationPolicy,
 ) *RefreshTokenUseCase {
 	return &RefreshTokenUseCase{
 		userRepository:          userRepository,
 		rememberTokenRepository: rememberTokenRepository,
 		tokenGenerator:          tokenGenerator,
+		rotationPolicy:          rotationPolicy,
 	}
 }
 
@@ -56,12 +59,21 @@ func (uc *RefreshTokenUseCase) Execute(ctx context.Context, rawRememberToken str
 	}
 
 	// Verify the user associated with the token still exists
-	_, err = uc.userRepository.FindByID(ctx, oldToken.UserID)
+	user, err := uc.userRepository.FindByID(ctx, oldToken.UserID)
 
 	if err != nil {
 		return nil, ErrInvalidCredentials
 	}
 
+	if user.IsBlocked() {
+		return nil, ErrAccountUnavailable
+	}
+
+	// The remember token is spent, the user signs in again with a new password
+	if err := requirePasswordChange(uc.tokenGenerator, uc.rotationPolicy, user); err != nil {
+		return nil, err
+	}
+
 	// Issue a new JWT for the user
 	newJWT, err := uc.tokenGenerator.GenerateToken(oldToken.UserID, "refresh_token")
 
@@ -79,7 +91,7 @@ func (uc *RefreshTokenUseCase) Execute(ctx context.Context, rawRememberToken str
 
 	// Use the same duration as the login use case
 	rememberTokenDuration := time.Hour * 24 * 30
-	if err := uc.rememberTokenRepository.Save(ctx, oldToken.UserID, newHash, rememberTokenDuration); err != nil {
+	if err := uc.rememberTokenRepository.Save(ctx, oldToken.UserID, oldToken.ClientID, newHash, rememberTokenDuration); err != nil {
 		return nil, err
 	}
 
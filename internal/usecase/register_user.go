@@ -6,23 +6,27 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 // RegisterUserUseCase represents the register user use case
 type RegisterUserUseCase struct {
 	userRepository                   UserRepository
+	passwordHasher                   PasswordHasher
+	passwordPolicy                   PasswordPolicy
 	sendEmailVerificationLinkUseCase *SendEmailVerificationLinkUseCase
 }
 
 // NewRegisterUserUseCase creates a new register user use case
 func NewRegisterUserUseCase(
 	userRepository UserRepository,
+	passwordHasher PasswordHasher,
+	passwordPolicy PasswordPolicy,
 	sendEmailVerificationLinkUC *SendEmailVerificationLinkUseCase,
 ) *RegisterUserUseCase {
 	return &RegisterUserUseCase{
 		userRepository:                   userRepository,
+		passwordHasher:                   passwordHasher,
+		passwordPolicy:                   passwordPolicy,
 		sendEmailVerificationLinkUseCase: sendEmailVerificationLinkUC,
 	}
 }
@@ -48,8 +52,8 @@ func (uc *RegisterUserUseCase) Execute(ctx context.Context, name, email, passwor
 	}
 
 	// password validation
-	if len(password) < 8 {
-		return nil, ErrPasswordTooShort
+	if err := validatePassword(uc.passwordPolicy, password, name, email); err != nil {
+		return nil, err
 	}
 
 	// Check if user already exist
@@ -65,7 +69,7 @@ func (uc *RegisterUserUseCase) Execute(ctx context.Context, name, email, passwor
 	}
 
 	// Hash the password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := uc.passwordHasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +78,7 @@ func (uc *RegisterUserUseCase) Execute(ctx context.Context, name, email, passwor
 	user := &domain.User{
 		Name:     name,
 		Email:    email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 	}
 
 	if err := uc.userRepository.Save(ctx, user); err != nil {
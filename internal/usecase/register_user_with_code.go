@@ -10,12 +10,13 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // RegisterUserWithCodeUseCase represents the RegisterUserWithCode use case object
 type RegisterUserWithCodeUseCase struct {
 	userRepository    UserRepository
+	passwordHasher    PasswordHasher
+	passwordPolicy    PasswordPolicy
 	verifyCodeUseCase *VerifyCodeUseCase
 	loginUseCase      *LoginUserUseCase
 }
@@ -32,11 +33,15 @@ type Claims struct {
 // NewRegisterUserWithCodeUseCase creates a new RegisterUserWithCodeUseCase object
 func NewRegisterUserWithCodeUseCase(
 	userRepository UserRepository,
+	passwordHasher PasswordHasher,
+	passwordPolicy PasswordPolicy,
 	verifyCodeUseCase *VerifyCodeUseCase,
 	loginUseCase *LoginUserUseCase,
 ) *RegisterUserWithCodeUseCase {
 	return &RegisterUserWithCodeUseCase{
 		userRepository:    userRepository,
+		passwordHasher:    passwordHasher,
+		passwordPolicy:    passwordPolicy,
 		verifyCodeUseCase: verifyCodeUseCase,
 		loginUseCase:      loginUseCase,
 	}
@@ -88,8 +93,8 @@ func (uc *RegisterUserWithCodeUseCase) Execute(ctx context.Context, verification
 	}
 
 	// password validation
-	if len(password) < 8 {
-		return nil, ErrPasswordTooShort
+	if err := validatePassword(uc.passwordPolicy, password, name, email); err != nil {
+		return nil, err
 	}
 
 	// Check if a user already exists
@@ -106,7 +111,7 @@ func (uc *RegisterUserWithCodeUseCase) Execute(ctx context.Context, verification
 	}
 
 	// Hash the password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := uc.passwordHasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
@@ -115,13 +120,13 @@ func (uc *RegisterUserWithCodeUseCase) Execute(ctx context.Context, verification
 	user := &domain.User{
 		Name:     name,
 		Email:    email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 	}
 
 	if err := uc.userRepository.Save(ctx, user); err != nil {
 		return nil, err
 	}
 
-	// Generate login token
-	return uc.loginUseCase.GenerateToken(ctx, user.ID, false)
+	// Generate login token, the account has just been created so it has no MFA or login history to assess
+	return uc.loginUseCase.GenerateToken(ctx, user.ID, false, []string{domain.AMROTP})
 }
@@ -12,10 +12,12 @@ type RememberTokenRepository interface {
 	Generate() (string, error)
 	// Hash hashes a raw token string using SHA-256.
 	Hash(token string) string
-	// Save stores a new remember token in the database.
-	Save(ctx context.Context, userID int64, tokenHash string, duration time.Duration) error
+	// Save stores a new remember token in the database, clientID is the OAuth client it is issued to or empty.
+	Save(ctx context.Context, userID int64, clientID string, tokenHash string, duration time.Duration) error
 	// FindByToken hashes the provided raw token and finds the matching record.
 	FindByToken(ctx context.Context, hashToken string) (*domain.RememberToken, error)
 	// Delete removes a token by its ID.
 	Delete(ctx context.Context, tokenID int64) error
+	// DeleteByUserID removes all tokens of the user except the one with exceptTokenHash.
+	DeleteByUserID(ctx context.Context, userID int64, exceptTokenHash string) error
 }
@@ -0,0 +1,18 @@
+package usecase
+
+import "context"
+
+// RemovePhoneNumberUseCase represents the remove phone number use case object
+type RemovePhoneNumberUseCase struct {
+	userRepository UserRepository
+}
+
+// NewRemovePhoneNumberUseCase creates a new RemovePhoneNumberUseCase object
+func NewRemovePhoneNumberUseCase(userRepository UserRepository) *RemovePhoneNumberUseCase {
+	return &RemovePhoneNumberUseCase{userRepository: userRepository}
+}
+
+// Execute removes the phone number of the user, its codes are emailed again
+func (uc *RemovePhoneNumberUseCase) Execute(ctx context.Context, userID int64) error {
+	return uc.userRepository.UpdatePhoneNumber(ctx, userID, "")
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+)
+
+// dataExportStaleAfter is how long an export can be in progress before the user may request a new one,
+// in case the task generating it was lost
+const dataExportStaleAfter = time.Hour
+
+// RequestDataExportUseCase represents the use case for requesting an export of the user's personal data
+type RequestDataExportUseCase struct {
+	dataExportRepository DataExportRepository
+	taskDistributor      TaskDistributor
+}
+
+// NewRequestDataExportUseCase creates a new RequestDataExportUseCase object
+func NewRequestDataExportUseCase(dataExportRepository DataExportRepository, taskDistributor TaskDistributor) *RequestDataExportUseCase {
+	return &RequestDataExportUseCase{
+		dataExportRepository: dataExportRepository,
+		taskDistributor:      taskDistributor,
+	}
+}
+
+// Execute queues the generation of a data export, the user is emailed a download link once it is ready
+func (uc *RequestDataExportUseCase) Execute(ctx context.Context, userID int64) (*domain.DataExport, error) {
+	exports, err := uc.dataExportRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Exports are listed most recent first
+	if len(exports) > 0 && exports[0].IsInProgress() && time.Since(exports[0].CreatedAt) < dataExportStaleAfter {
+		return nil, ErrDataExportInProgress
+	}
+
+	export := &domain.DataExport{
+		UserID: userID,
+		Status: domain.DataExportStatusPending,
+	}
+	if err := uc.dataExportRepository.Save(ctx, export); err != nil {
+		return nil, err
+	}
+
+	if err := uc.taskDistributor.DistributeTaskGenerateDataExport(ctx, export.ID); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
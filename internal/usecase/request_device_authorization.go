@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// deviceCodeDuration is how long the user has to approve a device
+	deviceCodeDuration = 10 * time.Minute
+
+	// devicePollInterval is the minimum number of seconds the device waits between polls
+	devicePollInterval = 5
+)
+
+// DeviceAuthorizationResult represents the response of the device authorization endpoint
+type DeviceAuthorizationResult struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresIn               int64
+	Interval                int
+}
+
+// RequestDeviceAuthorizationUseCase represents the use case for starting a device authorization grant
+type RequestDeviceAuthorizationUseCase struct {
+	deviceAuthorizationRepository DeviceAuthorizationRepository
+	verificationURI               string
+	allowedClientIDs              []string
+}
+
+// NewRequestDeviceAuthorizationUseCase creates a new RequestDeviceAuthorizationUseCase object.
+// The verification URI is the page where users enter the user code. When allowedClientIDs is empty, any client ID is accepted.
+func NewRequestDeviceAuthorizationUseCase(
+	deviceAuthorizationRepository DeviceAuthorizationRepository,
+	verificationURI string,
+	allowedClientIDs []string,
+) *RequestDeviceAuthorizationUseCase {
+	return &RequestDeviceAuthorizationUseCase{
+		deviceAuthorizationRepository: deviceAuthorizationRepository,
+		verificationURI:               verificationURI,
+		allowedClientIDs:              allowedClientIDs,
+	}
+}
+
+// Execute issues a device code and a user code for the client.
+// Without a scope, the issued token grants the same access as an interactive login.
+func (uc *RequestDeviceAuthorizationUseCase) Execute(ctx context.Context, clientID string, scope string) (*DeviceAuthorizationResult, error) {
+	if clientID == "" || (len(uc.allowedClientIDs) > 0 && !slices.Contains(uc.allowedClientIDs, clientID)) {
+		return nil, ErrInvalidClient
+	}
+
+	scopes := strings.Fields(scope)
+	for _, s := range scopes {
+		if !slices.Contains(domain.PersonalAccessTokenScopes, s) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	deviceCode, err := uc.deviceAuthorizationRepository.GenerateDeviceCode()
+	if err != nil {
+		return nil, err
+	}
+
+	userCode, err := uc.deviceAuthorizationRepository.GenerateUserCode()
+	if err != nil {
+		return nil, err
+	}
+
+	authorization := &domain.DeviceAuthorization{
+		DeviceCodeHash: uc.deviceAuthorizationRepository.Hash(deviceCode),
+		UserCodeHash:   uc.deviceAuthorizationRepository.Hash(domain.NormalizeUserCode(userCode)),
+		ClientID:       clientID,
+		Scope:          strings.Join(scopes, " "),
+		Interval:       devicePollInterval,
+		ExpiresAt:      time.Now().Add(deviceCodeDuration),
+	}
+
+	if err := uc.deviceAuthorizationRepository.Save(ctx, authorization); err != nil {
+		return nil, err
+	}
+
+	return &DeviceAuthorizationResult{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         uc.verificationURI,
+		VerificationURIComplete: uc.verificationURI + "?user_code=" + url.QueryEscape(userCode),
+		ExpiresIn:               int64(deviceCodeDuration.Seconds()),
+		Interval:                devicePollInterval,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Email change token lifetimes. The revert link stays valid long after the change,
+// since the owner of the old address may not notice the notice right away.
+const (
+	emailChangeConfirmDuration = time.Hour
+	emailChangeRevertDuration  = 7 * 24 * time.Hour
+)
+
+// RequestEmailChangeUseCase represents the use case for requesting a change of the user's email address
+type RequestEmailChangeUseCase struct {
+	userRepository        UserRepository
+	emailChangeRepository EmailChangeRepository
+	passwordHasher        PasswordHasher
+	taskDistributor       TaskDistributor
+}
+
+// NewRequestEmailChangeUseCase creates a new RequestEmailChangeUseCase object
+func NewRequestEmailChangeUseCase(
+	userRepository UserRepository,
+	emailChangeRepository EmailChangeRepository,
+	passwordHasher PasswordHasher,
+	taskDistributor TaskDistributor,
+) *RequestEmailChangeUseCase {
+	return &RequestEmailChangeUseCase{
+		userRepository:        userRepository,
+		emailChangeRepository: emailChangeRepository,
+		passwordHasher:        passwordHasher,
+		taskDistributor:       taskDistributor,
+	}
+}
+
+// Execute verifies the current password, then sends a confirmation link to the new address
+// and a notice with a revert link to the current one. Nothing changes until the new address is confirmed.
+func (uc *RequestEmailChangeUseCase) Execute(ctx context.Context, userID int64, newEmail string, currentPassword string) error {
+	newEmail = strings.TrimSpace(newEmail)
+	if newEmail == "" {
+		return ErrEmptyEmail
+	}
+
+	if err := (&domain.User{Email: newEmail}).Validate(); err != nil {
+		return ErrInvalidEmail
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		return err
+	}
+
+	// The directory owns the email of users provisioned from it
+	if user.AuthSource != domain.AuthSourceLocal {
+		return ErrEmailManagedExternally
+	}
+
+	if strings.EqualFold(newEmail, user.Email) {
+		return ErrSameEmail
+	}
+
+	if currentPassword == "" {
+		return ErrInvalidCurrentPassword
+	}
+
+	valid, err := uc.passwordHasher.Verify(currentPassword, user.Password)
+	if err != nil || !valid {
+		return ErrInvalidCurrentPassword
+	}
+
+	exists, err := uc.userRepository.IsVerifiedUserExists(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrEmailExists
+	}
+
+	confirmToken, err := uc.emailChangeRepository.Generate()
+	if err != nil {
+		return err
+	}
+	revertToken, err := uc.emailChangeRepository.Generate()
+	if err != nil {
+		return err
+	}
+
+	change := &domain.EmailChange{
+		UserID:           user.ID,
+		OldEmail:         user.Email,
+		NewEmail:         newEmail,
+		ConfirmTokenHash: uc.emailChangeRepository.Hash(confirmToken),
+		RevertTokenHash:  uc.emailChangeRepository.Hash(revertToken),
+		ExpiresAt:        time.Now().Add(emailChangeConfirmDuration),
+		RevertExpiresAt:  time.Now().Add(emailChangeRevertDuration),
+	}
+	if err := uc.emailChangeRepository.Save(ctx, change); err != nil {
+		return err
+	}
+
+	if err := uc.taskDistributor.DistributeTaskSendEmailChangeConfirmation(ctx, newEmail, confirmToken); err != nil {
+		return err
+	}
+
+	return uc.taskDistributor.DistributeTaskSendEmailChangeNotice(ctx, user.Email, newEmail, revertToken)
+}
@@ -3,6 +3,8 @@ package usecase
 import (
 	"auth/internal/domain"
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 	"strings"
 	"time"
@@ -10,10 +12,11 @@ import (
 
 // RequestLoginOTPUseCase represents the request login OTP use case object
 type RequestLoginOTPUseCase struct {
-	logger             *slog.Logger
-	loginOTPRepository LoginOTPRepository
-	userRepository     UserRepository
-	taskDistributor    TaskDistributor
+	logger               *slog.Logger
+	loginOTPRepository   LoginOTPRepository
+	userRepository       UserRepository
+	taskDistributor      TaskDistributor
+	sendPhoneCodeUseCase *SendPhoneCodeUseCase
 }
 
 // NewRequestLoginOTPUseCase creates a new request login OTP use case object
@@ -22,17 +25,20 @@ func NewRequestLoginOTPUseCase(
 	loginOTPRepository LoginOTPRepository,
 	userRepository UserRepository,
 	taskDistributor TaskDistributor,
+	sendPhoneCodeUseCase *SendPhoneCodeUseCase,
 ) *RequestLoginOTPUseCase {
 	return &RequestLoginOTPUseCase{
-		logger:             logger,
-		loginOTPRepository: loginOTPRepository,
-		userRepository:     userRepository,
-		taskDistributor:    taskDistributor,
+		logger:               logger,
+		loginOTPRepository:   loginOTPRepository,
+		userRepository:       userRepository,
+		taskDistributor:      taskDistributor,
+		sendPhoneCodeUseCase: sendPhoneCodeUseCase,
 	}
 }
 
-// Execute executes the request login OTP use case
-func (uc *RequestLoginOTPUseCase) Execute(ctx context.Context, email string) error {
+// Execute executes the request login OTP use case, the code goes out with the channel, email when empty.
+// Phone channels use the verified phone number of the account.
+func (uc *RequestLoginOTPUseCase) Execute(ctx context.Context, email string, channel string) error {
 	// Validate input
 	email = strings.TrimSpace(email)
 	if email == "" {
@@ -43,19 +49,50 @@ func (uc *RequestLoginOTPUseCase) Execute(ctx context.Context, email string) err
 		return ErrInvalidEmail
 	}
 
-	// Check if user exist and verified
-	exist, err := uc.userRepository.IsVerifiedUserExists(ctx, email)
+	if channel == "" {
+		channel = domain.OTPChannelEmail
+	}
+	if !domain.IsOTPChannel(channel) {
+		return ErrInvalidOTPChannel
+	}
 
-	if err != nil {
+	// Check if user exist and verified
+	user, err := uc.userRepository.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
 
-	if !exist {
+	if user == nil || !user.Verified {
 		// Don't reveal to client if user doesn't exist to prevent enumeration attack
 		uc.logger.Warn("login OTP request for user that doesn't exist")
 		return nil
 	}
 
+	// Nor whether the account is blocked
+	if user.IsBlocked() {
+		uc.logger.Warn("login OTP request for blocked user", "user_id", user.ID)
+		return nil
+	}
+
+	// Nor whether the account has a phone number
+	if domain.IsPhoneChannel(channel) && user.PhoneNumber == "" {
+		uc.logger.Warn("login OTP request by phone for user without phone number", "user_id", user.ID)
+		return nil
+	}
+
+	// A throttled request must not replace the current code
+	if domain.IsPhoneChannel(channel) {
+		err := uc.sendPhoneCodeUseCase.Reserve(ctx, user.PhoneNumber, channel)
+		// Nor whether the phone number is throttled
+		if errors.Is(err, ErrTooManyPhoneCodes) {
+			uc.logger.Warn("login OTP request by phone throttled", "user_id", user.ID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	// Generate code and hash
 	code, err := uc.loginOTPRepository.Generate(6)
 	if err != nil {
@@ -70,6 +107,10 @@ func (uc *RequestLoginOTPUseCase) Execute(ctx context.Context, email string) err
 		return err
 	}
 
+	if domain.IsPhoneChannel(channel) {
+		return uc.sendPhoneCodeUseCase.Send(ctx, user.PhoneNumber, channel, domain.PhoneCodePurposeLogin, code)
+	}
+
 	// Dispatch task to send the OTP email
 	return uc.taskDistributor.DistributeTaskSendEmailLoginOTP(ctx, email, code)
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"auth/internal/domain"
 	"context"
 	"database/sql"
 	"errors"
@@ -52,6 +53,12 @@ func (uc *RequestPasswordResetUseCase) Execute(ctx context.Context, email string
 		return nil
 	}
 
+	// Passwords of directory users are managed by the directory, not by us
+	if user.AuthSource != domain.AuthSourceLocal {
+		uc.logger.Warn("Password reset requested for externally managed user", "email", email, "auth_source", user.AuthSource)
+		return nil
+	}
+
 	// Generate token
 	token, err := uc.tokenRepository.Generate()
 	if err != nil {
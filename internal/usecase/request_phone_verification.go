@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"strings"
+	"time"
+)
+
+// phoneVerificationDuration is how long the code sent to a new phone number works
+const phoneVerificationDuration = 10 * time.Minute
+
+// RequestPhoneVerificationUseCase represents the use case for adding a phone number to an account.
+// The number replaces the current one once the code sent to it is entered.
+type RequestPhoneVerificationUseCase struct {
+	phoneVerificationRepository PhoneVerificationRepository
+	sendPhoneCodeUseCase        *SendPhoneCodeUseCase
+}
+
+// NewRequestPhoneVerificationUseCase creates a new RequestPhoneVerificationUseCase object
+func NewRequestPhoneVerificationUseCase(
+	phoneVerificationRepository PhoneVerificationRepository,
+	sendPhoneCodeUseCase *SendPhoneCodeUseCase,
+) *RequestPhoneVerificationUseCase {
+	return &RequestPhoneVerificationUseCase{
+		phoneVerificationRepository: phoneVerificationRepository,
+		sendPhoneCodeUseCase:        sendPhoneCodeUseCase,
+	}
+}
+
+// Execute sends a verification code to the phone number with the sms or voice channel, sms when empty
+func (uc *RequestPhoneVerificationUseCase) Execute(ctx context.Context, userID int64, phoneNumber string, channel string) error {
+	phoneNumber = strings.TrimSpace(phoneNumber)
+	if phoneNumber == "" {
+		return ErrEmptyPhoneNumber
+	}
+	if !domain.IsValidPhoneNumber(phoneNumber) {
+		return ErrInvalidPhoneNumber
+	}
+
+	if channel == "" {
+		channel = domain.OTPChannelSMS
+	}
+	if !domain.IsPhoneChannel(channel) {
+		return ErrInvalidOTPChannel
+	}
+
+	// A throttled request must not replace the code being verified
+	if err := uc.sendPhoneCodeUseCase.Reserve(ctx, phoneNumber, channel); err != nil {
+		return err
+	}
+
+	code, err := uc.phoneVerificationRepository.Generate(6)
+	if err != nil {
+		return err
+	}
+
+	verification := &domain.PhoneVerification{
+		UserID:      userID,
+		PhoneNumber: phoneNumber,
+		CodeHash:    uc.phoneVerificationRepository.Hash(code),
+		ExpiresAt:   time.Now().Add(phoneVerificationDuration),
+	}
+	if err := uc.phoneVerificationRepository.Save(ctx, verification); err != nil {
+		return err
+	}
+
+	return uc.sendPhoneCodeUseCase.Send(ctx, phoneNumber, channel, domain.PhoneCodePurposeVerification, code)
+}
@@ -4,29 +4,33 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-
-	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
 // ResetPasswordUseCase represents the reset password use case object
 type ResetPasswordUseCase struct {
-	userRepository  UserRepository
-	tokenRepository PasswordResetTokenRepository
+	tokenRepository         PasswordResetTokenRepository
+	trustedDeviceRepository TrustedDeviceRepository
+	changePasswordUseCase   *ChangePasswordUseCase
 }
 
 // NewResetPasswordUseCase creates a new reset password use case object
-func NewResetPasswordUseCase(userRepository UserRepository, tokenRepository PasswordResetTokenRepository) *ResetPasswordUseCase {
+func NewResetPasswordUseCase(
+	tokenRepository PasswordResetTokenRepository,
+	trustedDeviceRepository TrustedDeviceRepository,
+	changePasswordUseCase *ChangePasswordUseCase,
+) *ResetPasswordUseCase {
 	return &ResetPasswordUseCase{
-		userRepository:  userRepository,
-		tokenRepository: tokenRepository,
+		tokenRepository:         tokenRepository,
+		trustedDeviceRepository: trustedDeviceRepository,
+		changePasswordUseCase:   changePasswordUseCase,
 	}
 }
 
-// Execute executes the reset password use case
+// Execute executes the reset password use case. Devices trusted to skip the second factor must log in with it again.
 func (uc *ResetPasswordUseCase) Execute(ctx context.Context, token, newPassword string) error {
-	// Length of new password must be greater than 7
-	if len(newPassword) < 8 {
-		return ErrPasswordTooShort
+	if strings.TrimSpace(newPassword) == "" {
+		return ErrEmptyPassword
 	}
 
 	// Check if hashed token is exist in database
@@ -39,15 +43,13 @@ func (uc *ResetPasswordUseCase) Execute(ctx context.Context, token, newPassword
 		return err
 	}
 
-	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	// Validate and update password, the policy and history checks keep the token usable on failure
+	err = uc.changePasswordUseCase.Execute(ctx, resetToken.UserID, newPassword)
 	if err != nil {
-		return err
-	}
+		if errors.Is(err, ErrUserNotFound) {
+			return ErrInvalidToken
+		}
 
-	// Update password
-	err = uc.userRepository.UpdatePassword(ctx, resetToken.UserID, string(hashedPassword))
-	if err != nil {
 		return err
 	}
 
@@ -57,5 +59,5 @@ func (uc *ResetPasswordUseCase) Execute(ctx context.Context, token, newPassword
 		return err
 	}
 
-	return nil
+	return uc.trustedDeviceRepository.DeleteByUserID(ctx, resetToken.UserID)
 }
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+)
+
+// RestoreAccountUseCase represents the use case for cancelling the deletion of an account with the restricted token issued at login
+type RestoreAccountUseCase struct {
+	logger          *slog.Logger
+	userRepository  UserRepository
+	tokenParser     TokenParser
+	taskDistributor TaskDistributor
+	loginUseCase    *LoginUserUseCase
+}
+
+// NewRestoreAccountUseCase creates a new RestoreAccountUseCase object
+func NewRestoreAccountUseCase(
+	logger *slog.Logger,
+	userRepository UserRepository,
+	tokenParser TokenParser,
+	taskDistributor TaskDistributor,
+	loginUseCase *LoginUserUseCase,
+) *RestoreAccountUseCase {
+	return &RestoreAccountUseCase{
+		logger:          logger,
+		userRepository:  userRepository,
+		tokenParser:     tokenParser,
+		taskDistributor: taskDistributor,
+		loginUseCase:    loginUseCase,
+	}
+}
+
+// Execute validates the restricted token, restores the account and logs the user in
+func (uc *RestoreAccountUseCase) Execute(ctx context.Context, restoreToken string) (*LoginToken, error) {
+	claims, err := uc.tokenParser.ParseToken(restoreToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != AccountRestoreTokenPurpose {
+		return nil, ErrInvalidToken
+	}
+
+	// JWT stores numbers as float64
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	deletedAt, ok := claims["deleted_at"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	// The token is spent once the account has been restored
+	if !user.IsDeleted() || user.DeletedAt.Unix() != int64(deletedAt) {
+		return nil, ErrInvalidToken
+	}
+
+	if err := uc.userRepository.Restore(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.taskDistributor.DistributeTaskSendEmailAccountRestored(ctx, user.Email); err != nil {
+		uc.logger.Error("Failed to distribute account restored email", "user_id", user.ID, "error", err)
+	}
+
+	return uc.loginUseCase.GenerateToken(ctx, user.ID, false, []string{domain.AMRPassword})
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// RevertEmailChangeUseCase represents the use case for the "this wasn't me" link sent to the old email address
+type RevertEmailChangeUseCase struct {
+	userRepository                UserRepository
+	emailChangeRepository         EmailChangeRepository
+	rememberRepository            RememberTokenRepository
+	sessionRepository             SessionRepository
+	personalAccessTokenRepository PersonalAccessTokenRepository
+	serviceAccountRepository      ServiceAccountRepository
+	trustedDeviceRepository       TrustedDeviceRepository
+}
+
+// NewRevertEmailChangeUseCase creates a new RevertEmailChangeUseCase object
+func NewRevertEmailChangeUseCase(
+	userRepository UserRepository,
+	emailChangeRepository EmailChangeRepository,
+	rememberRepository RememberTokenRepository,
+	sessionRepository SessionRepository,
+	personalAccessTokenRepository PersonalAccessTokenRepository,
+	serviceAccountRepository ServiceAccountRepository,
+	trustedDeviceRepository TrustedDeviceRepository,
+) *RevertEmailChangeUseCase {
+	return &RevertEmailChangeUseCase{
+		userRepository:                userRepository,
+		emailChangeRepository:         emailChangeRepository,
+		rememberRepository:            rememberRepository,
+		sessionRepository:             sessionRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		serviceAccountRepository:      serviceAccountRepository,
+		trustedDeviceRepository:       trustedDeviceRepository,
+	}
+}
+
+// Execute cancels or undoes the email change. Whoever requested it knew the password, so every device is signed out,
+// every credential they may have created is revoked and the password must be reset before the next login.
+func (uc *RevertEmailChangeUseCase) Execute(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidToken
+	}
+
+	change, err := uc.emailChangeRepository.FindByRevertToken(ctx, uc.emailChangeRepository.Hash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	reverted, err := uc.emailChangeRepository.Revert(ctx, change)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+	if !reverted {
+		return ErrEmailExists
+	}
+
+	if err := uc.rememberRepository.DeleteByUserID(ctx, change.UserID, ""); err != nil {
+		return err
+	}
+	if err := uc.sessionRepository.DeleteByUserID(ctx, change.UserID, 0); err != nil {
+		return err
+	}
+	if err := uc.personalAccessTokenRepository.DeleteByUserID(ctx, change.UserID); err != nil {
+		return err
+	}
+	if err := uc.serviceAccountRepository.RevokeCredentialsByOwner(ctx, change.UserID); err != nil {
+		return err
+	}
+	if err := uc.trustedDeviceRepository.DeleteByUserID(ctx, change.UserID); err != nil {
+		return err
+	}
+
+	return uc.userRepository.RequirePasswordReset(ctx, change.UserID)
+}
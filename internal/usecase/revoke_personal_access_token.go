@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// RevokePersonalAccessTokenUseCase represents the revoke personal access token use case object
+type RevokePersonalAccessTokenUseCase struct {
+	tokenRepository PersonalAccessTokenRepository
+}
+
+// NewRevokePersonalAccessTokenUseCase creates a new RevokePersonalAccessTokenUseCase object
+func NewRevokePersonalAccessTokenUseCase(tokenRepository PersonalAccessTokenRepository) *RevokePersonalAccessTokenUseCase {
+	return &RevokePersonalAccessTokenUseCase{tokenRepository: tokenRepository}
+}
+
+// Execute deletes a personal access token owned by the user
+func (uc *RevokePersonalAccessTokenUseCase) Execute(ctx context.Context, userID int64, tokenID int64) error {
+	err := uc.tokenRepository.Delete(ctx, userID, tokenID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTokenNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
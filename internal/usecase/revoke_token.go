@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// RevokeTokenUseCase represents the use case for clients revoking a token (RFC 7009)
+type RevokeTokenUseCase struct {
+	authenticateClientUseCase *AuthenticateClientUseCase
+	tokenParser               TokenParser
+	rememberRepository        RememberTokenRepository
+}
+
+// NewRevokeTokenUseCase creates a new RevokeTokenUseCase object
+func NewRevokeTokenUseCase(
+	authenticateClientUseCase *AuthenticateClientUseCase,
+	tokenParser TokenParser,
+	rememberRepository RememberTokenRepository,
+) *RevokeTokenUseCase {
+	return &RevokeTokenUseCase{
+		authenticateClientUseCase: authenticateClientUseCase,
+		tokenParser:               tokenParser,
+		rememberRepository:        rememberRepository,
+	}
+}
+
+// Execute authenticates the calling client and revokes the refresh token it was issued (RFC 7009 section 2.1).
+// Unknown tokens and tokens issued to other clients are ignored, as the client can't act on the outcome anyway.
+// Personal access tokens are issued to users, who revoke them from their account.
+// JWT access tokens are stateless and can't be revoked before they expire.
+func (uc *RevokeTokenUseCase) Execute(ctx context.Context, credentials ClientCredentials, token string) error {
+	client, err := uc.authenticateClientUseCase.Execute(ctx, credentials)
+	if err != nil {
+		return err
+	}
+
+	if token == "" || strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+		return nil
+	}
+
+	if _, err := uc.tokenParser.ParseToken(token); err == nil {
+		return ErrUnsupportedTokenType
+	}
+
+	rememberToken, err := uc.rememberRepository.FindByToken(ctx, uc.rememberRepository.Hash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	}
+
+	if rememberToken.ClientID != client.ClientID {
+		return nil
+	}
+
+	return uc.rememberRepository.Delete(ctx, rememberToken.ID)
+}
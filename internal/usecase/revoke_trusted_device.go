@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// RevokeTrustedDeviceUseCase represents the revoke trusted device use case object
+type RevokeTrustedDeviceUseCase struct {
+	trustedDeviceRepository TrustedDeviceRepository
+}
+
+// NewRevokeTrustedDeviceUseCase creates a new RevokeTrustedDeviceUseCase object
+func NewRevokeTrustedDeviceUseCase(trustedDeviceRepository TrustedDeviceRepository) *RevokeTrustedDeviceUseCase {
+	return &RevokeTrustedDeviceUseCase{trustedDeviceRepository: trustedDeviceRepository}
+}
+
+// Execute deletes a trusted device of the user, its next login completes a second factor again
+func (uc *RevokeTrustedDeviceUseCase) Execute(ctx context.Context, userID int64, deviceID int64) error {
+	err := uc.trustedDeviceRepository.Delete(ctx, userID, deviceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTrustedDeviceNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// DefaultSecretRotationOverlap is how long the previous secrets keep working after a rotation
+const DefaultSecretRotationOverlap = 24 * time.Hour
+
+// maxSecretRotationOverlap caps the overlap so a leaked secret can't be kept alive indefinitely
+const maxSecretRotationOverlap = 7 * 24 * time.Hour
+
+// RotateServiceAccountSecretUseCase represents the rotate service account secret use case object
+type RotateServiceAccountSecretUseCase struct {
+	serviceAccountRepository ServiceAccountRepository
+}
+
+// NewRotateServiceAccountSecretUseCase creates a new RotateServiceAccountSecretUseCase object
+func NewRotateServiceAccountSecretUseCase(serviceAccountRepository ServiceAccountRepository) *RotateServiceAccountSecretUseCase {
+	return &RotateServiceAccountSecretUseCase{serviceAccountRepository: serviceAccountRepository}
+}
+
+// Execute issues a new client secret. The previous secrets stay valid during the overlap,
+// so clients can be redeployed without downtime.
+func (uc *RotateServiceAccountSecretUseCase) Execute(
+	ctx context.Context,
+	ownerUserID int64,
+	serviceAccountID int64,
+	overlap time.Duration,
+) (string, error) {
+	if overlap < 0 || overlap > maxSecretRotationOverlap {
+		return "", ErrInvalidRotationOverlap
+	}
+
+	if _, err := findOwnedServiceAccount(ctx, uc.serviceAccountRepository, ownerUserID, serviceAccountID); err != nil {
+		return "", err
+	}
+
+	secret, err := uc.serviceAccountRepository.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	if err := uc.serviceAccountRepository.RotateSecret(ctx, serviceAccountID, uc.serviceAccountRepository.Hash(secret), overlap); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// findOwnedServiceAccount finds a service account, hiding the ones owned by other users
+func findOwnedServiceAccount(
+	ctx context.Context,
+	serviceAccountRepository ServiceAccountRepository,
+	ownerUserID int64,
+	serviceAccountID int64,
+) (*domain.ServiceAccount, error) {
+	account, err := serviceAccountRepository.FindByID(ctx, serviceAccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrServiceAccountNotFound
+		}
+
+		return nil, err
+	}
+
+	if account.OwnerUserID != ownerUserID {
+		return nil, ErrServiceAccountNotFound
+	}
+
+	return account, nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SecureAccountUseCase represents the use case for the "secure my account" link of new device emails
+type SecureAccountUseCase struct {
+	tokenParser                   TokenParser
+	userRepository                UserRepository
+	rememberRepository            RememberTokenRepository
+	sessionRepository             SessionRepository
+	knownDeviceRepository         KnownDeviceRepository
+	trustedDeviceRepository       TrustedDeviceRepository
+	personalAccessTokenRepository PersonalAccessTokenRepository
+	serviceAccountRepository      ServiceAccountRepository
+}
+
+// NewSecureAccountUseCase creates a new SecureAccountUseCase object
+func NewSecureAccountUseCase(
+	tokenParser TokenParser,
+	userRepository UserRepository,
+	rememberRepository RememberTokenRepository,
+	sessionRepository SessionRepository,
+	knownDeviceRepository KnownDeviceRepository,
+	trustedDeviceRepository TrustedDeviceRepository,
+	personalAccessTokenRepository PersonalAccessTokenRepository,
+	serviceAccountRepository ServiceAccountRepository,
+) *SecureAccountUseCase {
+	return &SecureAccountUseCase{
+		tokenParser:                   tokenParser,
+		userRepository:                userRepository,
+		rememberRepository:            rememberRepository,
+		sessionRepository:             sessionRepository,
+		knownDeviceRepository:         knownDeviceRepository,
+		trustedDeviceRepository:       trustedDeviceRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		serviceAccountRepository:      serviceAccountRepository,
+	}
+}
+
+// Execute checks the token can only be used once, then signs the user out of every device, forgets the reported device,
+// stops trusting any device to skip the second factor and revokes the personal access tokens and service account credentials
+// the intruder may have created. Whoever signed in knew the password, so it must be reset before the next login.
+func (uc *SecureAccountUseCase) Execute(ctx context.Context, token string) error {
+	claims, err := uc.tokenParser.ParseToken(token)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != SecureAccountTokenPurpose {
+		return ErrInvalidToken
+	}
+
+	// JWT stores numbers as float64
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return ErrInvalidToken
+	}
+	deviceID, ok := claims["device_id"].(float64)
+	if !ok {
+		return ErrInvalidToken
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	// Whoever can read the email must not be able to sign the user out again and again
+	fresh, err := uc.knownDeviceRepository.MarkSecureAccountTokenUsed(ctx, user.ID, uc.knownDeviceRepository.Hash(token), time.Unix(int64(exp), 0))
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidToken
+	}
+
+	if err := uc.rememberRepository.DeleteByUserID(ctx, user.ID, ""); err != nil {
+		return err
+	}
+	if err := uc.sessionRepository.DeleteByUserID(ctx, user.ID, 0); err != nil {
+		return err
+	}
+	if err := uc.knownDeviceRepository.Delete(ctx, user.ID, int64(deviceID)); err != nil {
+		return err
+	}
+	if err := uc.trustedDeviceRepository.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := uc.personalAccessTokenRepository.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := uc.serviceAccountRepository.RevokeCredentialsByOwner(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return uc.userRepository.RequirePasswordReset(ctx, user.ID)
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+// PhoneThrottlePolicy limits the codes sent to a phone number, texts and calls cost money and can be used to harass.
+// Zero values disable the limits.
+type PhoneThrottlePolicy struct {
+	// MinInterval is the time to wait between two codes
+	MinInterval time.Duration
+	// Limit is the number of codes that can be sent within Window
+	Limit  int
+	Window time.Duration
+}
+
+// DefaultPhoneThrottlePolicy returns the policy used when nothing is configured
+func DefaultPhoneThrottlePolicy() PhoneThrottlePolicy {
+	return PhoneThrottlePolicy{
+		MinInterval: time.Minute,
+		Limit:       5,
+		Window:      time.Hour,
+	}
+}
+
+// SendPhoneCodeUseCase represents the use case for texting or calling one-time codes, throttled per phone number
+type SendPhoneCodeUseCase struct {
+	phoneCodeSendRepository PhoneCodeSendRepository
+	taskDistributor         TaskDistributor
+	policy                  PhoneThrottlePolicy
+}
+
+// NewSendPhoneCodeUseCase creates a new SendPhoneCodeUseCase object
+func NewSendPhoneCodeUseCase(
+	phoneCodeSendRepository PhoneCodeSendRepository,
+	taskDistributor TaskDistributor,
+	policy PhoneThrottlePolicy,
+) *SendPhoneCodeUseCase {
+	return &SendPhoneCodeUseCase{
+		phoneCodeSendRepository: phoneCodeSendRepository,
+		taskDistributor:         taskDistributor,
+		policy:                  policy,
+	}
+}
+
+// Execute sends the code to the phone number with the sms or voice channel.
+// It returns ErrTooManyPhoneCodes when the phone number has received too many codes recently.
+func (uc *SendPhoneCodeUseCase) Execute(ctx context.Context, phoneNumber string, channel string, purpose string, code string) error {
+	if err := uc.Reserve(ctx, phoneNumber, channel); err != nil {
+		return err
+	}
+
+	return uc.Send(ctx, phoneNumber, channel, purpose, code)
+}
+
+// Reserve counts a code against the limits of the phone number, it returns ErrTooManyPhoneCodes when they are reached.
+// Callers storing the code reserve first, so a throttled request leaves the current code alone.
+func (uc *SendPhoneCodeUseCase) Reserve(ctx context.Context, phoneNumber string, channel string) error {
+	allowed, err := uc.phoneCodeSendRepository.SaveIfAllowed(
+		ctx,
+		phoneNumber,
+		channel,
+		uc.policy.MinInterval,
+		uc.policy.Limit,
+		uc.policy.Window,
+	)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrTooManyPhoneCodes
+	}
+
+	return nil
+}
+
+// Send sends a code reserved with Reserve
+func (uc *SendPhoneCodeUseCase) Send(ctx context.Context, phoneNumber string, channel string, purpose string, code string) error {
+	return uc.taskDistributor.DistributeTaskSendPhoneCode(ctx, phoneNumber, channel, purpose, code)
+}
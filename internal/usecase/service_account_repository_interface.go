@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"time"
+)
+
+// ServiceAccountRepository represents the service account repository interface
+type ServiceAccountRepository interface {
+	// GenerateClientID creates a new public client identifier.
+	GenerateClientID() (string, error)
+	// GenerateSecret creates a new client secret.
+	GenerateSecret() (string, error)
+	// Hash hashes a client secret using SHA-256.
+	Hash(secret string) string
+	// Save stores a new service account together with its first secret.
+	Save(ctx context.Context, account *domain.ServiceAccount, secretHash string) error
+	FindByID(ctx context.Context, id int64) (*domain.ServiceAccount, error)
+	FindByClientID(ctx context.Context, clientID string) (*domain.ServiceAccount, error)
+	FindByOwner(ctx context.Context, ownerUserID int64) ([]domain.ServiceAccount, error)
+	// RotateSecret adds a new secret and lets the current secrets expire after the overlap.
+	RotateSecret(ctx context.Context, serviceAccountID int64, secretHash string, overlap time.Duration) error
+	// RevokeCredentialsByOwner deletes the secrets and public keys of every service account of the user,
+	// they can't authenticate until the owner issues new ones.
+	RevokeCredentialsByOwner(ctx context.Context, ownerUserID int64) error
+	// IsSecretValid checks whether the hash belongs to an unexpired secret of the service account.
+	IsSecretValid(ctx context.Context, serviceAccountID int64, secretHash string) (bool, error)
+	SaveKey(ctx context.Context, key *domain.ServiceAccountKey) error
+	FindKeys(ctx context.Context, serviceAccountID int64) ([]domain.ServiceAccountKey, error)
+	// MarkAssertionUsed records a client assertion ID, returning false when it was already used.
+	MarkAssertionUsed(ctx context.Context, serviceAccountID int64, jti string, expiresAt time.Time) (bool, error)
+}
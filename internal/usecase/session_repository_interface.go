@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// SessionRepository represents the session store interface.
+// Sessions are kept in Postgres, a Redis store only has to implement this interface.
+type SessionRepository interface {
+	// Generate creates a new opaque session token.
+	Generate() (string, error)
+	// Hash hashes a session token using SHA-256.
+	Hash(token string) string
+	Save(ctx context.Context, session *domain.Session) error
+	// FindByToken finds the session by its current or previous token hash, unless it passed its absolute expiry.
+	FindByToken(ctx context.Context, tokenHash string) (*domain.Session, error)
+	// Touch records that the session was just used.
+	Touch(ctx context.Context, sessionID int64) error
+	// Rotate replaces the token of the session and the roles it was issued for, keeping the replaced token as the previous one.
+	Rotate(ctx context.Context, sessionID int64, tokenHash string, roles []string) error
+	// Reauthenticate replaces the token of the session, keeping the replaced token as the previous one,
+	// and records that the user just authenticated with amr.
+	Reauthenticate(ctx context.Context, sessionID int64, tokenHash string, amr []string) (*domain.Session, error)
+	Delete(ctx context.Context, sessionID int64) error
+	// DeleteByUserID ends all sessions of the user except exceptSessionID.
+	DeleteByUserID(ctx context.Context, userID int64, exceptSessionID int64) error
+}
@@ -0,0 +1,11 @@
+package usecase
+
+import "context"
+
+// SMSSender interface, the counterpart of EmailSender for phone numbers.
+// purpose is one of the domain.PhoneCodePurpose constants, it decides the wording of the message.
+type SMSSender interface {
+	SendSMSCode(ctx context.Context, phoneNumber string, purpose string, code string) error
+	// SendVoiceCode reads the code out in a phone call
+	SendVoiceCode(ctx context.Context, phoneNumber string, purpose string, code string) error
+}
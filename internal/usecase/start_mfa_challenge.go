@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// MFAChallengeTokenPurpose is the purpose of the restricted token identifying an MFA challenge.
+// It is only accepted by the verify MFA challenge use case.
+const MFAChallengeTokenPurpose = "mfa_challenge"
+
+// mfaChallengeDuration is how long the user has to enter the code of an MFA challenge
+const mfaChallengeDuration = 10 * time.Minute
+
+// mfaCodeLength is the number of digits of MFA codes
+const mfaCodeLength = 6
+
+// maxOpenMFAChallenges is the number of live challenges a user can have, with the attempts of each it caps the guesses per user
+const maxOpenMFAChallenges = 3
+
+// MFARequiredError is returned by logins that need a second factor.
+// It matches ErrMFARequired with errors.Is and carries the restricted token to complete the challenge with.
+type MFARequiredError struct {
+	Token     string
+	Channel   string
+	ExpiresAt time.Time
+}
+
+func (e *MFARequiredError) Error() string {
+	return ErrMFARequired.Error()
+}
+
+// Is reports whether the target is ErrMFARequired
+func (e *MFARequiredError) Is(target error) bool {
+	return target == ErrMFARequired
+}
+
+// StartMFAChallengeUseCase represents the use case for sending a second factor to a user in the middle of a login
+type StartMFAChallengeUseCase struct {
+	logger                 *slog.Logger
+	mfaChallengeRepository MFAChallengeRepository
+	tokenGenerator         TokenGenerator
+	taskDistributor        TaskDistributor
+	sendPhoneCodeUseCase   *SendPhoneCodeUseCase
+}
+
+// NewStartMFAChallengeUseCase creates a new StartMFAChallengeUseCase object
+func NewStartMFAChallengeUseCase(
+	logger *slog.Logger,
+	mfaChallengeRepository MFAChallengeRepository,
+	tokenGenerator TokenGenerator,
+	taskDistributor TaskDistributor,
+	sendPhoneCodeUseCase *SendPhoneCodeUseCase,
+) *StartMFAChallengeUseCase {
+	return &StartMFAChallengeUseCase{
+		logger:                 logger,
+		mfaChallengeRepository: mfaChallengeRepository,
+		tokenGenerator:         tokenGenerator,
+		taskDistributor:        taskDistributor,
+		sendPhoneCodeUseCase:   sendPhoneCodeUseCase,
+	}
+}
+
+// Execute sends a code to the user with their MFA channel and returns the MFARequiredError to answer the login with.
+// loginEventID is the login waiting for the challenge, if it was recorded.
+func (uc *StartMFAChallengeUseCase) Execute(ctx context.Context, user *domain.User, loginEventID *int64, rememberMe bool, useSession bool) error {
+	code, err := uc.mfaChallengeRepository.Generate(mfaCodeLength)
+	if err != nil {
+		return err
+	}
+
+	channel, err := uc.channel(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	challenge := &domain.MFAChallenge{
+		UserID:       user.ID,
+		LoginEventID: loginEventID,
+		CodeHash:     uc.mfaChallengeRepository.Hash(code),
+		Channel:      channel,
+		RememberMe:   rememberMe,
+		UseSession:   useSession,
+		ExpiresAt:    time.Now().Add(mfaChallengeDuration),
+	}
+	allowed, err := uc.mfaChallengeRepository.SaveIfAllowed(ctx, challenge, maxOpenMFAChallenges)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrTooManyMFAChallenges
+	}
+
+	if err := uc.send(ctx, user, channel, code); err != nil {
+		return err
+	}
+
+	token, err := uc.tokenGenerator.GenerateTokenWithClaims(user.ID, MFAChallengeTokenPurpose, mfaChallengeDuration, map[string]any{
+		"challenge_id": challenge.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return &MFARequiredError{Token: token, Channel: challenge.Channel, ExpiresAt: challenge.ExpiresAt}
+}
+
+// channel returns the channel to send the code with, the MFA channel of the user when it can be used.
+// Codes are emailed when the user has no phone number or it has received too many codes,
+// so a throttled phone never locks the user out.
+func (uc *StartMFAChallengeUseCase) channel(ctx context.Context, user *domain.User) (string, error) {
+	if domain.IsPhoneChannel(user.MFAChannel) && user.PhoneNumber != "" {
+		err := uc.sendPhoneCodeUseCase.Reserve(ctx, user.PhoneNumber, user.MFAChannel)
+		if err == nil {
+			return user.MFAChannel, nil
+		}
+		if !errors.Is(err, ErrTooManyPhoneCodes) {
+			return "", err
+		}
+
+		uc.logger.Warn("MFA code throttled on phone, falling back to email", "user_id", user.ID)
+	}
+
+	return domain.OTPChannelEmail, nil
+}
+
+// send delivers the code with the channel
+func (uc *StartMFAChallengeUseCase) send(ctx context.Context, user *domain.User, channel string, code string) error {
+	if domain.IsPhoneChannel(channel) {
+		return uc.sendPhoneCodeUseCase.Send(ctx, user.PhoneNumber, channel, domain.PhoneCodePurposeMFA, code)
+	}
+
+	return uc.taskDistributor.DistributeTaskSendEmailMFACode(ctx, user.Email, code)
+}
@@ -1,7 +1,9 @@
 package usecase
 
 import (
+	"auth/internal/domain"
 	"context"
+	"time"
 )
 
 // TaskDistributor interface for task distributor
@@ -10,4 +12,15 @@ type TaskDistributor interface {
 	DistributeTaskSendEmailPasswordResetLink(ctx context.Context, email string, token string) error
 	DistributeTaskSendEmailVerificationCode(ctx context.Context, email string, code string) error
 	DistributeTaskSendEmailLoginOTP(ctx context.Context, email string, code string) error
+	DistributeTaskSendEmailPasswordChanged(ctx context.Context, email string, changedAt time.Time) error
+	DistributeTaskSendEmailChangeConfirmation(ctx context.Context, newEmail string, token string) error
+	DistributeTaskSendEmailChangeNotice(ctx context.Context, oldEmail string, newEmail string, revertToken string) error
+	DistributeTaskSendEmailAccountDeletionScheduled(ctx context.Context, email string, purgeAt time.Time) error
+	DistributeTaskSendEmailAccountRestored(ctx context.Context, email string) error
+	DistributeTaskSendEmailAccountDeleted(ctx context.Context, email string) error
+	DistributeTaskGenerateDataExport(ctx context.Context, exportID int64) error
+	DistributeTaskSendEmailDataExportReady(ctx context.Context, email string, token string, expiresAt time.Time) error
+	DistributeTaskSendEmailNewDeviceLogin(ctx context.Context, email string, login domain.DeviceLogin, secureToken string) error
+	DistributeTaskSendEmailMFACode(ctx context.Context, email string, code string) error
+	DistributeTaskSendPhoneCode(ctx context.Context, phoneNumber string, channel string, purpose string, code string) error
 }
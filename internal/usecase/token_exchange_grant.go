@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Token types of the token exchange grant (RFC 8693 section 3)
+const (
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// exchangedTokenDuration is the longest lifetime of an exchanged token, it never outlives the subject token
+const exchangedTokenDuration = 15 * time.Minute
+
+// TokenExchangeRequest represents a token exchange request (RFC 8693 section 2.1)
+type TokenExchangeRequest struct {
+	Credentials  ClientCredentials
+	SubjectToken string
+	// ActorToken is optional, the authenticated client is the actor without it
+	ActorToken string
+	// Audience lists the services the exchanged token is meant for
+	Audience []string
+	Scope    string
+}
+
+// TokenExchangeGrantUseCase represents the OAuth token exchange grant use case object
+type TokenExchangeGrantUseCase struct {
+	authenticateClientUseCase *AuthenticateClientUseCase
+	checkUserStatusUseCase    *CheckUserStatusUseCase
+	tokenParser               TokenParser
+	tokenGenerator            TokenGenerator
+	// audiences lists the services tokens can be exchanged for
+	audiences []string
+}
+
+// NewTokenExchangeGrantUseCase creates a new TokenExchangeGrantUseCase object
+func NewTokenExchangeGrantUseCase(
+	authenticateClientUseCase *AuthenticateClientUseCase,
+	checkUserStatusUseCase *CheckUserStatusUseCase,
+	tokenParser TokenParser,
+	tokenGenerator TokenGenerator,
+	audiences []string,
+) *TokenExchangeGrantUseCase {
+	return &TokenExchangeGrantUseCase{
+		authenticateClientUseCase: authenticateClientUseCase,
+		checkUserStatusUseCase:    checkUserStatusUseCase,
+		tokenParser:               tokenParser,
+		tokenGenerator:            tokenGenerator,
+		audiences:                 audiences,
+	}
+}
+
+// Execute swaps the subject token for a token limited to the requested audience and scopes.
+// Scopes and audience can only be narrowed, and the actor is added to the act claim chain of the subject token.
+func (uc *TokenExchangeGrantUseCase) Execute(ctx context.Context, req TokenExchangeRequest) (*OAuthToken, error) {
+	account, err := uc.authenticateClientUseCase.Execute(ctx, req.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(account.Scopes, domain.ScopeTokenExchange) {
+		return nil, ErrUnauthorizedClient
+	}
+
+	subject, err := uc.parseAccessToken(req.SubjectToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Impersonation is only possible through this API, where each request is audited
+	if _, ok := subject["impersonation_id"]; ok {
+		return nil, ErrInvalidGrant
+	}
+
+	claims := map[string]any{"client_id": account.ClientID}
+
+	var sub any
+	if subject["principal_type"] == domain.PrincipalTypeService {
+		sub = subject["sub"]
+		claims["principal_type"] = domain.PrincipalTypeService
+		claims["service_account_id"] = subject["service_account_id"]
+	} else {
+		// JWT stores numbers as float64
+		userID, ok := subject["sub"].(float64)
+		if !ok {
+			return nil, ErrInvalidGrant
+		}
+
+		if err := uc.checkUser(ctx, userID); err != nil {
+			return nil, err
+		}
+		sub = int64(userID)
+	}
+
+	scopes, err := narrowScopes(subject, strings.Fields(req.Scope))
+	if err != nil {
+		return nil, err
+	}
+	grantedScope := strings.Join(scopes, " ")
+	claims["scope"] = grantedScope
+
+	for _, audience := range req.Audience {
+		if !slices.Contains(uc.audiences, audience) {
+			return nil, ErrInvalidTarget
+		}
+	}
+	if err := narrowAudience(subject, req.Audience); err != nil {
+		return nil, err
+	}
+	claims["aud"] = req.Audience
+
+	actor, err := uc.actor(ctx, account.ClientID, req.ActorToken)
+	if err != nil {
+		return nil, err
+	}
+	// Downstream services can require a recent login as well
+	if authTime, ok := subject["auth_time"]; ok {
+		claims["auth_time"] = authTime
+		claims["amr"] = subject["amr"]
+	}
+
+	// Earlier actors are nested, the outermost act claim is the current actor (RFC 8693 section 4.1)
+	if previous, ok := subject["act"].(map[string]any); ok {
+		actor["act"] = previous
+	}
+	claims["act"] = actor
+
+	duration := exchangedTokenDuration
+	if exp, ok := subject["exp"].(float64); ok {
+		duration = min(duration, time.Until(time.Unix(int64(exp), 0)))
+	}
+
+	accessToken, err := uc.tokenGenerator.GenerateTokenWithClaims(sub, "access_token", duration, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthToken{
+		AccessToken:     accessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(duration.Seconds()),
+		Scope:           grantedScope,
+		IssuedTokenType: TokenTypeAccessToken,
+	}, nil
+}
+
+// actor returns the act claim of the current actor, the authenticated client or the actor token it presented.
+// The actor token must have been issued to the client, so a client can't act on behalf of another one.
+func (uc *TokenExchangeGrantUseCase) actor(ctx context.Context, clientID string, actorToken string) (map[string]any, error) {
+	if actorToken == "" {
+		return map[string]any{"sub": clientID, "client_id": clientID}, nil
+	}
+
+	actorClaims, err := uc.parseAccessToken(actorToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorClaims["client_id"] != clientID {
+		return nil, ErrInvalidGrant
+	}
+
+	if _, ok := actorClaims["impersonation_id"]; ok {
+		return nil, ErrInvalidGrant
+	}
+
+	if actorClaims["principal_type"] != domain.PrincipalTypeService {
+		userID, ok := actorClaims["sub"].(float64)
+		if !ok {
+			return nil, ErrInvalidGrant
+		}
+
+		if err := uc.checkUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]any{"sub": actorClaims["sub"], "client_id": clientID}, nil
+}
+
+// checkUser refuses the exchange when the user of a subject or actor token can no longer sign in.
+// JWT stores numbers as float64.
+func (uc *TokenExchangeGrantUseCase) checkUser(ctx context.Context, userID float64) error {
+	if _, err := uc.checkUserStatusUseCase.Execute(ctx, int64(userID)); err != nil {
+		if errors.Is(err, ErrAccountUnavailable) || errors.Is(err, ErrUserUnauthorized) {
+			return ErrInvalidGrant
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// parseAccessToken validates a subject or actor token, which must be an access token issued by this service
+func (uc *TokenExchangeGrantUseCase) parseAccessToken(token string) (map[string]any, error) {
+	claims, err := uc.tokenParser.ParseToken(token)
+	if err != nil {
+		return nil, ErrInvalidGrant
+	}
+
+	purpose, _ := claims["purpose"].(string)
+	if purpose != "access_token" && purpose != "refresh_token" {
+		return nil, ErrInvalidGrant
+	}
+
+	return claims, nil
+}
+
+// narrowScopes returns the requested scopes, or those of the subject token when none were requested.
+// Full access is never delegated, a token without a scope claim must be exchanged for explicit scopes.
+func narrowScopes(subject map[string]any, requested []string) ([]string, error) {
+	var subjectScopes []string
+	if scope, ok := subject["scope"].(string); ok {
+		subjectScopes = strings.Fields(scope)
+	}
+
+	if len(requested) == 0 {
+		requested = subjectScopes
+	}
+
+	if len(requested) == 0 || slices.Contains(requested, domain.ScopeAll) {
+		return nil, ErrInvalidScope
+	}
+
+	// Interactive tokens carry every scope
+	if subjectScopes == nil || slices.Contains(subjectScopes, domain.ScopeAll) {
+		return requested, nil
+	}
+
+	for _, scope := range requested {
+		if !slices.Contains(subjectScopes, scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	return requested, nil
+}
+
+// narrowAudience checks that a subject token already limited to an audience is only exchanged within it
+func narrowAudience(subject map[string]any, requested []string) error {
+	if len(requested) == 0 {
+		return ErrInvalidTarget
+	}
+
+	subjectAudience := claimStrings(subject["aud"])
+	if len(subjectAudience) == 0 {
+		return nil
+	}
+
+	for _, audience := range requested {
+		if !slices.Contains(subjectAudience, audience) {
+			return ErrInvalidTarget
+		}
+	}
+
+	return nil
+}
+
+// claimStrings reads a claim that can be a single string or an array of strings, like aud
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
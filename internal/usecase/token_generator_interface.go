@@ -1,6 +1,16 @@
 package usecase
 
+import "time"
+
 // TokenGenerator interface for token generation
 type TokenGenerator interface {
 	GenerateToken(subject any, purpose string) (string, error)
+	// GenerateTokenWithClaims generates a token with a custom lifetime and additional claims
+	GenerateTokenWithClaims(subject any, purpose string, duration time.Duration, claims map[string]any) (string, error)
+}
+
+// TokenParser interface for validating tokens issued by TokenGenerator
+type TokenParser interface {
+	// ParseToken verifies the signature and expiry of the token and returns its claims
+	ParseToken(token string) (map[string]any, error)
 }
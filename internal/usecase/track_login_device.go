@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SecureAccountTokenPurpose is the purpose of the tokens in the "secure my account" link of new device emails
+const SecureAccountTokenPurpose = "secure_account"
+
+// secureAccountTokenDuration is how long the "secure my account" link of a new device email works
+const secureAccountTokenDuration = 7 * 24 * time.Hour
+
+// TrackLoginDeviceUseCase represents the use case for recording the devices users sign in from
+type TrackLoginDeviceUseCase struct {
+	logger                *slog.Logger
+	knownDeviceRepository KnownDeviceRepository
+	ipLocator             IPLocator
+	tokenGenerator        TokenGenerator
+	taskDistributor       TaskDistributor
+}
+
+// NewTrackLoginDeviceUseCase creates a new TrackLoginDeviceUseCase object. The IP locator is optional.
+func NewTrackLoginDeviceUseCase(
+	logger *slog.Logger,
+	knownDeviceRepository KnownDeviceRepository,
+	ipLocator IPLocator,
+	tokenGenerator TokenGenerator,
+	taskDistributor TaskDistributor,
+) *TrackLoginDeviceUseCase {
+	return &TrackLoginDeviceUseCase{
+		logger:                logger,
+		knownDeviceRepository: knownDeviceRepository,
+		ipLocator:             ipLocator,
+		tokenGenerator:        tokenGenerator,
+		taskDistributor:       taskDistributor,
+	}
+}
+
+// Execute records the device of the client in the context and emails the user when they signed in from a new device.
+// Failures are logged, they never block a login.
+func (uc *TrackLoginDeviceUseCase) Execute(ctx context.Context, user *domain.User) {
+	// Logins outside an HTTP request have no device
+	info, ok := ClientInfoFromContext(ctx)
+	if !ok || info.DeviceID == "" {
+		return
+	}
+
+	if err := uc.track(ctx, user, info); err != nil {
+		uc.logger.Error("Failed to track login device", "user_id", user.ID, "error", err)
+	}
+}
+
+func (uc *TrackLoginDeviceUseCase) track(ctx context.Context, user *domain.User, info domain.ClientInfo) error {
+	family := info.UserAgentFamily()
+
+	hasAny, err := uc.knownDeviceRepository.HasAny(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Clients that don't keep the device cookie get a new device ID on every login
+	similar, err := uc.knownDeviceRepository.HasSimilar(ctx, user.ID, family, info.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	device := &domain.KnownDevice{
+		UserID:          user.ID,
+		DeviceIDHash:    uc.knownDeviceRepository.Hash(info.DeviceID),
+		UserAgentFamily: family,
+		IPAddress:       info.IPAddress,
+		Location:        uc.locate(info.IPAddress),
+	}
+
+	isNew, err := uc.knownDeviceRepository.Upsert(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	// The first device of an account is the one it was created from
+	if !isNew || !hasAny || similar {
+		return nil
+	}
+
+	token, err := uc.tokenGenerator.GenerateTokenWithClaims(user.ID, SecureAccountTokenPurpose, secureAccountTokenDuration, map[string]any{
+		"device_id": device.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.taskDistributor.DistributeTaskSendEmailNewDeviceLogin(ctx, user.Email, domain.DeviceLogin{
+		Device:    family,
+		IPAddress: info.IPAddress,
+		Location:  device.Location,
+		LoginAt:   device.LastSeenAt,
+	}, token)
+}
+
+// locate describes the approximate location of the IP address, it is empty when unknown
+func (uc *TrackLoginDeviceUseCase) locate(ipAddress string) string {
+	if uc.ipLocator == nil {
+		return ""
+	}
+
+	location, err := uc.ipLocator.Locate(ipAddress)
+	if err != nil {
+		uc.logger.Warn("Failed to locate IP address", "error", err)
+		return ""
+	}
+	if location == nil {
+		return ""
+	}
+
+	return location.String()
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// TrustedDeviceTokenPurpose is the purpose of the tokens kept by trusted devices in the trusted device cookie
+const TrustedDeviceTokenPurpose = "trusted_device"
+
+// TrustedDeviceToken represents the signed token a trusted device presents to skip the second factor
+type TrustedDeviceToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TrustDeviceUseCase represents the use case for letting a device skip the second factor for a while.
+// The token only works together with the server record it names and the device ID it was issued to,
+// so revoking the record or losing the device cookie ends the trust.
+type TrustDeviceUseCase struct {
+	trustedDeviceRepository TrustedDeviceRepository
+	tokenGenerator          TokenGenerator
+	tokenParser             TokenParser
+	duration                time.Duration
+}
+
+// NewTrustDeviceUseCase creates a new TrustDeviceUseCase object, devices are trusted for duration
+func NewTrustDeviceUseCase(
+	trustedDeviceRepository TrustedDeviceRepository,
+	tokenGenerator TokenGenerator,
+	tokenParser TokenParser,
+	duration time.Duration,
+) *TrustDeviceUseCase {
+	return &TrustDeviceUseCase{
+		trustedDeviceRepository: trustedDeviceRepository,
+		tokenGenerator:          tokenGenerator,
+		tokenParser:             tokenParser,
+		duration:                duration,
+	}
+}
+
+// Execute trusts the device of the client in the context, it returns nil when the client has no device ID
+func (uc *TrustDeviceUseCase) Execute(ctx context.Context, userID int64) (*TrustedDeviceToken, error) {
+	info, ok := ClientInfoFromContext(ctx)
+	if !ok || info.DeviceID == "" {
+		return nil, nil
+	}
+
+	device := &domain.TrustedDevice{
+		UserID:          userID,
+		DeviceIDHash:    uc.trustedDeviceRepository.Hash(info.DeviceID),
+		UserAgentFamily: info.UserAgentFamily(),
+		IPAddress:       info.IPAddress,
+		ExpiresAt:       time.Now().Add(uc.duration),
+	}
+	if err := uc.trustedDeviceRepository.Save(ctx, device); err != nil {
+		return nil, err
+	}
+
+	token, err := uc.tokenGenerator.GenerateTokenWithClaims(userID, TrustedDeviceTokenPurpose, uc.duration, map[string]any{
+		"trusted_device_id": device.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrustedDeviceToken{Token: token, ExpiresAt: device.ExpiresAt}, nil
+}
+
+// IsTrusted reports whether the client in the context presents a trusted device token of the user from the device it was issued to
+func (uc *TrustDeviceUseCase) IsTrusted(ctx context.Context, userID int64) (bool, error) {
+	info, ok := ClientInfoFromContext(ctx)
+	if !ok || info.TrustedDeviceToken == "" || info.DeviceID == "" {
+		return false, nil
+	}
+
+	// Invalid tokens only mean the device isn't trusted
+	claims, err := uc.tokenParser.ParseToken(info.TrustedDeviceToken)
+	if err != nil {
+		return false, nil
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != TrustedDeviceTokenPurpose {
+		return false, nil
+	}
+
+	// JWT stores numbers as float64
+	subject, ok := claims["sub"].(float64)
+	if !ok || int64(subject) != userID {
+		return false, nil
+	}
+	deviceID, ok := claims["trusted_device_id"].(float64)
+	if !ok {
+		return false, nil
+	}
+
+	device, err := uc.trustedDeviceRepository.FindByID(ctx, int64(deviceID))
+	if err != nil {
+		// Revoked devices are deleted
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+	if device.UserID != userID || device.IsExpired() || device.DeviceIDHash != uc.trustedDeviceRepository.Hash(info.DeviceID) {
+		return false, nil
+	}
+
+	if err := uc.trustedDeviceRepository.Touch(ctx, device.ID, info.IPAddress); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
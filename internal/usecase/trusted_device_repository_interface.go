@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// TrustedDeviceRepository represents the repository of the devices allowed to skip the second factor
+type TrustedDeviceRepository interface {
+	// Hash hashes a device ID using SHA-256.
+	Hash(deviceID string) string
+	Save(ctx context.Context, device *domain.TrustedDevice) error
+	FindByID(ctx context.Context, id int64) (*domain.TrustedDevice, error)
+	// FindByUserID lists the trusted devices of the user that haven't expired.
+	FindByUserID(ctx context.Context, userID int64) ([]domain.TrustedDevice, error)
+	// Touch records that the device skipped a second factor.
+	Touch(ctx context.Context, id int64, ipAddress string) error
+	// Delete deletes a trusted device of the user, it returns sql.ErrNoRows when there is none.
+	Delete(ctx context.Context, userID int64, id int64) error
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
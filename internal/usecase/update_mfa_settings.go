@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+)
+
+// UpdateMFASettingsUseCase represents the use case for turning the second factor of password logins on or off
+type UpdateMFASettingsUseCase struct {
+	userRepository          UserRepository
+	trustedDeviceRepository TrustedDeviceRepository
+}
+
+// NewUpdateMFASettingsUseCase creates a new UpdateMFASettingsUseCase object
+func NewUpdateMFASettingsUseCase(userRepository UserRepository, trustedDeviceRepository TrustedDeviceRepository) *UpdateMFASettingsUseCase {
+	return &UpdateMFASettingsUseCase{
+		userRepository:          userRepository,
+		trustedDeviceRepository: trustedDeviceRepository,
+	}
+}
+
+// Execute turns MFA on or off for the user and sets the channel of its codes, email when empty.
+// Phone channels need a verified phone number. Turning MFA off forgets the trusted devices,
+// so turning it back on prompts every device again.
+func (uc *UpdateMFASettingsUseCase) Execute(ctx context.Context, userID int64, enabled bool, channel string) error {
+	if channel == "" {
+		channel = domain.OTPChannelEmail
+	}
+	if !domain.IsOTPChannel(channel) {
+		return ErrInvalidOTPChannel
+	}
+
+	if domain.IsPhoneChannel(channel) {
+		user, err := uc.userRepository.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.PhoneNumber == "" {
+			return ErrPhoneNumberRequired
+		}
+	}
+
+	if err := uc.userRepository.UpdateMFASettings(ctx, userID, enabled, channel); err != nil {
+		return err
+	}
+
+	if enabled {
+		return nil
+	}
+
+	return uc.trustedDeviceRepository.DeleteByUserID(ctx, userID)
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// Profile attribute limits
+const (
+	maxNameLength      = 100
+	maxAvatarURLLength = 2048
+	// maxMetadataBytes is the largest encoded size of each metadata bag, it is returned with every profile
+	maxMetadataBytes = 4096
+)
+
+// UpdateUserProfileInput represents the input of the update user profile use case.
+// Nil fields are left unchanged.
+type UpdateUserProfileInput struct {
+	UserID    int64
+	Name      *string
+	Locale    *string
+	Timezone  *string
+	AvatarURL *string
+	// PublicMetadata and PrivateMetadata are merged into the stored metadata, keys set to nil are removed
+	PublicMetadata  map[string]any
+	PrivateMetadata map[string]any
+}
+
+// UpdateUserProfileUseCase represents the use case for a user updating their own profile
+type UpdateUserProfileUseCase struct {
+	userRepository UserRepository
+}
+
+// NewUpdateUserProfileUseCase creates a new UpdateUserProfileUseCase object
+func NewUpdateUserProfileUseCase(userRepository UserRepository) *UpdateUserProfileUseCase {
+	return &UpdateUserProfileUseCase{userRepository: userRepository}
+}
+
+// Execute validates and applies the given profile changes and returns the updated user
+func (uc *UpdateUserProfileUseCase) Execute(ctx context.Context, input UpdateUserProfileInput) (*domain.User, error) {
+	user, err := uc.userRepository.FindByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	var errs []error
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			errs = append(errs, ErrEmptyName)
+		} else if utf8.RuneCountInString(name) > maxNameLength {
+			errs = append(errs, ErrNameTooLong)
+		}
+		user.Name = name
+	}
+
+	if input.Locale != nil {
+		locale := strings.TrimSpace(*input.Locale)
+		if locale != "" {
+			tag, err := language.Parse(locale)
+			if err != nil {
+				errs = append(errs, ErrInvalidLocale)
+			}
+			locale = tag.String()
+		}
+		user.Locale = locale
+	}
+
+	if input.Timezone != nil {
+		timezone := strings.TrimSpace(*input.Timezone)
+		// LoadLocation accepts "" and "Local", neither is a meaningful user timezone
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+				errs = append(errs, ErrInvalidTimezone)
+			}
+		}
+		user.Timezone = timezone
+	}
+
+	if input.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*input.AvatarURL)
+		if avatarURL != "" && !isValidAvatarURL(avatarURL) {
+			errs = append(errs, ErrInvalidAvatarURL)
+		}
+		user.AvatarURL = avatarURL
+	}
+
+	user.PublicMetadata, err = mergeMetadata(user.PublicMetadata, input.PublicMetadata)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	user.PrivateMetadata, err = mergeMetadata(user.PrivateMetadata, input.PrivateMetadata)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := uc.userRepository.UpdateProfile(ctx, user); err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// isValidAvatarURL reports whether the URL is an absolute http or https URL
+func isValidAvatarURL(rawURL string) bool {
+	if len(rawURL) > maxAvatarURLLength {
+		return false
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// mergeMetadata applies the top level keys of patch to metadata like a JSON merge patch, nil values delete keys
+func mergeMetadata(metadata map[string]any, patch map[string]any) (map[string]any, error) {
+	if len(patch) == 0 {
+		return metadata, nil
+	}
+
+	merged := make(map[string]any, len(metadata)+len(patch))
+	for key, value := range metadata {
+		merged[key] = value
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return metadata, err
+	}
+	if len(encoded) > maxMetadataBytes {
+		return metadata, ErrMetadataTooLarge
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxStatusNoteLength is the longest note an administrator can leave on a status change
+const maxStatusNoteLength = 500
+
+// UpdateUserStatusInput represents the input of the update user status use case
+type UpdateUserStatusInput struct {
+	// AdminID is the administrator changing the status
+	AdminID int64
+	UserID  int64
+	Status  string
+	// Reason is one of domain.StatusReasons, Note is free text for other administrators
+	Reason string
+	Note   string
+	// SuspendedUntil is required when suspending
+	SuspendedUntil *time.Time
+}
+
+// UpdateUserStatusUseCase represents the use case for an administrator disabling, suspending or reactivating an account
+type UpdateUserStatusUseCase struct {
+	logger         *slog.Logger
+	userRepository UserRepository
+}
+
+// NewUpdateUserStatusUseCase creates a new UpdateUserStatusUseCase object
+func NewUpdateUserStatusUseCase(logger *slog.Logger, userRepository UserRepository) *UpdateUserStatusUseCase {
+	return &UpdateUserStatusUseCase{
+		logger:         logger,
+		userRepository: userRepository,
+	}
+}
+
+// Execute validates and stores the new status of the user and returns the updated user.
+// Blocking an account signs the user out everywhere.
+func (uc *UpdateUserStatusUseCase) Execute(ctx context.Context, input UpdateUserStatusInput) (*domain.User, error) {
+	// An administrator locking themselves out needs another administrator to undo it
+	if input.AdminID == input.UserID {
+		return nil, ErrOwnStatusChange
+	}
+
+	var errs []error
+
+	input.Reason = strings.TrimSpace(input.Reason)
+	input.Note = strings.TrimSpace(input.Note)
+
+	switch input.Status {
+	case domain.UserStatusActive:
+		// Reactivating clears the reason of the previous block
+		input.Reason = ""
+		input.SuspendedUntil = nil
+	case domain.UserStatusDisabled, domain.UserStatusSuspended:
+		if !slices.Contains(domain.StatusReasons, input.Reason) {
+			errs = append(errs, ErrInvalidStatusReason)
+		}
+	default:
+		errs = append(errs, ErrInvalidUserStatus)
+	}
+
+	if input.Status == domain.UserStatusSuspended {
+		if input.SuspendedUntil == nil || !input.SuspendedUntil.After(time.Now()) {
+			errs = append(errs, ErrInvalidSuspensionEnd)
+		}
+	} else {
+		input.SuspendedUntil = nil
+	}
+
+	if utf8.RuneCountInString(input.Note) > maxStatusNoteLength {
+		errs = append(errs, ErrStatusNoteTooLong)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	user.Status = input.Status
+	user.StatusReason = input.Reason
+	user.StatusNote = input.Note
+	user.SuspendedUntil = input.SuspendedUntil
+
+	if err := uc.userRepository.UpdateStatus(ctx, user); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("User status changed", "user_id", user.ID, "admin_id", input.AdminID, "status", user.Status, "reason", user.StatusReason)
+
+	// The password hash never leaves the service
+	user.Password = ""
+
+	return user, nil
+}
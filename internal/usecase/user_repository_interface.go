@@ -3,6 +3,7 @@ package usecase
 import (
 	"auth/internal/domain"
 	"context"
+	"time"
 )
 
 // UserRepository represents the user repository interface
@@ -13,4 +14,17 @@ type UserRepository interface {
 	IsVerifiedUserExists(ctx context.Context, email string) (bool, error)
 	SetVerified(ctx context.Context, userID int64) error
 	UpdatePassword(ctx context.Context, userID int64, newPassword string) error
+	RehashPassword(ctx context.Context, userID int64, newHash string) error
+	FindPasswordHistory(ctx context.Context, userID int64, limit int) ([]string, error)
+	UpdateRoles(ctx context.Context, userID int64, roles []string) error
+	RequirePasswordReset(ctx context.Context, userID int64) error
+	UpdateProfile(ctx context.Context, user *domain.User) error
+	UpdateMFASettings(ctx context.Context, userID int64, enabled bool, channel string) error
+	// UpdatePhoneNumber sets the verified phone number of the user, an empty number removes it.
+	UpdatePhoneNumber(ctx context.Context, userID int64, phoneNumber string) error
+	UpdateLastLogin(ctx context.Context, userID int64) error
+	SoftDelete(ctx context.Context, userID int64, purgeAt time.Time) (time.Time, error)
+	Restore(ctx context.Context, userID int64) error
+	PurgeDeleted(ctx context.Context) ([]string, error)
+	UpdateStatus(ctx context.Context, user *domain.User) error
 }
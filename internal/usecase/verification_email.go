@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"auth/internal/domain"
 	"context"
 	"database/sql"
 	"errors"
@@ -11,14 +12,21 @@ type VerifyEmailUseCase struct {
 	userRepository              UserRepository
 	verificationTokenRepository VerificationTokenRepository
 	loginUseCase                *LoginUserUseCase
+	assessRiskUseCase           *AssessLoginRiskUseCase
 }
 
 // NewVerifyEmailUseCase creates a new VerifyEmailUseCase object
-func NewVerifyEmailUseCase(userRepository UserRepository, verificationTokenRepository VerificationTokenRepository, loginUseCase *LoginUserUseCase) *VerifyEmailUseCase {
+func NewVerifyEmailUseCase(
+	userRepository UserRepository,
+	verificationTokenRepository VerificationTokenRepository,
+	loginUseCase *LoginUserUseCase,
+	assessRiskUseCase *AssessLoginRiskUseCase,
+) *VerifyEmailUseCase {
 	return &VerifyEmailUseCase{
 		userRepository:              userRepository,
 		verificationTokenRepository: verificationTokenRepository,
 		loginUseCase:                loginUseCase,
+		assessRiskUseCase:           assessRiskUseCase,
 	}
 }
 
@@ -48,9 +56,19 @@ func (uc *VerifyEmailUseCase) Execute(ctx context.Context, rawToken string) (*Lo
 		return nil, err
 	}
 
+	user, err := uc.userRepository.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The link is a login like any other, users with MFA enabled still complete a second factor
+	if err := uc.assessRiskUseCase.Execute(ctx, user, true, false); err != nil {
+		return nil, err
+	}
+
 	// Log the user in by generating a JWT and a new remember token
 	// A long-lived remember token is created by default upon verification
-	loginToken, err := uc.loginUseCase.GenerateToken(ctx, token.UserID, true)
+	loginToken, err := uc.loginUseCase.GenerateToken(ctx, token.UserID, true, []string{domain.AMROTP})
 	if err != nil {
 		return nil, err
 	}
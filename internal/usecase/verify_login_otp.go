@@ -1,42 +1,86 @@
 package usecase
 
-import "context"
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// maxLoginOTPAttempts is the number of wrong codes after which the login OTP of an email is discarded
+const maxLoginOTPAttempts = 5
 
 // VerifyLoginOTPUseCase represents the use case for verifying login otp
 type VerifyLoginOTPUseCase struct {
 	loginOTPRepository LoginOTPRepository
 	userRepository     UserRepository
+	tokenGenerator     TokenGenerator
+	rotationPolicy     PasswordRotationPolicy
 	loginUseCase       *LoginUserUseCase
+	assessRiskUseCase  *AssessLoginRiskUseCase
 }
 
 // NewVerifyLoginOTPUseCase creates a new VerifyLoginOTPUseCase object
 func NewVerifyLoginOTPUseCase(
 	loginOTPRepository LoginOTPRepository,
 	userRepository UserRepository,
+	tokenGenerator TokenGenerator,
+	rotationPolicy PasswordRotationPolicy,
 	loginUseCase *LoginUserUseCase,
+	assessRiskUseCase *AssessLoginRiskUseCase,
 ) *VerifyLoginOTPUseCase {
 	return &VerifyLoginOTPUseCase{
 		loginOTPRepository: loginOTPRepository,
 		userRepository:     userRepository,
+		tokenGenerator:     tokenGenerator,
+		rotationPolicy:     rotationPolicy,
 		loginUseCase:       loginUseCase,
+		assessRiskUseCase:  assessRiskUseCase,
 	}
 }
 
-// Execute verify login otp
-func (uc *VerifyLoginOTPUseCase) Execute(ctx context.Context, code string) (*LoginToken, error) {
-	// Validate code
-	hashCode := uc.loginOTPRepository.Hash(code)
-	err := uc.loginOTPRepository.IsCodeExist(ctx, hashCode)
-	if err != nil {
+// Execute checks the code sent to the email, by email or to the phone number of the account, and logs the user in.
+// Every guess spends an attempt, so parallel guesses can't go past the limit.
+func (uc *VerifyLoginOTPUseCase) Execute(ctx context.Context, email string, code string, rememberMe bool) (*LoginToken, error) {
+	email = strings.TrimSpace(email)
+	if email == "" || code == "" {
+		return nil, ErrInvalidVerificationCode
+	}
+
+	if err := uc.loginOTPRepository.IncrementAttempts(ctx, email, maxLoginOTPAttempts); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidVerificationCode
+		}
+
+		return nil, err
+	}
+
+	// Deleting by hash both checks the code and makes sure it is only used once
+	if err := uc.loginOTPRepository.DeleteByHash(ctx, email, uc.loginOTPRepository.Hash(code)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidVerificationCode
+		}
+
 		return nil, err
 	}
 
-	// Delete from db if code is valid
-	err = uc.loginOTPRepository.Delete(ctx, hashCode)
+	user, err := uc.userRepository.FindByEmail(ctx, email)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidVerificationCode
+		}
+
+		return nil, err
+	}
+	if !user.Verified {
+		return nil, ErrInvalidVerificationCode
+	}
+
+	if err := authorizeLogin(ctx, uc.assessRiskUseCase, uc.tokenGenerator, uc.rotationPolicy, user, rememberMe, false); err != nil {
 		return nil, err
 	}
 
 	// Generate login token
-	return uc.loginUseCase.GenerateToken(ctx, 123, false)
+	return uc.loginUseCase.GenerateToken(ctx, user.ID, rememberMe, []string{domain.AMROTP})
 }
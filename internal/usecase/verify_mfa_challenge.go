@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"auth/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// MFAResult holds the tokens of a login completed with a second factor, either a login token or a session.
+// TrustedDevice is set when the user chose to trust the device.
+type MFAResult struct {
+	Login         *LoginToken
+	Session       *SessionToken
+	TrustedDevice *TrustedDeviceToken
+}
+
+// VerifyMFAChallengeUseCase represents the use case for completing a login with the code of an MFA challenge
+type VerifyMFAChallengeUseCase struct {
+	userRepository         UserRepository
+	tokenGenerator         TokenGenerator
+	tokenParser            TokenParser
+	rotationPolicy         PasswordRotationPolicy
+	mfaChallengeRepository MFAChallengeRepository
+	loginEventRepository   LoginEventRepository
+	loginUseCase           *LoginUserUseCase
+	createSessionUseCase   *CreateSessionUseCase
+	trustDeviceUseCase     *TrustDeviceUseCase
+}
+
+// NewVerifyMFAChallengeUseCase creates a new VerifyMFAChallengeUseCase object
+func NewVerifyMFAChallengeUseCase(
+	userRepository UserRepository,
+	tokenGenerator TokenGenerator,
+	tokenParser TokenParser,
+	rotationPolicy PasswordRotationPolicy,
+	mfaChallengeRepository MFAChallengeRepository,
+	loginEventRepository LoginEventRepository,
+	loginUseCase *LoginUserUseCase,
+	createSessionUseCase *CreateSessionUseCase,
+	trustDeviceUseCase *TrustDeviceUseCase,
+) *VerifyMFAChallengeUseCase {
+	return &VerifyMFAChallengeUseCase{
+		userRepository:         userRepository,
+		tokenGenerator:         tokenGenerator,
+		tokenParser:            tokenParser,
+		rotationPolicy:         rotationPolicy,
+		mfaChallengeRepository: mfaChallengeRepository,
+		loginEventRepository:   loginEventRepository,
+		loginUseCase:           loginUseCase,
+		createSessionUseCase:   createSessionUseCase,
+		trustDeviceUseCase:     trustDeviceUseCase,
+	}
+}
+
+// Execute checks the code against the challenge of the restricted token and finishes the login with the options it was started with.
+// With trustDevice, the device skips the second factor of its next logins for a while.
+// Like a password login, a deleted account or an expired password only gets a restricted token.
+func (uc *VerifyMFAChallengeUseCase) Execute(ctx context.Context, mfaToken string, code string, trustDevice bool) (*MFAResult, error) {
+	claims, err := uc.tokenParser.ParseToken(mfaToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != MFAChallengeTokenPurpose {
+		return nil, ErrInvalidToken
+	}
+
+	// JWT stores numbers as float64
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	challengeID, ok := claims["challenge_id"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	challenge, err := uc.mfaChallengeRepository.FindByID(ctx, int64(challengeID))
+	if err != nil {
+		// The challenge is gone once completed
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+	if challenge.UserID != int64(userID) {
+		return nil, ErrInvalidToken
+	}
+
+	// Every guess spends an attempt, so parallel guesses can't go past the limit
+	if err := uc.mfaChallengeRepository.IncrementAttempts(ctx, challenge.ID, domain.MaxMFAAttempts); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidMFACode
+		}
+
+		return nil, err
+	}
+
+	// Deleting by hash both checks the code and lets a single request complete the challenge
+	if err := uc.mfaChallengeRepository.DeleteByHash(ctx, challenge.ID, uc.mfaChallengeRepository.Hash(code)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidMFACode
+		}
+
+		return nil, err
+	}
+
+	if challenge.LoginEventID != nil {
+		if err := uc.loginEventRepository.MarkSucceeded(ctx, *challenge.LoginEventID); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The risk of the login was assessed before the challenge was sent
+	if err := authorizeLogin(ctx, nil, uc.tokenGenerator, uc.rotationPolicy, user, challenge.RememberMe, challenge.UseSession); err != nil {
+		return nil, err
+	}
+
+	amr := []string{domain.AMRPassword, domain.AMROTP, domain.AMRMFA}
+
+	result := &MFAResult{}
+	if challenge.UseSession {
+		result.Session, err = uc.createSessionUseCase.Execute(ctx, challenge.UserID, challenge.RememberMe, amr)
+	} else {
+		result.Login, err = uc.loginUseCase.GenerateToken(ctx, challenge.UserID, challenge.RememberMe, amr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if trustDevice {
+		if result.TrustedDevice, err = uc.trustDeviceUseCase.Execute(ctx, challenge.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
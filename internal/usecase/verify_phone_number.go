@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// maxPhoneVerificationAttempts is the number of wrong codes after which a phone verification is discarded
+const maxPhoneVerificationAttempts = 5
+
+// VerifyPhoneNumberUseCase represents the use case for confirming a phone number with the code sent to it
+type VerifyPhoneNumberUseCase struct {
+	phoneVerificationRepository PhoneVerificationRepository
+	userRepository              UserRepository
+}
+
+// NewVerifyPhoneNumberUseCase creates a new VerifyPhoneNumberUseCase object
+func NewVerifyPhoneNumberUseCase(
+	phoneVerificationRepository PhoneVerificationRepository,
+	userRepository UserRepository,
+) *VerifyPhoneNumberUseCase {
+	return &VerifyPhoneNumberUseCase{
+		phoneVerificationRepository: phoneVerificationRepository,
+		userRepository:              userRepository,
+	}
+}
+
+// Execute checks the code against the pending verification of the user and sets the verified phone number.
+// Every guess spends an attempt, so parallel guesses can't go past the limit.
+func (uc *VerifyPhoneNumberUseCase) Execute(ctx context.Context, userID int64, code string) (string, error) {
+	if err := uc.phoneVerificationRepository.IncrementAttempts(ctx, userID, maxPhoneVerificationAttempts); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidVerificationCode
+		}
+
+		return "", err
+	}
+
+	verification, err := uc.phoneVerificationRepository.DeleteByHash(ctx, userID, uc.phoneVerificationRepository.Hash(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidVerificationCode
+		}
+
+		return "", err
+	}
+
+	if err := uc.userRepository.UpdatePhoneNumber(ctx, userID, verification.PhoneNumber); err != nil {
+		return "", err
+	}
+
+	return verification.PhoneNumber, nil
+}
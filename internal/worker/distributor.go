@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"auth/internal/domain"
 	"context"
 	"time"
 
@@ -66,3 +67,124 @@ func (d *RedisTaskDistributor) DistributeTaskSendEmailLoginOTP(ctx context.Conte
 	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
 	return err
 }
+
+// DistributeTaskSendEmailPasswordChanged distributes a task to notify the user that their password was changed
+func (d *RedisTaskDistributor) DistributeTaskSendEmailPasswordChanged(ctx context.Context, email string, changedAt time.Time) error {
+	task, err := NewSendEmailPasswordChangedPayload(email, changedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailChangeConfirmation distributes a task to send the confirmation link to a new email address
+func (d *RedisTaskDistributor) DistributeTaskSendEmailChangeConfirmation(ctx context.Context, newEmail string, token string) error {
+	task, err := NewSendEmailChangeConfirmationPayload(newEmail, token)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailChangeNotice distributes a task to warn the old email address about an email change
+func (d *RedisTaskDistributor) DistributeTaskSendEmailChangeNotice(ctx context.Context, oldEmail string, newEmail string, revertToken string) error {
+	task, err := NewSendEmailChangeNoticePayload(oldEmail, newEmail, revertToken)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailAccountDeletionScheduled distributes a task to confirm that the user's account is scheduled for deletion
+func (d *RedisTaskDistributor) DistributeTaskSendEmailAccountDeletionScheduled(ctx context.Context, email string, purgeAt time.Time) error {
+	task, err := NewSendEmailAccountDeletionScheduledPayload(email, purgeAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailAccountRestored distributes a task to confirm that the user's account has been restored
+func (d *RedisTaskDistributor) DistributeTaskSendEmailAccountRestored(ctx context.Context, email string) error {
+	task, err := NewSendEmailAccountRestoredPayload(email)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailAccountDeleted distributes a task to confirm that the user's account has been permanently deleted
+func (d *RedisTaskDistributor) DistributeTaskSendEmailAccountDeleted(ctx context.Context, email string) error {
+	task, err := NewSendEmailAccountDeletedPayload(email)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskGenerateDataExport distributes a task to generate a data export
+func (d *RedisTaskDistributor) DistributeTaskGenerateDataExport(ctx context.Context, exportID int64) error {
+	task, err := NewGenerateDataExportPayload(exportID)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(5*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailDataExportReady distributes a task to send the download link of a data export
+func (d *RedisTaskDistributor) DistributeTaskSendEmailDataExportReady(ctx context.Context, email string, token string, expiresAt time.Time) error {
+	task, err := NewSendEmailDataExportReadyPayload(email, token, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailNewDeviceLogin distributes a task to warn the user of a sign-in from a new device
+func (d *RedisTaskDistributor) DistributeTaskSendEmailNewDeviceLogin(ctx context.Context, email string, login domain.DeviceLogin, secureToken string) error {
+	task, err := NewSendEmailNewDeviceLoginPayload(email, login, secureToken)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendEmailMFACode distributes a task to send the code of an MFA challenge
+func (d *RedisTaskDistributor) DistributeTaskSendEmailMFACode(ctx context.Context, email string, code string) error {
+	task, err := NewSendEmailMFACodePayload(email, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
+
+// DistributeTaskSendPhoneCode distributes a task to text or call a one-time code to a phone number
+func (d *RedisTaskDistributor) DistributeTaskSendPhoneCode(ctx context.Context, phoneNumber string, channel string, purpose string, code string) error {
+	task, err := NewSendPhoneCodePayload(phoneNumber, channel, purpose, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.EnqueueContext(ctx, task, asynq.MaxRetry(3), asynq.Timeout(1*time.Minute))
+	return err
+}
@@ -1,9 +1,11 @@
 package worker
 
 import (
+	"auth/internal/domain"
 	"auth/internal/usecase"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 
 	"github.com/hibiken/asynq"
@@ -17,17 +19,33 @@ type TaskProcessor interface {
 
 // RedisTaskProcessor is the concrete implementation for processing tasks from Redis
 type RedisTaskProcessor struct {
-	server      *asynq.Server
-	emailSender usecase.EmailSender
-	logger      *slog.Logger
+	server                         *asynq.Server
+	emailSender                    usecase.EmailSender
+	smsSender                      usecase.SMSSender
+	purgeDeletedAccountsUseCase    *usecase.PurgeDeletedAccountsUseCase
+	generateDataExportUseCase      *usecase.GenerateDataExportUseCase
+	purgeExpiredDataExportsUseCase *usecase.PurgeExpiredDataExportsUseCase
+	logger                         *slog.Logger
 }
 
 // NewRedisTaskProcessor creates a new RedisTaskProcessor object
-func NewRedisTaskProcessor(server *asynq.Server, emailSender usecase.EmailSender, logger *slog.Logger) *RedisTaskProcessor {
+func NewRedisTaskProcessor(
+	server *asynq.Server,
+	emailSender usecase.EmailSender,
+	smsSender usecase.SMSSender,
+	purgeDeletedAccountsUseCase *usecase.PurgeDeletedAccountsUseCase,
+	generateDataExportUseCase *usecase.GenerateDataExportUseCase,
+	purgeExpiredDataExportsUseCase *usecase.PurgeExpiredDataExportsUseCase,
+	logger *slog.Logger,
+) *RedisTaskProcessor {
 	return &RedisTaskProcessor{
-		server:      server,
-		emailSender: emailSender,
-		logger:      logger,
+		server:                         server,
+		emailSender:                    emailSender,
+		smsSender:                      smsSender,
+		purgeDeletedAccountsUseCase:    purgeDeletedAccountsUseCase,
+		generateDataExportUseCase:      generateDataExportUseCase,
+		purgeExpiredDataExportsUseCase: purgeExpiredDataExportsUseCase,
+		logger:                         logger,
 	}
 }
 
@@ -37,6 +55,20 @@ func (p *RedisTaskProcessor) Start() error {
 	mux.HandleFunc(TypeSendEmailVerificationLink, p.handleTaskSendEmailVerificationLink)
 	mux.HandleFunc(TypeSendEmailPasswordResetLink, p.handleTaskSendEmailPasswordResetLink)
 	mux.HandleFunc(TypeSendEmailVerificationCode, p.handleTaskSendEmailVerificationCode)
+	mux.HandleFunc(TypeSendEmailLoginOTP, p.handleTaskSendEmailLoginOTP)
+	mux.HandleFunc(TypeSendEmailPasswordChanged, p.handleTaskSendEmailPasswordChanged)
+	mux.HandleFunc(TypeSendEmailChangeConfirmation, p.handleTaskSendEmailChangeConfirmation)
+	mux.HandleFunc(TypeSendEmailChangeNotice, p.handleTaskSendEmailChangeNotice)
+	mux.HandleFunc(TypeSendEmailAccountDeletionScheduled, p.handleTaskSendEmailAccountDeletionScheduled)
+	mux.HandleFunc(TypeSendEmailAccountRestored, p.handleTaskSendEmailAccountRestored)
+	mux.HandleFunc(TypeSendEmailAccountDeleted, p.handleTaskSendEmailAccountDeleted)
+	mux.HandleFunc(TypePurgeDeletedAccounts, p.handleTaskPurgeDeletedAccounts)
+	mux.HandleFunc(TypeGenerateDataExport, p.handleTaskGenerateDataExport)
+	mux.HandleFunc(TypePurgeExpiredDataExports, p.handleTaskPurgeExpiredDataExports)
+	mux.HandleFunc(TypeSendEmailDataExportReady, p.handleTaskSendEmailDataExportReady)
+	mux.HandleFunc(TypeSendEmailNewDeviceLogin, p.handleTaskSendEmailNewDeviceLogin)
+	mux.HandleFunc(TypeSendEmailMFACode, p.handleTaskSendEmailMFACode)
+	mux.HandleFunc(TypeSendPhoneCode, p.handleTaskSendPhoneCode)
 
 	p.logger.Info("Starting task processor...")
 
@@ -81,3 +113,176 @@ func (p *RedisTaskProcessor) handleTaskSendEmailVerificationCode(ctx context.Con
 	p.logger.Info("Processing verification code task", "email", payload.Email)
 	return p.emailSender.SendEmailVerificationCode(ctx, payload.Email, payload.Code)
 }
+
+func (p *RedisTaskProcessor) handleTaskSendEmailLoginOTP(ctx context.Context, t *asynq.Task) error {
+	var payload SendLoginOTPPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal login otp payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing login otp email task", "email", payload.Email)
+	return p.emailSender.SendEmailLoginOTP(ctx, payload.Email, payload.Code)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailPasswordChanged(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailPasswordChangedPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal password changed payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing password changed email task", "email", payload.Email)
+	return p.emailSender.SendEmailPasswordChanged(ctx, payload.Email, payload.ChangedAt)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailChangeConfirmation(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailChangeConfirmationPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal email change confirmation payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing email change confirmation task", "email", payload.Email)
+	return p.emailSender.SendEmailChangeConfirmation(ctx, payload.Email, payload.Token)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailChangeNotice(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailChangeNoticePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal email change notice payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing email change notice task", "email", payload.Email)
+	return p.emailSender.SendEmailChangeNotice(ctx, payload.Email, payload.NewEmail, payload.RevertToken)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailAccountDeletionScheduled(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailAccountDeletionScheduledPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal account deletion scheduled payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing account deletion scheduled email task", "email", payload.Email)
+	return p.emailSender.SendEmailAccountDeletionScheduled(ctx, payload.Email, payload.PurgeAt)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailAccountRestored(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailAccountRestoredPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal account restored payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing account restored email task", "email", payload.Email)
+	return p.emailSender.SendEmailAccountRestored(ctx, payload.Email)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailAccountDeleted(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailAccountDeletedPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal account deleted payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing account deleted email task", "email", payload.Email)
+	return p.emailSender.SendEmailAccountDeleted(ctx, payload.Email)
+}
+
+func (p *RedisTaskProcessor) handleTaskPurgeDeletedAccounts(ctx context.Context, _ *asynq.Task) error {
+	purged, err := p.purgeDeletedAccountsUseCase.Execute(ctx)
+	if err != nil {
+		p.logger.Error("Failed to purge deleted accounts", "error", err)
+		return err
+	}
+
+	if purged > 0 {
+		p.logger.Info("Purged deleted accounts", "count", purged)
+	}
+
+	return nil
+}
+
+func (p *RedisTaskProcessor) handleTaskGenerateDataExport(ctx context.Context, t *asynq.Task) error {
+	var payload GenerateDataExportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal generate data export payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing generate data export task", "export_id", payload.ExportID)
+	if err := p.generateDataExportUseCase.Execute(ctx, payload.ExportID); err != nil {
+		p.logger.Error("Failed to generate data export", "export_id", payload.ExportID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *RedisTaskProcessor) handleTaskPurgeExpiredDataExports(ctx context.Context, _ *asynq.Task) error {
+	purged, err := p.purgeExpiredDataExportsUseCase.Execute(ctx)
+	if err != nil {
+		p.logger.Error("Failed to purge expired data exports", "error", err)
+		return err
+	}
+
+	if purged > 0 {
+		p.logger.Info("Purged expired data exports", "count", purged)
+	}
+
+	return nil
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailDataExportReady(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailDataExportReadyPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal data export ready payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing data export ready email task", "email", payload.Email)
+	return p.emailSender.SendEmailDataExportReady(ctx, payload.Email, payload.Token, payload.ExpiresAt)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailNewDeviceLogin(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailNewDeviceLoginPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal new device login payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing new device login email task", "email", payload.Email)
+	return p.emailSender.SendEmailNewDeviceLogin(ctx, payload.Email, payload.Login, payload.SecureToken)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendEmailMFACode(ctx context.Context, t *asynq.Task) error {
+	var payload SendEmailMFACodePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal mfa code payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing mfa code email task", "email", payload.Email)
+	return p.emailSender.SendEmailMFACode(ctx, payload.Email, payload.Code)
+}
+
+func (p *RedisTaskProcessor) handleTaskSendPhoneCode(ctx context.Context, t *asynq.Task) error {
+	var payload SendPhoneCodePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		p.logger.Error("Failed to unmarshal phone code payload", "error", err)
+		return err
+	}
+
+	p.logger.Info("Processing phone code task", "channel", payload.Channel, "purpose", payload.Purpose)
+	switch payload.Channel {
+	case domain.OTPChannelSMS:
+		return p.smsSender.SendSMSCode(ctx, payload.PhoneNumber, payload.Purpose, payload.Code)
+	case domain.OTPChannelVoice:
+		return p.smsSender.SendVoiceCode(ctx, payload.PhoneNumber, payload.Purpose, payload.Code)
+	}
+
+	// Retrying won't help an unknown channel
+	return fmt.Errorf("unknown phone channel %q: %w", payload.Channel, asynq.SkipRetry)
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// Schedules of the periodic tasks
+const (
+	// purgeDeletedAccountsSchedule is how often accounts past their grace period are purged
+	purgeDeletedAccountsSchedule = "@hourly"
+	// purgeExpiredDataExportsSchedule is how often expired data export archives are deleted
+	purgeExpiredDataExportsSchedule = "@hourly"
+)
+
+// RedisTaskScheduler enqueues the periodic tasks
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+	logger    *slog.Logger
+}
+
+// NewRedisTaskScheduler creates a new RedisTaskScheduler object
+func NewRedisTaskScheduler(scheduler *asynq.Scheduler, logger *slog.Logger) *RedisTaskScheduler {
+	return &RedisTaskScheduler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// Start registers the periodic tasks and starts the Asynq scheduler in the background
+func (s *RedisTaskScheduler) Start() error {
+	// Every instance runs a scheduler, Unique keeps them from enqueueing the same run twice
+	_, err := s.scheduler.Register(purgeDeletedAccountsSchedule, NewPurgeDeletedAccountsTask(), asynq.Unique(30*time.Minute), asynq.MaxRetry(3))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.scheduler.Register(purgeExpiredDataExportsSchedule, NewPurgeExpiredDataExportsTask(), asynq.Unique(30*time.Minute), asynq.MaxRetry(3))
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Starting task scheduler...")
+
+	return s.scheduler.Start()
+}
+
+// Shutdown stops the Asynq scheduler
+func (s *RedisTaskScheduler) Shutdown() {
+	s.logger.Info("Shutting down task scheduler")
+	s.scheduler.Shutdown()
+}
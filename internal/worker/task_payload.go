@@ -1,7 +1,9 @@
 package worker
 
 import (
+	"auth/internal/domain"
 	"encoding/json"
+	"time"
 
 	"github.com/hibiken/asynq"
 )
@@ -83,3 +85,226 @@ func NewSendEmailLoginOTPPayload(email string, code string) (*asynq.Task, error)
 
 	return asynq.NewTask(TypeSendEmailLoginOTP, payload), nil
 }
+
+// SendEmailPasswordChangedPayload is the data needed for the TypeSendEmailPasswordChanged task
+type SendEmailPasswordChangedPayload struct {
+	Email     string
+	ChangedAt time.Time
+}
+
+// NewSendEmailPasswordChangedPayload creates a new SendEmailPasswordChangedPayload object
+func NewSendEmailPasswordChangedPayload(email string, changedAt time.Time) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailPasswordChangedPayload{
+		Email:     email,
+		ChangedAt: changedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailPasswordChanged, payload), nil
+}
+
+// SendEmailChangeConfirmationPayload is the data needed for the TypeSendEmailChangeConfirmation task
+type SendEmailChangeConfirmationPayload struct {
+	Email string
+	Token string
+}
+
+// NewSendEmailChangeConfirmationPayload creates a new SendEmailChangeConfirmationPayload object
+func NewSendEmailChangeConfirmationPayload(email string, token string) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailChangeConfirmationPayload{
+		Email: email,
+		Token: token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailChangeConfirmation, payload), nil
+}
+
+// SendEmailChangeNoticePayload is the data needed for the TypeSendEmailChangeNotice task
+type SendEmailChangeNoticePayload struct {
+	Email       string
+	NewEmail    string
+	RevertToken string
+}
+
+// NewSendEmailChangeNoticePayload creates a new SendEmailChangeNoticePayload object
+func NewSendEmailChangeNoticePayload(email string, newEmail string, revertToken string) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailChangeNoticePayload{
+		Email:       email,
+		NewEmail:    newEmail,
+		RevertToken: revertToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailChangeNotice, payload), nil
+}
+
+// SendEmailAccountDeletionScheduledPayload is the data needed for the TypeSendEmailAccountDeletionScheduled task
+type SendEmailAccountDeletionScheduledPayload struct {
+	Email   string
+	PurgeAt time.Time
+}
+
+// NewSendEmailAccountDeletionScheduledPayload creates a new SendEmailAccountDeletionScheduledPayload object
+func NewSendEmailAccountDeletionScheduledPayload(email string, purgeAt time.Time) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailAccountDeletionScheduledPayload{
+		Email:   email,
+		PurgeAt: purgeAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailAccountDeletionScheduled, payload), nil
+}
+
+// SendEmailAccountRestoredPayload is the data needed for the TypeSendEmailAccountRestored task
+type SendEmailAccountRestoredPayload struct {
+	Email string
+}
+
+// NewSendEmailAccountRestoredPayload creates a new SendEmailAccountRestoredPayload object
+func NewSendEmailAccountRestoredPayload(email string) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailAccountRestoredPayload{
+		Email: email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailAccountRestored, payload), nil
+}
+
+// SendEmailAccountDeletedPayload is the data needed for the TypeSendEmailAccountDeleted task
+type SendEmailAccountDeletedPayload struct {
+	Email string
+}
+
+// NewSendEmailAccountDeletedPayload creates a new SendEmailAccountDeletedPayload object
+func NewSendEmailAccountDeletedPayload(email string) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailAccountDeletedPayload{
+		Email: email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailAccountDeleted, payload), nil
+}
+
+// NewPurgeDeletedAccountsTask creates the periodic TypePurgeDeletedAccounts task, it needs no payload
+func NewPurgeDeletedAccountsTask() *asynq.Task {
+	return asynq.NewTask(TypePurgeDeletedAccounts, nil)
+}
+
+// GenerateDataExportPayload is the data needed for the TypeGenerateDataExport task
+type GenerateDataExportPayload struct {
+	ExportID int64
+}
+
+// NewGenerateDataExportPayload creates a new GenerateDataExportPayload object
+func NewGenerateDataExportPayload(exportID int64) (*asynq.Task, error) {
+	payload, err := json.Marshal(GenerateDataExportPayload{
+		ExportID: exportID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeGenerateDataExport, payload), nil
+}
+
+// NewPurgeExpiredDataExportsTask creates the periodic TypePurgeExpiredDataExports task, it needs no payload
+func NewPurgeExpiredDataExportsTask() *asynq.Task {
+	return asynq.NewTask(TypePurgeExpiredDataExports, nil)
+}
+
+// SendEmailDataExportReadyPayload is the data needed for the TypeSendEmailDataExportReady task
+type SendEmailDataExportReadyPayload struct {
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// NewSendEmailDataExportReadyPayload creates a new SendEmailDataExportReadyPayload object
+func NewSendEmailDataExportReadyPayload(email string, token string, expiresAt time.Time) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailDataExportReadyPayload{
+		Email:     email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailDataExportReady, payload), nil
+}
+
+// SendEmailNewDeviceLoginPayload is the data needed for the TypeSendEmailNewDeviceLogin task
+type SendEmailNewDeviceLoginPayload struct {
+	Email       string
+	Login       domain.DeviceLogin
+	SecureToken string
+}
+
+// NewSendEmailNewDeviceLoginPayload creates a new SendEmailNewDeviceLoginPayload object
+func NewSendEmailNewDeviceLoginPayload(email string, login domain.DeviceLogin, secureToken string) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailNewDeviceLoginPayload{
+		Email:       email,
+		Login:       login,
+		SecureToken: secureToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailNewDeviceLogin, payload), nil
+}
+
+// SendEmailMFACodePayload is the data needed for the TypeSendEmailMFACode task
+type SendEmailMFACodePayload struct {
+	Email string
+	Code  string
+}
+
+// NewSendEmailMFACodePayload creates a new SendEmailMFACodePayload object
+func NewSendEmailMFACodePayload(email string, code string) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendEmailMFACodePayload{
+		Email: email,
+		Code:  code,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendEmailMFACode, payload), nil
+}
+
+// SendPhoneCodePayload is the data needed for the TypeSendPhoneCode task
+type SendPhoneCodePayload struct {
+	PhoneNumber string
+	Channel     string
+	Purpose     string
+	Code        string
+}
+
+// NewSendPhoneCodePayload creates a new SendPhoneCodePayload object
+func NewSendPhoneCodePayload(phoneNumber string, channel string, purpose string, code string) (*asynq.Task, error) {
+	payload, err := json.Marshal(SendPhoneCodePayload{
+		PhoneNumber: phoneNumber,
+		Channel:     channel,
+		Purpose:     purpose,
+		Code:        code,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSendPhoneCode, payload), nil
+}
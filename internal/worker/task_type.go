@@ -2,8 +2,21 @@ package worker
 
 // This file defines the names/types of all our background tasks
 const (
-	TypeSendEmailVerificationLink  = "email:verify_token"
-	TypeSendEmailPasswordResetLink = "email:password_reset"
-	TypeSendEmailVerificationCode  = "email:verify_code"
-	TypeSendEmailLoginOTP          = "email:login_otp"
+	TypeSendEmailVerificationLink         = "email:verify_token"
+	TypeSendEmailPasswordResetLink        = "email:password_reset"
+	TypeSendEmailVerificationCode         = "email:verify_code"
+	TypeSendEmailLoginOTP                 = "email:login_otp"
+	TypeSendEmailPasswordChanged          = "email:password_changed"
+	TypeSendEmailChangeConfirmation       = "email:email_change_confirmation"
+	TypeSendEmailChangeNotice             = "email:email_change_notice"
+	TypeSendEmailAccountDeletionScheduled = "email:account_deletion_scheduled"
+	TypeSendEmailAccountRestored          = "email:account_restored"
+	TypeSendEmailAccountDeleted           = "email:account_deleted"
+	TypePurgeDeletedAccounts              = "account:purge_deleted"
+	TypeGenerateDataExport                = "export:generate"
+	TypePurgeExpiredDataExports           = "export:purge_expired"
+	TypeSendEmailDataExportReady          = "email:data_export_ready"
+	TypeSendEmailNewDeviceLogin           = "email:new_device_login"
+	TypeSendEmailMFACode                  = "email:mfa_code"
+	TypeSendPhoneCode                     = "phone:send_code"
 )
@@ -3,21 +3,28 @@ package main
 
 import (
 	_ "auth/docs"
+	"auth/internal/domain"
 	"auth/internal/handler"
 	"auth/internal/repository"
 	"auth/internal/service"
 	"auth/internal/usecase"
 	"auth/internal/worker"
 	"context"
+	"crypto/rand"
 	"errors"
+	"expvar"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Timezones are validated without relying on the zoneinfo of the host
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -110,6 +117,9 @@ func main() {
 	asynqServer := asynq.NewServer(redisConnOpt, asynq.Config{
 		Logger: asynqLogger,
 	})
+	asynqScheduler := asynq.NewScheduler(redisConnOpt, &asynq.SchedulerOpts{
+		Logger: asynqLogger,
+	})
 
 	// Initialize service
 	SMTPConfig := service.SMTPConfig{
@@ -121,50 +131,332 @@ func main() {
 		BaseURL:  os.Getenv("BASE_URL"),
 	}
 	emailSender := service.NewSMTPEmailSender(SMTPConfig)
+	smsSender := openSMSSender(logger)
 
 	// Initialize repositories
 	userRepository := repository.NewPostgresUserRepository(dbpool)
-	authRepository := repository.NewJWTAuthRepository()
+	authRepository := repository.NewJWTAuthRepository(secretKey())
 	rememberRepository := repository.NewPostgresRememberTokenRepository(dbpool)
 	verifyRepository := repository.NewPostgresVerificationTokenRepository(dbpool)
 	passwordResetRepository := repository.NewPostgresPasswordResetTokenRepository(dbpool)
 	emailVerificationCodeRepository := repository.NewPostgresEmailVerificationCodeRepository(dbpool)
 	loginOTPRepository := repository.NewPostgresLoginOTPRepository(dbpool)
+	personalAccessTokenRepository := repository.NewPostgresPersonalAccessTokenRepository(dbpool)
+	serviceAccountRepository := repository.NewPostgresServiceAccountRepository(dbpool)
+	deviceAuthorizationRepository := repository.NewPostgresDeviceAuthorizationRepository(dbpool)
+	sessionRepository := repository.NewPostgresSessionRepository(dbpool)
+	emailChangeRepository := repository.NewPostgresEmailChangeRepository(dbpool)
+	dataExportRepository := repository.NewPostgresDataExportRepository(dbpool)
+	auditEventRepository := repository.NewPostgresAuditEventRepository(dbpool)
+	impersonationRepository := repository.NewPostgresImpersonationRepository(dbpool)
+	personalDataRepository := repository.NewPostgresPersonalDataRepository(dbpool)
+	knownDeviceRepository := repository.NewPostgresKnownDeviceRepository(dbpool)
+	loginEventRepository := repository.NewPostgresLoginEventRepository(dbpool)
+	mfaChallengeRepository := repository.NewPostgresMFAChallengeRepository(dbpool)
+	trustedDeviceRepository := repository.NewPostgresTrustedDeviceRepository(dbpool)
+	phoneVerificationRepository := repository.NewPostgresPhoneVerificationRepository(dbpool)
+	phoneCodeSendRepository := repository.NewPostgresPhoneCodeSendRepository(dbpool)
+
+	// Generated data exports are kept in DATA_EXPORT_DIR until they expire
+	dataExportDir := os.Getenv("DATA_EXPORT_DIR")
+	if dataExportDir == "" {
+		dataExportDir = filepath.Join(os.TempDir(), "auth-exports")
+	}
+	dataExportStorage, err := service.NewFileDataExportStorage(dataExportDir)
+	if err != nil {
+		logger.Error("Could not open data export directory", "error", err)
+		os.Exit(1)
+	}
+
+	passwordHashConfig := loadPasswordHashConfig()
+	passwordHasher := service.NewPasswordHasher(passwordHashConfig)
+	breachedPasswords := openBreachedPasswordList(logger)
+	passwordPolicy := service.NewPasswordPolicy(loadPasswordPolicyConfig(passwordHashConfig), breachedPasswords, logger)
+
+	// Users logging in with a breached password are forced to reset it when BREACHED_PASSWORDS_CHECK_ON_LOGIN=true
+	var loginBreachedPasswords usecase.BreachedPasswordChecker
+	if checkOnLogin, _ := strconv.ParseBool(os.Getenv("BREACHED_PASSWORDS_CHECK_ON_LOGIN")); checkOnLogin {
+		loginBreachedPasswords = breachedPasswords
+	}
+
+	// Select the backend that verifies passwords
+	var credentialVerifier usecase.CredentialVerifier = usecase.NewLocalCredentialVerifier(logger, userRepository, passwordHasher, loginBreachedPasswords)
+	if os.Getenv("AUTH_BACKEND") == "ldap" {
+		ldapConfig := loadLDAPConfig()
+		credentialVerifier = service.NewLDAPCredentialVerifier(ldapConfig, service.NewLDAPDialer(ldapConfig), userRepository, logger)
+		logger.Info("Using LDAP authentication backend", "url", ldapConfig.URL)
+	}
+
+	ipLocator := openIPLocator(logger)
+	ipBlocklist := openIPBlocklist(logger)
+
+	cookiePolicy := loadCookiePolicy()
+	sessionPolicy := loadSessionPolicy()
+	rotationPolicy := loadPasswordRotationPolicy()
+	loginRiskPolicy := loadLoginRiskPolicy()
+	phoneThrottlePolicy := loadPhoneThrottlePolicy()
 
 	// Initialize use case
 	sendEmailVerificationLinkUseCase := usecase.NewSendEmailVerificationLinkUseCase(verifyRepository, taskDistributor)
-	registerUserUseCase := usecase.NewRegisterUserUseCase(userRepository, sendEmailVerificationLinkUseCase)
+	registerUserUseCase := usecase.NewRegisterUserUseCase(userRepository, passwordHasher, passwordPolicy, sendEmailVerificationLinkUseCase)
 	//sendVerificationEmail := usecase.NewSendEmailVerificationLinkUseCase(verifyRepository, taskDistributor)
-	loginUseCase := usecase.NewLoginUserUseCase(userRepository, authRepository, rememberRepository)
-	refreshTokenUseCase := usecase.NewRefreshTokenUseCase(userRepository, rememberRepository, authRepository)
-	verifyEmailUseCase := usecase.NewVerifyEmailUseCase(userRepository, verifyRepository, loginUseCase)
+	trackLoginDeviceUseCase := usecase.NewTrackLoginDeviceUseCase(logger, knownDeviceRepository, ipLocator, authRepository, taskDistributor)
+	sendPhoneCodeUseCase := usecase.NewSendPhoneCodeUseCase(phoneCodeSendRepository, taskDistributor, phoneThrottlePolicy)
+	startMFAChallengeUseCase := usecase.NewStartMFAChallengeUseCase(logger, mfaChallengeRepository, authRepository, taskDistributor, sendPhoneCodeUseCase)
+	trustDeviceUseCase := usecase.NewTrustDeviceUseCase(trustedDeviceRepository, authRepository, authRepository, durationFromEnv("TRUSTED_DEVICE_DURATION", 30*24*time.Hour))
+	assessLoginRiskUseCase := usecase.NewAssessLoginRiskUseCase(loginEventRepository, ipLocator, ipBlocklist, startMFAChallengeUseCase, trustDeviceUseCase, loginRiskPolicy)
+	loginUseCase := usecase.NewLoginUserUseCase(userRepository, credentialVerifier, authRepository, rememberRepository, rotationPolicy, trackLoginDeviceUseCase, assessLoginRiskUseCase)
+	createSessionUseCase := usecase.NewCreateSessionUseCase(sessionRepository, userRepository, trackLoginDeviceUseCase, sessionPolicy)
+	loginUserSessionUseCase := usecase.NewLoginUserSessionUseCase(credentialVerifier, authRepository, rotationPolicy, createSessionUseCase, assessLoginRiskUseCase)
+	verifyMFAChallengeUseCase := usecase.NewVerifyMFAChallengeUseCase(userRepository, authRepository, authRepository, rotationPolicy, mfaChallengeRepository, loginEventRepository, loginUseCase, createSessionUseCase, trustDeviceUseCase)
+	updateMFASettingsUseCase := usecase.NewUpdateMFASettingsUseCase(userRepository, trustedDeviceRepository)
+	listTrustedDevicesUseCase := usecase.NewListTrustedDevicesUseCase(trustedDeviceRepository)
+	revokeTrustedDeviceUseCase := usecase.NewRevokeTrustedDeviceUseCase(trustedDeviceRepository)
+	requestPhoneVerificationUseCase := usecase.NewRequestPhoneVerificationUseCase(phoneVerificationRepository, sendPhoneCodeUseCase)
+	verifyPhoneNumberUseCase := usecase.NewVerifyPhoneNumberUseCase(phoneVerificationRepository, userRepository)
+	removePhoneNumberUseCase := usecase.NewRemovePhoneNumberUseCase(userRepository)
+	authenticateSessionUseCase := usecase.NewAuthenticateSessionUseCase(sessionRepository, userRepository, sessionPolicy, rotationPolicy)
+	logoutUseCase := usecase.NewLogoutUseCase(sessionRepository, rememberRepository)
+	reauthenticateUseCase := usecase.NewReauthenticateUseCase(userRepository, credentialVerifier, authRepository, sessionRepository)
+	secureAccountUseCase := usecase.NewSecureAccountUseCase(
+		authRepository,
+		userRepository,
+		rememberRepository,
+		sessionRepository,
+		knownDeviceRepository,
+		trustedDeviceRepository,
+		personalAccessTokenRepository,
+		serviceAccountRepository,
+	)
+	refreshTokenUseCase := usecase.NewRefreshTokenUseCase(userRepository, rememberRepository, authRepository, rotationPolicy)
+	verifyEmailUseCase := usecase.NewVerifyEmailUseCase(userRepository, verifyRepository, loginUseCase, assessLoginRiskUseCase)
 	requestPasswordResetUseCase := usecase.NewRequestPasswordResetUseCase(logger, userRepository, passwordResetRepository, taskDistributor)
-	resetPasswordUseCase := usecase.NewResetPasswordUseCase(userRepository, passwordResetRepository)
+	changePasswordUseCase := usecase.NewChangePasswordUseCase(userRepository, passwordHasher, passwordPolicy, rotationPolicy)
+	resetPasswordUseCase := usecase.NewResetPasswordUseCase(passwordResetRepository, trustedDeviceRepository, changePasswordUseCase)
+	changeExpiredPasswordUseCase := usecase.NewChangeExpiredPasswordUseCase(userRepository, authRepository, changePasswordUseCase, loginUseCase)
+	changeUserPasswordUseCase := usecase.NewChangeUserPasswordUseCase(
+		logger,
+		userRepository,
+		rememberRepository,
+		sessionRepository,
+		trustedDeviceRepository,
+		passwordHasher,
+		changePasswordUseCase,
+		taskDistributor,
+	)
+	requestEmailChangeUseCase := usecase.NewRequestEmailChangeUseCase(userRepository, emailChangeRepository, passwordHasher, taskDistributor)
+	confirmEmailChangeUseCase := usecase.NewConfirmEmailChangeUseCase(emailChangeRepository)
+	revertEmailChangeUseCase := usecase.NewRevertEmailChangeUseCase(
+		userRepository,
+		emailChangeRepository,
+		rememberRepository,
+		sessionRepository,
+		personalAccessTokenRepository,
+		serviceAccountRepository,
+		trustedDeviceRepository,
+	)
+	// Deleted accounts can be restored by logging in until ACCOUNT_DELETION_GRACE_PERIOD has passed
+	deleteAccountUseCase := usecase.NewDeleteAccountUseCase(
+		logger,
+		userRepository,
+		credentialVerifier,
+		taskDistributor,
+		durationFromEnv("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+	)
+	restoreAccountUseCase := usecase.NewRestoreAccountUseCase(logger, userRepository, authRepository, taskDistributor, loginUseCase)
+	purgeDeletedAccountsUseCase := usecase.NewPurgeDeletedAccountsUseCase(logger, userRepository, taskDistributor)
+	requestDataExportUseCase := usecase.NewRequestDataExportUseCase(dataExportRepository, taskDistributor)
+	listDataExportsUseCase := usecase.NewListDataExportsUseCase(dataExportRepository)
+	getDataExportUseCase := usecase.NewGetDataExportUseCase(dataExportRepository)
+	downloadDataExportUseCase := usecase.NewDownloadDataExportUseCase(dataExportRepository, dataExportStorage)
+	// Download links of data exports are valid for DATA_EXPORT_TTL
+	generateDataExportUseCase := usecase.NewGenerateDataExportUseCase(
+		logger,
+		dataExportRepository,
+		personalDataRepository,
+		dataExportStorage,
+		taskDistributor,
+		durationFromEnv("DATA_EXPORT_TTL", 72*time.Hour),
+	)
+	purgeExpiredDataExportsUseCase := usecase.NewPurgeExpiredDataExportsUseCase(logger, dataExportRepository, dataExportStorage)
+	checkPasswordUseCase := usecase.NewCheckPasswordUseCase(passwordPolicy)
 	requestVerificationCodeUseCase := usecase.NewRequestVerificationCodeUseCase(emailVerificationCodeRepository, userRepository, taskDistributor)
 	verifyCodeUseCase := usecase.NewVerifyCodeUseCase(emailVerificationCodeRepository, authRepository)
 	getUserProfileUseCase := usecase.NewGetUserProfileUseCase(userRepository)
-	requestLoginOTPUseCase := usecase.NewRequestLoginOTPUseCase(logger, loginOTPRepository, userRepository, taskDistributor)
-	verifyLoginOTPUseCase := usecase.NewVerifyLoginOTPUseCase(loginOTPRepository, userRepository, loginUseCase)
-	registerUserWithCodeUseCase := usecase.NewRegisterUserWithCodeUseCase(userRepository, verifyCodeUseCase, loginUseCase)
+	updateUserProfileUseCase := usecase.NewUpdateUserProfileUseCase(userRepository)
+	updateUserStatusUseCase := usecase.NewUpdateUserStatusUseCase(logger, userRepository)
+	checkUserStatusUseCase := usecase.NewCheckUserStatusUseCase(userRepository)
+	startImpersonationUseCase := usecase.NewStartImpersonationUseCase(logger, userRepository, impersonationRepository, auditEventRepository, authRepository)
+	auditImpersonatedRequestUseCase := usecase.NewAuditImpersonatedRequestUseCase(userRepository, impersonationRepository, auditEventRepository)
+	endImpersonationUseCase := usecase.NewEndImpersonationUseCase(logger, impersonationRepository, auditEventRepository)
+	requestLoginOTPUseCase := usecase.NewRequestLoginOTPUseCase(logger, loginOTPRepository, userRepository, taskDistributor, sendPhoneCodeUseCase)
+	verifyLoginOTPUseCase := usecase.NewVerifyLoginOTPUseCase(loginOTPRepository, userRepository, authRepository, rotationPolicy, loginUseCase, assessLoginRiskUseCase)
+	registerUserWithCodeUseCase := usecase.NewRegisterUserWithCodeUseCase(userRepository, passwordHasher, passwordPolicy, verifyCodeUseCase, loginUseCase)
+	createPersonalAccessTokenUseCase := usecase.NewCreatePersonalAccessTokenUseCase(personalAccessTokenRepository)
+	listPersonalAccessTokensUseCase := usecase.NewListPersonalAccessTokensUseCase(personalAccessTokenRepository)
+	revokePersonalAccessTokenUseCase := usecase.NewRevokePersonalAccessTokenUseCase(personalAccessTokenRepository)
+	authenticatePersonalAccessTokenUseCase := usecase.NewAuthenticatePersonalAccessTokenUseCase(personalAccessTokenRepository)
+	authenticateAccessTokenUseCase := usecase.NewAuthenticateAccessTokenUseCase(authRepository)
+	createServiceAccountUseCase := usecase.NewCreateServiceAccountUseCase(serviceAccountRepository, userRepository)
+	listServiceAccountsUseCase := usecase.NewListServiceAccountsUseCase(serviceAccountRepository)
+	rotateServiceAccountSecretUseCase := usecase.NewRotateServiceAccountSecretUseCase(serviceAccountRepository)
+	addServiceAccountKeyUseCase := usecase.NewAddServiceAccountKeyUseCase(serviceAccountRepository)
+	checkServiceAccountStatusUseCase := usecase.NewCheckServiceAccountStatusUseCase(serviceAccountRepository, checkUserStatusUseCase)
+	authenticateClientUseCase := usecase.NewAuthenticateClientUseCase(serviceAccountRepository, checkServiceAccountStatusUseCase, oauthAudiences())
+	clientCredentialsGrantUseCase := usecase.NewClientCredentialsGrantUseCase(authenticateClientUseCase, authRepository)
+	requestDeviceAuthorizationUseCase := usecase.NewRequestDeviceAuthorizationUseCase(
+		deviceAuthorizationRepository,
+		deviceVerificationURI(),
+		splitList(os.Getenv("DEVICE_CLIENT_IDS")),
+	)
+	getDeviceAuthorizationUseCase := usecase.NewGetDeviceAuthorizationUseCase(deviceAuthorizationRepository)
+	approveDeviceAuthorizationUseCase := usecase.NewApproveDeviceAuthorizationUseCase(deviceAuthorizationRepository, getDeviceAuthorizationUseCase)
+	deviceCodeGrantUseCase := usecase.NewDeviceCodeGrantUseCase(deviceAuthorizationRepository, rememberRepository, authRepository, checkUserStatusUseCase)
+	tokenExchangeGrantUseCase := usecase.NewTokenExchangeGrantUseCase(
+		authenticateClientUseCase,
+		checkUserStatusUseCase,
+		authRepository,
+		authRepository,
+		// Services tokens can be exchanged for, token exchange is refused for any other audience
+		splitList(os.Getenv("TOKEN_EXCHANGE_AUDIENCES")),
+	)
+	introspectTokenUseCase := usecase.NewIntrospectTokenUseCase(
+		authenticateClientUseCase,
+		authRepository,
+		rememberRepository,
+		personalAccessTokenRepository,
+		impersonationRepository,
+		checkUserStatusUseCase,
+		checkServiceAccountStatusUseCase,
+	)
+	revokeTokenUseCase := usecase.NewRevokeTokenUseCase(authenticateClientUseCase, authRepository, rememberRepository)
+	checkForwardAuthUseCase := usecase.NewCheckForwardAuthUseCase(
+		authenticateAccessTokenUseCase,
+		authenticatePersonalAccessTokenUseCase,
+		authenticateSessionUseCase,
+		rememberRepository,
+		userRepository,
+		rotationPolicy,
+		// Validated credentials are cached for FORWARD_AUTH_CACHE_TTL, "0" disables the cache
+		repository.NewMemoryCache[*usecase.ForwardAuthIdentity](durationFromEnv("FORWARD_AUTH_CACHE_TTL", 30*time.Second)),
+		loadForwardAuthRules(),
+	)
 
 	// Initialize handler
-	userHandler := handler.NewUserHandler(logger, registerUserUseCase, registerUserWithCodeUseCase, getUserProfileUseCase)
+	userHandler := handler.NewUserHandler(
+		logger,
+		registerUserUseCase,
+		registerUserWithCodeUseCase,
+		getUserProfileUseCase,
+		changeUserPasswordUseCase,
+		updateUserProfileUseCase,
+		deleteAccountUseCase,
+	)
 	authHandler := handler.NewAuthHandler(
 		logger,
+		cookiePolicy,
 		loginUseCase,
+		loginUserSessionUseCase,
+		logoutUseCase,
 		refreshTokenUseCase,
 		verifyEmailUseCase,
 		requestPasswordResetUseCase,
 		resetPasswordUseCase,
+		checkPasswordUseCase,
+		changeExpiredPasswordUseCase,
+		restoreAccountUseCase,
 		requestVerificationCodeUseCase,
 		verifyCodeUseCase,
 		requestLoginOTPUseCase,
 		verifyLoginOTPUseCase,
+		reauthenticateUseCase,
+		secureAccountUseCase,
+		verifyMFAChallengeUseCase,
+	)
+	mfaHandler := handler.NewMFAHandler(
+		logger,
+		updateMFASettingsUseCase,
+		listTrustedDevicesUseCase,
+		revokeTrustedDeviceUseCase,
+	)
+	phoneHandler := handler.NewPhoneHandler(
+		logger,
+		requestPhoneVerificationUseCase,
+		verifyPhoneNumberUseCase,
+		removePhoneNumberUseCase,
+	)
+	personalAccessTokenHandler := handler.NewPersonalAccessTokenHandler(
+		logger,
+		createPersonalAccessTokenUseCase,
+		listPersonalAccessTokensUseCase,
+		revokePersonalAccessTokenUseCase,
+	)
+	emailChangeHandler := handler.NewEmailChangeHandler(
+		logger,
+		requestEmailChangeUseCase,
+		confirmEmailChangeUseCase,
+		revertEmailChangeUseCase,
+	)
+	adminHandler := handler.NewAdminHandler(
+		logger,
+		getUserProfileUseCase,
+		updateUserStatusUseCase,
+		startImpersonationUseCase,
+		endImpersonationUseCase,
+	)
+	dataExportHandler := handler.NewDataExportHandler(
+		logger,
+		requestDataExportUseCase,
+		listDataExportsUseCase,
+		getDataExportUseCase,
+		downloadDataExportUseCase,
+	)
+	serviceAccountHandler := handler.NewServiceAccountHandler(
+		logger,
+		createServiceAccountUseCase,
+		listServiceAccountsUseCase,
+		rotateServiceAccountSecretUseCase,
+		addServiceAccountKeyUseCase,
+	)
+	oauthHandler := handler.NewOAuthHandler(
+		logger,
+		clientCredentialsGrantUseCase,
+		requestDeviceAuthorizationUseCase,
+		getDeviceAuthorizationUseCase,
+		approveDeviceAuthorizationUseCase,
+		deviceCodeGrantUseCase,
+		tokenExchangeGrantUseCase,
+		introspectTokenUseCase,
+		revokeTokenUseCase,
+	)
+	clientInfoMiddleware := handler.NewClientInfoMiddleware(cookiePolicy)
+	forwardAuthHandler := handler.NewForwardAuthHandler(logger, cookiePolicy, checkForwardAuthUseCase, os.Getenv("FORWARD_AUTH_LOGIN_URL"))
+	csrfMiddleware := handler.NewCSRFMiddleware(
+		cookiePolicy,
+		csrfSecret(),
+		append([]string{os.Getenv("BASE_URL")}, splitList(os.Getenv("CSRF_TRUSTED_ORIGINS"))...),
+	)
+	authMiddleware := handler.NewAuthMiddleware(
+		logger,
+		cookiePolicy,
+		authenticateAccessTokenUseCase,
+		authenticatePersonalAccessTokenUseCase,
+		authenticateSessionUseCase,
+		checkUserStatusUseCase,
+		checkServiceAccountStatusUseCase,
+		auditImpersonatedRequestUseCase,
 	)
-	authMiddleware := handler.AuthMiddleware
 
 	// Start task processor
-	taskProcessor := worker.NewRedisTaskProcessor(asynqServer, emailSender, logger)
+	taskProcessor := worker.NewRedisTaskProcessor(
+		asynqServer,
+		emailSender,
+		smsSender,
+		purgeDeletedAccountsUseCase,
+		generateDataExportUseCase,
+		purgeExpiredDataExportsUseCase,
+		logger,
+	)
 	go func() {
 		err := taskProcessor.Start()
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -172,14 +464,30 @@ func main() {
 		}
 	}()
 
+	// Start task scheduler for periodic tasks, without it deleted accounts and expired exports are never purged
+	taskScheduler := worker.NewRedisTaskScheduler(asynqScheduler, logger)
+	if err := taskScheduler.Start(); err != nil {
+		logger.Error("Failed to start task scheduler", "error", err)
+		os.Exit(1)
+	}
+
 	// Setup router and middleware
 	router := chi.NewRouter()
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 
+	// Bodies are small JSON documents, MAX_REQUEST_BODY_BYTES (default 1 MiB) keeps large ones from tying the server up
+	maxBodyBytes := int64(1 << 20)
+	if limit, err := strconv.ParseInt(os.Getenv("MAX_REQUEST_BODY_BYTES"), 10, 64); err == nil && limit > 0 {
+		maxBodyBytes = limit
+	}
+	router.Use(handler.LimitRequestBody(maxBodyBytes))
+
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{os.Getenv("BASE_URL")},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Remember-Token", handler.CSRFHeaderName, handler.DeviceIDHeaderName, handler.TrustedDeviceHeaderName},
+		ExposedHeaders:   []string{handler.DeviceIDHeaderName},
 		AllowCredentials: true,
 	}))
 
@@ -190,6 +498,9 @@ func main() {
 		}
 	})
 
+	// Sensitive actions require the user to have authenticated within REAUTH_MAX_AGE
+	requireFreshAuth := handler.RequireFreshAuth(durationFromEnv("REAUTH_MAX_AGE", 10*time.Minute))
+
 	// API v1 routes
 	router.Route("/api/v1", func(api chi.Router) {
 		// Swagger documentation
@@ -197,26 +508,129 @@ func main() {
 
 		// Auth routes
 		api.Route("/auth", func(auth chi.Router) {
-			auth.Post("/", authHandler.LoginUser)
-			auth.Post("/refresh", authHandler.RefreshToken)
-			auth.Get("/verify-email", authHandler.VerifyEmail)
+			// Routes signing the user in record the device, see also user registration
+			signIn := auth.With(clientInfoMiddleware.Handle)
+			signIn.Post("/", authHandler.LoginUser)
+			auth.Get("/csrf", csrfMiddleware.IssueToken)
+
+			// Cookie-authenticated routes
+			auth.With(csrfMiddleware.Protect).Post("/refresh", authHandler.RefreshToken)
+			auth.With(csrfMiddleware.Protect).Post("/logout", authHandler.Logout)
+			auth.With(csrfMiddleware.Protect, authMiddleware.Handle).Post("/reauthenticate", authHandler.Reauthenticate)
+			signIn.Get("/verify-email", authHandler.VerifyEmail)
 			auth.Post("/request-code", authHandler.RequestVerificationCode)
 			auth.Post("/verify-code", authHandler.VerifyCode)
 			auth.Post("/password/request-reset", authHandler.RequestPasswordReset)
 			auth.Post("/password/reset", authHandler.ResetPassword)
+			auth.Post("/password/check", authHandler.CheckPassword)
+			signIn.Post("/password/change", authHandler.ChangeExpiredPassword)
+			signIn.Post("/account/restore", authHandler.RestoreAccount)
+			signIn.Post("/mfa/verify", authHandler.VerifyMFA)
 			auth.Post("/otp/request", authHandler.RequestLoginOTP)
+			signIn.Post("/otp/verify", authHandler.VerifyLoginOTP)
+			auth.Get("/email/confirm", emailChangeHandler.ConfirmEmailChange)
+			auth.Get("/email/revert", emailChangeHandler.RevertEmailChangePage)
+			auth.Post("/email/revert", emailChangeHandler.RevertEmailChange)
+			auth.Get("/secure-account", authHandler.SecureAccountPage)
+			auth.Post("/secure-account", authHandler.SecureAccount)
+
+			// Reverse proxies forward the method of the original request
+			auth.HandleFunc("/check", forwardAuthHandler.Check)
 		})
 
 		// User routes
 		api.Route("/users", func(user chi.Router) {
-			user.Post("/", userHandler.RegisterUser)
+			// Registering with a code signs the user in
+			user.With(clientInfoMiddleware.Handle).Post("/", userHandler.RegisterUser)
+			user.Get("/exports/download", dataExportHandler.DownloadDataExport)
 
 			// Protected routes
 			user.Group(func(user chi.Router) {
-				user.Use(authMiddleware)
-				user.Get("/me", userHandler.GetUserProfile)
+				user.Use(csrfMiddleware.Protect)
+				user.Use(authMiddleware.Handle)
+				user.With(handler.RequireScope(domain.ScopeProfileRead)).Get("/me", userHandler.GetUserProfile)
+				user.With(handler.RequireScope(domain.ScopeProfileWrite)).Patch("/me", userHandler.UpdateUserProfile)
+
+				// Administrators impersonating the user can't change how the account is secured
+				user.Group(func(security chi.Router) {
+					security.Use(handler.DenyImpersonation)
+					security.Use(requireFreshAuth)
+					security.With(handler.RequireScope(domain.ScopeProfileWrite)).Delete("/me", userHandler.DeleteAccount)
+					security.With(handler.RequireScope(domain.ScopeProfileWrite)).Post("/me/password", userHandler.ChangePassword)
+					security.With(handler.RequireScope(domain.ScopeProfileWrite)).Post("/me/email", emailChangeHandler.RequestEmailChange)
+					security.With(handler.RequireScope(domain.ScopeProfileWrite)).Put("/me/mfa", mfaHandler.UpdateMFASettings)
+					security.With(handler.RequireScope(domain.ScopeProfileWrite)).Post("/me/phone", phoneHandler.RequestPhoneVerification)
+					security.With(handler.RequireScope(domain.ScopeProfileWrite)).Post("/me/phone/verify", phoneHandler.VerifyPhoneNumber)
+					security.With(handler.RequireScope(domain.ScopeProfileWrite)).Delete("/me/phone", phoneHandler.RemovePhoneNumber)
+				})
+
+				// Revoking a trusted device only makes logins stricter, so it needs no fresh authentication
+				user.Route("/me/trusted-devices", func(devices chi.Router) {
+					devices.Use(handler.DenyImpersonation)
+					devices.With(handler.RequireScope(domain.ScopeProfileRead)).Get("/", mfaHandler.ListTrustedDevices)
+					devices.With(handler.RequireScope(domain.ScopeProfileWrite)).Delete("/{id}", mfaHandler.RevokeTrustedDevice)
+				})
+
+				// Requesting an export starts a job and emails the user, so it needs the write scope
+				user.Route("/me/exports", func(exports chi.Router) {
+					exports.With(handler.RequireScope(domain.ScopeProfileWrite)).Post("/", dataExportHandler.RequestDataExport)
+					exports.With(handler.RequireScope(domain.ScopeProfileRead)).Get("/", dataExportHandler.ListDataExports)
+					exports.With(handler.RequireScope(domain.ScopeProfileRead)).Get("/{id}", dataExportHandler.GetDataExport)
+				})
+
+				// Personal access tokens can't be used to mint or manage other tokens
+				user.Route("/me/tokens", func(tokens chi.Router) {
+					tokens.Use(handler.DenyImpersonation)
+					tokens.Use(handler.RequireScope(domain.ScopeTokensWrite))
+					tokens.With(requireFreshAuth).Post("/", personalAccessTokenHandler.CreatePersonalAccessToken)
+					tokens.Get("/", personalAccessTokenHandler.ListPersonalAccessTokens)
+					tokens.Delete("/{id}", personalAccessTokenHandler.RevokePersonalAccessToken)
+				})
+			})
+		})
+
+		// Administration routes
+		api.Route("/admin", func(admin chi.Router) {
+			admin.Use(csrfMiddleware.Protect)
+			admin.Use(authMiddleware.Handle)
+			// Administration is interactive only, a scoped credential is never enough
+			admin.Use(handler.RequireScope(domain.ScopeAll))
+			admin.Use(handler.RequireRole(domain.RoleAdmin))
+			admin.Get("/users/{id}", adminHandler.GetUser)
+			admin.With(requireFreshAuth).Put("/users/{id}/status", adminHandler.UpdateUserStatus)
+			admin.With(requireFreshAuth).Post("/impersonations", adminHandler.StartImpersonation)
+			admin.Delete("/impersonations/{id}", adminHandler.EndImpersonation)
+		})
+
+		// OAuth 2.0 routes
+		api.Route("/oauth", func(oauth chi.Router) {
+			oauth.Post("/token", oauthHandler.Token)
+			oauth.Post("/device_authorization", oauthHandler.DeviceAuthorization)
+			oauth.Post("/introspect", oauthHandler.Introspect)
+			oauth.Post("/revoke", oauthHandler.Revoke)
+
+			// Devices are approved from an interactive session, not with a scoped credential
+			oauth.Group(func(device chi.Router) {
+				device.Use(csrfMiddleware.Protect)
+				device.Use(authMiddleware.Handle)
+				device.Use(handler.DenyImpersonation)
+				device.Use(handler.RequireScope(domain.ScopeTokensWrite))
+				device.Get("/device", oauthHandler.GetDeviceAuthorization)
+				device.Post("/device", oauthHandler.ApproveDeviceAuthorization)
 			})
 		})
+
+		// Service account management routes, only available to users
+		api.Route("/service-accounts", func(accounts chi.Router) {
+			accounts.Use(csrfMiddleware.Protect)
+			accounts.Use(authMiddleware.Handle)
+			accounts.Use(handler.DenyImpersonation)
+			accounts.Use(handler.RequireScope(domain.ScopeTokensWrite))
+			accounts.With(requireFreshAuth).Post("/", serviceAccountHandler.CreateServiceAccount)
+			accounts.Get("/", serviceAccountHandler.ListServiceAccounts)
+			accounts.With(requireFreshAuth).Post("/{id}/secrets", serviceAccountHandler.RotateServiceAccountSecret)
+			accounts.With(requireFreshAuth).Post("/{id}/keys", serviceAccountHandler.AddServiceAccountKey)
+		})
 	})
 
 	// Set up the server
@@ -229,6 +643,22 @@ func main() {
 		ReadHeaderTimeout: 3 * time.Second,   // Max time to read request headers
 	}
 
+	// Expvar metrics are served on a separate, internal-only address since they include the command line
+	if metricsAddr := os.Getenv("METRICS_ADDR"); metricsAddr != "" {
+		metricsServer := &http.Server{
+			Addr:              metricsAddr,
+			Handler:           expvar.Handler(),
+			ReadHeaderTimeout: 3 * time.Second,
+		}
+
+		go func() {
+			logger.Info("Starting the metrics server on " + metricsAddr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Failed to start metrics server", "error", err)
+			}
+		}()
+	}
+
 	go func() {
 		logger.Info("Starting the server on port :" + os.Getenv("PORT"))
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -252,6 +682,7 @@ func main() {
 
 	logger.Info("HTTP server is shutting down")
 
+	taskScheduler.Shutdown()
 	taskProcessor.Shutdown()
 	logger.Info("Task processor shut down")
 }
@@ -274,3 +705,397 @@ func connectToDb() (*pgxpool.Pool, error) {
 	}
 	return pool, nil
 }
+
+// loadLDAPConfig reads the LDAP backend configuration from the environment.
+// LDAP_GROUP_ROLES maps groups to roles as "group:role,role;group:role", e.g.
+// "cn=admins,ou=groups,dc=example,dc=com:admin;developers:developer".
+func loadLDAPConfig() service.LDAPConfig {
+	groupRoles := make(map[string][]string)
+	for _, mapping := range strings.Split(os.Getenv("LDAP_GROUP_ROLES"), ";") {
+		separator := strings.LastIndex(mapping, ":")
+		if separator <= 0 {
+			continue
+		}
+
+		group := strings.TrimSpace(mapping[:separator])
+		for _, role := range strings.Split(mapping[separator+1:], ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				groupRoles[group] = append(groupRoles[group], role)
+			}
+		}
+	}
+
+	return service.LDAPConfig{
+		URL:                os.Getenv("LDAP_URL"),
+		StartTLS:           os.Getenv("LDAP_START_TLS") == "true",
+		InsecureSkipVerify: os.Getenv("LDAP_INSECURE_SKIP_VERIFY") == "true",
+		BindDN:             os.Getenv("LDAP_BIND_DN"),
+		BindPassword:       os.Getenv("LDAP_BIND_PASSWORD"),
+		BaseDN:             os.Getenv("LDAP_BASE_DN"),
+		UserFilter:         os.Getenv("LDAP_USER_FILTER"),
+		EmailAttribute:     os.Getenv("LDAP_EMAIL_ATTRIBUTE"),
+		NameAttribute:      os.Getenv("LDAP_NAME_ATTRIBUTE"),
+		GroupAttribute:     os.Getenv("LDAP_GROUP_ATTRIBUTE"),
+		GroupBaseDN:        os.Getenv("LDAP_GROUP_BASE_DN"),
+		GroupFilter:        os.Getenv("LDAP_GROUP_FILTER"),
+		GroupRoles:         groupRoles,
+		DefaultRoles:       splitList(os.Getenv("LDAP_DEFAULT_ROLES")),
+	}
+}
+
+// oauthAudiences returns the audiences client assertions may be addressed to: the issuer and its token endpoint.
+// OAUTH_ISSUER defaults to BASE_URL, the service refuses to start without either.
+func oauthAudiences() []string {
+	issuer := strings.TrimSuffix(os.Getenv("OAUTH_ISSUER"), "/")
+	if issuer == "" {
+		issuer = strings.TrimSuffix(os.Getenv("BASE_URL"), "/")
+	}
+	if issuer == "" {
+		log.Fatal("OAUTH_ISSUER or BASE_URL must be set")
+	}
+
+	return []string{issuer, issuer + "/api/v1/oauth/token"}
+}
+
+// deviceVerificationURI returns the page where users enter device user codes.
+// DEVICE_VERIFICATION_URI defaults to the /device page of BASE_URL.
+func deviceVerificationURI() string {
+	if uri := os.Getenv("DEVICE_VERIFICATION_URI"); uri != "" {
+		return uri
+	}
+
+	return strings.TrimSuffix(os.Getenv("BASE_URL"), "/") + "/device"
+}
+
+// splitList splits a comma separated environment value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// loadForwardAuthRules reads the forward auth access rules from the environment.
+// FORWARD_AUTH_RULES lists rules as "host/path=role,role;host=role", e.g.
+// "admin.example.com=admin;*.internal.example.com/metrics=ops,admin". The first matching rule applies.
+func loadForwardAuthRules() []domain.AccessRule {
+	var rules []domain.AccessRule
+	for _, definition := range strings.Split(os.Getenv("FORWARD_AUTH_RULES"), ";") {
+		destination, roles, _ := strings.Cut(definition, "=")
+		destination = strings.TrimSpace(destination)
+		if destination == "" {
+			continue
+		}
+
+		host, path, hasPath := strings.Cut(destination, "/")
+		rule := domain.AccessRule{Host: host, Roles: splitList(roles)}
+		if hasPath {
+			rule.PathPrefix = "/" + path
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// loadCookiePolicy reads the attributes of the remember and session cookies from the environment.
+// COOKIE_SECURE defaults to true and COOKIE_SAMESITE to "lax", COOKIE_DOMAIN is empty unless set.
+func loadCookiePolicy() handler.CookiePolicy {
+	policy := handler.DefaultCookiePolicy()
+	policy.Domain = os.Getenv("COOKIE_DOMAIN")
+
+	if os.Getenv("COOKIE_SECURE") == "false" {
+		policy.Secure = false
+	}
+
+	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
+	case "strict":
+		policy.SameSite = http.SameSiteStrictMode
+	case "none":
+		// Browsers reject SameSite=None cookies that aren't secure
+		policy.SameSite = http.SameSiteNoneMode
+		policy.Secure = true
+	}
+
+	return policy
+}
+
+// loadSessionPolicy reads the session timeouts from the environment.
+// SESSION_IDLE_TIMEOUT defaults to 2h, SESSION_ABSOLUTE_TIMEOUT to 24h and SESSION_REMEMBER_ME_TIMEOUT to 30 days.
+func loadSessionPolicy() usecase.SessionPolicy {
+	return usecase.SessionPolicy{
+		IdleTimeout:       durationFromEnv("SESSION_IDLE_TIMEOUT", 2*time.Hour),
+		AbsoluteTimeout:   durationFromEnv("SESSION_ABSOLUTE_TIMEOUT", 24*time.Hour),
+		RememberMeTimeout: durationFromEnv("SESSION_REMEMBER_ME_TIMEOUT", 30*24*time.Hour),
+	}
+}
+
+// loadPasswordRotationPolicy reads the password reuse and expiry policy from the environment.
+// PASSWORD_HISTORY is the number of recent passwords that can't be reused, larger values are clamped to 25,
+// PASSWORD_MAX_AGE is a duration such as "2160h" after which passwords must be changed. Both are disabled by default.
+func loadPasswordRotationPolicy() usecase.PasswordRotationPolicy {
+	policy := usecase.PasswordRotationPolicy{MaxAge: durationFromEnv("PASSWORD_MAX_AGE", 0)}
+
+	if history, err := strconv.Atoi(os.Getenv("PASSWORD_HISTORY")); err == nil {
+		policy.History = min(max(history, 0), usecase.MaxPasswordHistory)
+	}
+
+	return policy
+}
+
+// loadLoginRiskPolicy reads the login risk policy from the environment.
+// LOGIN_RISK_MFA_SCORE (default 30) and LOGIN_RISK_BLOCK_SCORE (default 90) map scores to decisions, 0 disables the band.
+// IMPOSSIBLE_TRAVEL_SPEED_KMH defaults to 1000, LOGIN_VELOCITY_LIMIT to 10 logins per LOGIN_VELOCITY_WINDOW of 1h.
+func loadLoginRiskPolicy() usecase.LoginRiskPolicy {
+	policy := usecase.DefaultLoginRiskPolicy()
+	policy.VelocityWindow = durationFromEnv("LOGIN_VELOCITY_WINDOW", policy.VelocityWindow)
+
+	if score, err := strconv.Atoi(os.Getenv("LOGIN_RISK_MFA_SCORE")); err == nil {
+		policy.MFAScore = score
+	}
+	if score, err := strconv.Atoi(os.Getenv("LOGIN_RISK_BLOCK_SCORE")); err == nil {
+		policy.BlockScore = score
+	}
+	if limit, err := strconv.Atoi(os.Getenv("LOGIN_VELOCITY_LIMIT")); err == nil {
+		policy.VelocityLimit = limit
+	}
+	if speed, err := strconv.ParseFloat(os.Getenv("IMPOSSIBLE_TRAVEL_SPEED_KMH"), 64); err == nil {
+		policy.MaxTravelSpeedKmh = speed
+	}
+
+	return policy
+}
+
+// loadPhoneThrottlePolicy reads the limits of the codes sent to a phone number from the environment.
+// PHONE_CODE_MIN_INTERVAL defaults to 1m between codes, PHONE_CODE_LIMIT to 5 codes per PHONE_CODE_WINDOW of 1h.
+func loadPhoneThrottlePolicy() usecase.PhoneThrottlePolicy {
+	policy := usecase.DefaultPhoneThrottlePolicy()
+	policy.MinInterval = durationFromEnv("PHONE_CODE_MIN_INTERVAL", policy.MinInterval)
+	policy.Window = durationFromEnv("PHONE_CODE_WINDOW", policy.Window)
+
+	if limit, err := strconv.Atoi(os.Getenv("PHONE_CODE_LIMIT")); err == nil {
+		policy.Limit = limit
+	}
+
+	return policy
+}
+
+// durationFromEnv parses a duration such as "30s" from the environment, falling back when unset or invalid
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return duration
+}
+
+// minSecretKeyLength is the shortest SECRET_KEY accepted, 32 bytes matches the output of HMAC-SHA256
+const minSecretKeyLength = 32
+
+// secretKey returns SECRET_KEY, which signs every token issued by the service.
+// The service refuses to start without one, a guessable key would let anyone forge tokens.
+func secretKey() string {
+	secret := os.Getenv("SECRET_KEY")
+	if len(secret) < minSecretKeyLength {
+		log.Fatalf("SECRET_KEY must be set to at least %d characters", minSecretKeyLength)
+	}
+
+	return secret
+}
+
+// csrfSecret returns the key CSRF tokens are signed with, CSRF_SECRET or else SECRET_KEY.
+// Without either, a random key is used and tokens don't survive restarts.
+func csrfSecret() []byte {
+	if secret := os.Getenv("CSRF_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+
+	if secret := os.Getenv("SECRET_KEY"); secret != "" {
+		return []byte(secret)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal("Failed to generate csrf secret: ", err)
+	}
+
+	return secret
+}
+
+// loadPasswordHashConfig reads the password hashing parameters from the environment.
+// PASSWORD_HASH_ALGORITHM is "argon2id" (default) or "bcrypt". ARGON2_MEMORY_KIB, ARGON2_ITERATIONS,
+// ARGON2_PARALLELISM and BCRYPT_COST override the defaults, PASSWORD_PEPPER enables the pepper.
+// Changing any of them upgrades existing hashes the next time their owner logs in.
+// Startup fails on values that can't hash passwords.
+func loadPasswordHashConfig() service.PasswordHashConfig {
+	config := service.DefaultPasswordHashConfig()
+	config.Pepper = os.Getenv("PASSWORD_PEPPER")
+
+	if algorithm := os.Getenv("PASSWORD_HASH_ALGORITHM"); algorithm != "" {
+		config.Algorithm = algorithm
+	}
+
+	config.Memory = uint32(uintFromEnv("ARGON2_MEMORY_KIB", uint64(config.Memory), 32))
+	config.Iterations = uint32(uintFromEnv("ARGON2_ITERATIONS", uint64(config.Iterations), 32))
+	config.Parallelism = uint8(uintFromEnv("ARGON2_PARALLELISM", uint64(config.Parallelism), 8))
+	config.BcryptCost = int(uintFromEnv("BCRYPT_COST", uint64(config.BcryptCost), 8))
+
+	if err := config.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	return config
+}
+
+// uintFromEnv reads an unsigned integer of bitSize bits from the environment, it fails startup on invalid values
+func uintFromEnv(key string, fallback uint64, bitSize int) uint64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+
+	value, err := strconv.ParseUint(raw, 10, bitSize)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, raw, err)
+	}
+
+	return value
+}
+
+// loadPasswordPolicyConfig reads the password policy from the environment.
+// PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH and PASSWORD_MIN_SCORE (0-4) override the defaults,
+// PASSWORD_REQUIRE is a comma-separated list of "upper", "lower", "digit" and "symbol"
+// and PASSWORD_ALLOW_USER_INPUTS=true stops rejecting passwords containing the user's name or email.
+func loadPasswordPolicyConfig(hashConfig service.PasswordHashConfig) service.PasswordPolicyConfig {
+	config := service.DefaultPasswordPolicyConfig()
+
+	if minLength, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil {
+		config.MinLength = minLength
+	}
+	if maxLength, err := strconv.Atoi(os.Getenv("PASSWORD_MAX_LENGTH")); err == nil {
+		config.MaxLength = maxLength
+	}
+	if minScore, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_SCORE")); err == nil {
+		config.MinScore = minScore
+	}
+	if allow, err := strconv.ParseBool(os.Getenv("PASSWORD_ALLOW_USER_INPUTS")); err == nil {
+		config.BlockUserInputs = !allow
+	}
+
+	for _, class := range splitList(os.Getenv("PASSWORD_REQUIRE")) {
+		switch strings.ToLower(class) {
+		case "upper":
+			config.RequireUppercase = true
+		case "lower":
+			config.RequireLowercase = true
+		case "digit":
+			config.RequireDigit = true
+		case "symbol":
+			config.RequireSymbol = true
+		}
+	}
+
+	// Bcrypt hashes the password as is, so it must fit in its 72-byte input
+	if hashConfig.Algorithm == service.PasswordHashBcrypt {
+		config.MaxBytes = service.BcryptMaxPasswordBytes
+	}
+
+	return config
+}
+
+// openIPLocator opens the MaxMind format database at GEOIP_DATABASE_FILE, e.g. GeoLite2-City.mmdb.
+// Without one, locations are left out of login notifications and login risk scores.
+func openIPLocator(logger *slog.Logger) usecase.IPLocator {
+	path := os.Getenv("GEOIP_DATABASE_FILE")
+	if path == "" {
+		return nil
+	}
+
+	locator, err := service.OpenMaxMindIPLocator(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Info("Loaded GeoIP database", "file", path)
+	return locator
+}
+
+// openSMSSender selects the provider of texts and voice calls with SMS_PROVIDER, which must be set since phone
+// verification and phone login codes are always available.
+// "twilio" uses TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM, TWILIO_API_URL points it to a compatible API.
+// "log" writes messages to SMS_LOG_FILE, or to the log, which is only fit for development.
+func openSMSSender(logger *slog.Logger) usecase.SMSSender {
+	switch provider := os.Getenv("SMS_PROVIDER"); provider {
+	case "twilio":
+		config := service.TwilioConfig{
+			BaseURL:    os.Getenv("TWILIO_API_URL"),
+			AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+			AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+			From:       os.Getenv("TWILIO_FROM"),
+		}
+		if config.AccountSID == "" || config.AuthToken == "" || config.From == "" {
+			log.Fatal("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM are required with SMS_PROVIDER=twilio")
+		}
+
+		logger.Info("Using Twilio SMS provider", "from", config.From)
+		return service.NewTwilioSMSSender(config)
+	case "log":
+		path := os.Getenv("SMS_LOG_FILE")
+		logger.Warn("Phone codes are only logged, SMS_PROVIDER=log is not fit for production", "file", path)
+		return service.NewLogSMSSender(logger, path)
+	default:
+		log.Fatalf("SMS_PROVIDER must be \"twilio\" or \"log\", got %q", provider)
+		return nil
+	}
+}
+
+// openIPBlocklist loads the CIDR ranges listed in BAD_IP_RANGES_FILE, logins from them are scored as known bad.
+func openIPBlocklist(logger *slog.Logger) usecase.IPBlocklist {
+	path := os.Getenv("BAD_IP_RANGES_FILE")
+	if path == "" {
+		return nil
+	}
+
+	list, err := service.LoadIPBlocklist(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Info("Loaded IP blocklist", "file", path, "ranges", list.Len())
+	return list
+}
+
+// openBreachedPasswordList opens the HIBP style hash list at BREACHED_PASSWORDS_FILE, if any.
+// With BREACHED_PASSWORDS_COMPACT=true a sorted text file is converted once to "<file>.bin" and that copy is used.
+// It returns nil, disabling the screening, when no list is configured.
+func openBreachedPasswordList(logger *slog.Logger) usecase.BreachedPasswordChecker {
+	path := os.Getenv("BREACHED_PASSWORDS_FILE")
+	if path == "" {
+		return nil
+	}
+
+	if compact, _ := strconv.ParseBool(os.Getenv("BREACHED_PASSWORDS_COMPACT")); compact && !strings.HasSuffix(path, ".bin") {
+		compactPath := path + ".bin"
+		if _, err := os.Stat(compactPath); errors.Is(err, os.ErrNotExist) {
+			logger.Info("Compacting breached password file, this may take a while", "file", path)
+			if err := service.CompactBreachedPasswordFile(path, compactPath); err != nil {
+				log.Fatal("Failed to compact breached password file: ", err)
+			}
+		}
+		path = compactPath
+	}
+
+	list, err := service.OpenBreachedPasswordList(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Info("Loaded breached password list", "file", path)
+	return list
+}